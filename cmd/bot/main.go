@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
+	"net/url"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
@@ -12,10 +14,17 @@ import (
 	"github.com/telegram-llm-bot/internal/bot"
 	"github.com/telegram-llm-bot/internal/config"
 	"github.com/telegram-llm-bot/internal/embeddings"
+	"github.com/telegram-llm-bot/internal/health"
+	"github.com/telegram-llm-bot/internal/httpserver"
+	"github.com/telegram-llm-bot/internal/images"
+	"github.com/telegram-llm-bot/internal/jobs"
 	"github.com/telegram-llm-bot/internal/llm"
+	"github.com/telegram-llm-bot/internal/metrics"
+	"github.com/telegram-llm-bot/internal/models"
 	"github.com/telegram-llm-bot/internal/rag"
 	"github.com/telegram-llm-bot/internal/ratelimit"
 	"github.com/telegram-llm-bot/internal/scheduler"
+	"github.com/telegram-llm-bot/internal/settings"
 	"github.com/telegram-llm-bot/internal/storage"
 	"github.com/telegram-llm-bot/internal/summary"
 )
@@ -67,19 +76,16 @@ func main() {
 		}
 	}()
 
-	// Initialize rate limiter
-	logger.Info().Msg("Initializing rate limiter...")
-	limiter, err := ratelimit.NewLimiter(
-		storageClient,
-		cfg.Timezone,
-		cfg.ProDailyLimit,
-		cfg.FlashDailyLimit,
-		logger,
-	)
+	// Initialize chat and user settings
+	settingsService, err := settings.NewService(storageClient, cfg, logger)
 	if err != nil {
-		logger.Fatal().Err(err).Msg("Failed to create rate limiter")
+		logger.Fatal().Err(err).Msg("Failed to create settings service")
 	}
 
+	// Initialize rate limiter
+	logger.Info().Msg("Initializing rate limiter...")
+	limiter := ratelimit.NewLimiter(storageClient, settingsService, cfg, logger)
+
 	// Initialize embeddings client for RAG
 	logger.Info().Msg("Initializing embeddings client...")
 	embeddingsClient := embeddings.NewClient(
@@ -111,7 +117,7 @@ func main() {
 
 	// Initialize bot
 	logger.Info().Msg("Initializing Telegram bot...")
-	telegramBot, err := bot.New(cfg, storageClient, llmClient, ragSearcher, limiter, logger)
+	telegramBot, err := bot.New(cfg, storageClient, llmClient, ragSearcher, limiter, settingsService, logger)
 	if err != nil {
 		logger.Fatal().Err(err).Msg("Failed to create bot")
 	}
@@ -121,6 +127,14 @@ func main() {
 		Interface("allowed_chat_ids", cfg.AllowedChatIDs).
 		Msg("Bot initialized successfully")
 
+	// Initialize durable job queue for mentions and /draw
+	var jobRunner *jobs.Runner
+	if cfg.JobsEnabled {
+		logger.Info().Msg("Initializing job queue...")
+		jobRunner = jobs.NewRunner(storageClient, cfg, logger)
+		telegramBot.SetJobRunner(jobRunner)
+	}
+
 	// Initialize summary generator
 	logger.Info().Msg("Initializing summary generator...")
 	summaryGenerator := summary.NewGenerator(cfg.GeminiAPIKey, cfg, logger)
@@ -130,6 +144,18 @@ func main() {
 		}
 	}()
 
+	// Record token usage of background Gemini calls (summaries, embeddings)
+	recordUsage := func(ctx context.Context, record *models.UsageRecord) {
+		if err := storageClient.RecordTokenUsage(ctx, record); err != nil {
+			logger.Error().
+				Err(err).
+				Str("component", record.Component).
+				Msg("Failed to record token usage")
+		}
+	}
+	summaryGenerator.SetUsageCallback(recordUsage)
+	embeddingsClient.SetUsageCallback(recordUsage)
+
 	// Initialize sync job for RAG
 	logger.Info().Msg("Initializing sync job...")
 	syncJob := scheduler.NewSyncJob(
@@ -142,26 +168,42 @@ func main() {
 
 	// Initialize scheduler for daily summaries and RAG sync
 	logger.Info().Msg("Initializing scheduler...")
-	summaryScheduler, err := scheduler.NewScheduler(
+	summaryScheduler := scheduler.NewScheduler(
 		storageClient,
 		summaryGenerator,
 		cfg,
-		telegramBot.SendDailySummary,
+		telegramBot.DeliverSummary,
 		syncJob,
+		settingsService,
 		logger,
 	)
-	if err != nil {
-		logger.Fatal().Err(err).Msg("Failed to create scheduler")
-	}
+
+	// Let /schedule manage per-chat schedules
+	telegramBot.SetChatScheduler(summaryScheduler)
+
+	// Generate /draw images with the configured models
+	telegramBot.SetImageGenerator(images.NewGenerator(cfg, logger))
+
+	// Let /summarytemplate preview and validate summary templates
+	telegramBot.SetSummaryRenderer(summaryScheduler)
+
+	// Send activity charts of weekly and monthly digests
+	summaryScheduler.SetChartCallback(telegramBot.SendChart)
 
 	// Set up callback for manual summary generation via /summary command
-	telegramBot.SetSummaryCallback(func(chatID int64) error {
+	telegramBot.SetSummaryCallback(func(ctx context.Context, chatID int64, period string) error {
+		if period != "" {
+			return summaryScheduler.GenerateDigest(ctx, chatID, period)
+		}
 		return summaryScheduler.GenerateSummaryForYesterday(ctx, chatID)
 	})
 
+	// Set up callback for on-demand summaries of ranges via /summary <period>
+	telegramBot.SetRangeSummaryCallback(summaryScheduler.SummarizeRange)
+
 	// Set up callback for manual RAG sync via /sync command
-	telegramBot.SetSyncCallback(func() error {
-		return syncJob.Run(context.Background())
+	telegramBot.SetSyncCallback(func(ctx context.Context) error {
+		return syncJob.Run(ctx)
 	})
 
 	// Setup signal handling for graceful shutdown
@@ -176,6 +218,13 @@ func main() {
 		}
 	}()
 
+	// Start job runner in a goroutine, it resumes jobs left from previous runs
+	if jobRunner != nil {
+		go func() {
+			_ = jobRunner.Start(ctx)
+		}()
+	}
+
 	// Start scheduler in a goroutine
 	schedulerErrChan := make(chan error, 1)
 	go func() {
@@ -184,6 +233,59 @@ func main() {
 		}
 	}()
 
+	// Start HTTP servers for operational endpoints and the Telegram webhook
+	webhookMode := cfg.TelegramMode == models.TelegramModeWebhook
+	var httpServers []*httpserver.Server
+	var opsServer *httpserver.Server
+
+	if cfg.MetricsEnabled || cfg.HealthEnabled || (webhookMode && cfg.WebhookListenAddr == cfg.HTTPAddr) {
+		opsServer = httpserver.New(cfg.HTTPAddr, logger)
+		httpServers = append(httpServers, opsServer)
+
+		if cfg.MetricsEnabled {
+			opsServer.Handle("/metrics", metrics.Handler())
+		}
+
+		if cfg.HealthEnabled {
+			healthRegistry := health.NewRegistry()
+			healthRegistry.AddLiveness("telegram", telegramBot.HealthCheck)
+			healthRegistry.AddReadiness("supabase", storageClient.HealthCheck)
+			healthRegistry.AddReadiness("gemini", llmClient.HealthCheck)
+			healthRegistry.AddReadiness("scheduler", summaryScheduler.HealthCheck)
+
+			opsServer.Handle("/healthz", healthRegistry.LivenessHandler())
+			opsServer.Handle("/readyz", healthRegistry.ReadinessHandler())
+		}
+	}
+
+	if webhookMode {
+		// Serve the webhook on the operational server if both share an address
+		webhookServer := opsServer
+		if cfg.WebhookListenAddr != cfg.HTTPAddr {
+			webhookServer = httpserver.New(cfg.WebhookListenAddr, logger)
+			if cfg.WebhookTLSCert != "" {
+				webhookServer.EnableTLS(cfg.WebhookTLSCert, cfg.WebhookTLSKey)
+			}
+			httpServers = append(httpServers, webhookServer)
+		}
+
+		webhookURL, _ := url.Parse(cfg.WebhookURL) // Validated in config
+		webhookPath := webhookURL.Path
+		if webhookPath == "" {
+			webhookPath = "/"
+		}
+		webhookServer.Handle(webhookPath, telegramBot.WebhookHandler())
+	}
+
+	httpErrChan := make(chan error, len(httpServers))
+	for _, server := range httpServers {
+		go func(server *httpserver.Server) {
+			if err := server.Start(ctx); err != nil {
+				httpErrChan <- err
+			}
+		}(server)
+	}
+
 	logger.Info().Msg("Bot and scheduler are running. Press Ctrl+C to stop.")
 
 	// Wait for termination signal or errors
@@ -194,28 +296,39 @@ func main() {
 		logger.Error().Err(err).Msg("Bot stopped with error")
 	case err := <-schedulerErrChan:
 		logger.Error().Err(err).Msg("Scheduler stopped with error")
+	case err := <-httpErrChan:
+		logger.Error().Err(err).Msg("HTTP server stopped with error")
 	}
 
-	// Graceful shutdown
-	logger.Info().Msg("Initiating graceful shutdown...")
+	// Graceful shutdown: cancelling the root context stops intake (updates, webhook, job claiming,
+	// scheduler), then in-flight requests get SHUTDOWN_TIMEOUT to finish with their own context
+	logger.Info().
+		Int("timeout_seconds", cfg.ShutdownTimeout).
+		Msg("Initiating graceful shutdown...")
 	cancel()
 
-	// Give the bot some time to finish processing
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), time.Duration(cfg.ShutdownTimeout)*time.Second)
 	defer shutdownCancel()
 
-	// Create a channel to signal shutdown complete
-	done := make(chan struct{})
+	// Drain the bot and the job queue in parallel
+	var shutdownWg sync.WaitGroup
+	shutdownWg.Add(1)
 	go func() {
-		telegramBot.Stop() // This will wait for WaitGroup internally
-		close(done)
+		defer shutdownWg.Done()
+		telegramBot.Shutdown(shutdownCtx)
 	}()
+	if jobRunner != nil {
+		shutdownWg.Add(1)
+		go func() {
+			defer shutdownWg.Done()
+			jobRunner.Shutdown(shutdownCtx)
+		}()
+	}
+	shutdownWg.Wait()
 
-	// Wait for shutdown or timeout
-	select {
-	case <-shutdownCtx.Done():
-		logger.Warn().Msg("Shutdown timeout exceeded, some requests may be lost")
-	case <-done:
+	if shutdownCtx.Err() != nil {
+		logger.Warn().Msg("Shutdown timeout exceeded, unfinished requests were dropped or requeued")
+	} else {
 		logger.Info().Msg("Graceful shutdown completed")
 	}
 
@@ -476,3 +476,1037 @@ END;
 $$ LANGUAGE plpgsql;
 
 COMMENT ON FUNCTION record_image_generation IS 'Records an image generation for a user, incrementing their daily counter';
+
+-- =============================================================================
+-- QUOTA TIERS
+-- =============================================================================
+
+-- Table: quota_tiers
+-- Named sets of daily limits. NULL limit means "use the global value from env"
+CREATE TABLE IF NOT EXISTS quota_tiers (
+    name TEXT PRIMARY KEY,                      -- Tier name used in admin commands
+    description TEXT,                           -- Human-readable description
+    pro_daily_limit INTEGER,                    -- Daily Pro requests (NULL = PRO_DAILY_LIMIT)
+    flash_daily_limit INTEGER,                  -- Daily Flash requests (NULL = FLASH_DAILY_LIMIT)
+    image_daily_limit INTEGER,                  -- Daily image generations (NULL = IMAGE_GENERATION_DAILY_LIMIT_PER_USER)
+    daily_token_budget INTEGER,                 -- Daily token budget (NULL = TOKEN_DAILY_BUDGET_PER_USER, 0 = unlimited)
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Migration for databases created before token budgets were introduced
+ALTER TABLE quota_tiers ADD COLUMN IF NOT EXISTS daily_token_budget INTEGER;
+
+-- Built-in tiers
+INSERT INTO quota_tiers (name, description, pro_daily_limit, flash_daily_limit, image_daily_limit)
+VALUES
+    ('default', 'Global limits from configuration', NULL, NULL, NULL),
+    ('trusted', 'Active members with extended limits', 15, 75, 40),
+    ('admin', 'Bot administrators', 1000, 1000, 1000),
+    ('banned', 'No access to AI requests', 0, 0, 0)
+ON CONFLICT (name) DO NOTHING;
+
+-- Table: quota_assignments
+-- Binds tiers to users and chats
+--   chat_id = 0, user_id = X  -> user X in all chats
+--   chat_id = C, user_id = X  -> user X in chat C (per-chat override)
+--   chat_id = C, user_id = 0  -> default tier for everyone in chat C
+CREATE TABLE IF NOT EXISTS quota_assignments (
+    id BIGSERIAL PRIMARY KEY,
+    chat_id BIGINT NOT NULL DEFAULT 0,          -- Telegram Chat ID (0 = all chats)
+    user_id BIGINT NOT NULL DEFAULT 0,          -- Telegram User ID (0 = whole chat)
+    tier TEXT NOT NULL REFERENCES quota_tiers(name) ON UPDATE CASCADE,
+    assigned_by BIGINT,                         -- Admin who made the assignment
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+
+    CONSTRAINT unique_quota_assignment UNIQUE(chat_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_quota_assignments_user_id ON quota_assignments(user_id);
+
+COMMENT ON TABLE quota_tiers IS 'Named sets of daily request limits (NULL limit = global default)';
+COMMENT ON TABLE quota_assignments IS 'Tier assignments per user, per chat and per user in chat';
+
+-- Function: Resolve the effective tier for a user in a chat
+-- Priority: user in chat > user in all chats > chat default > default tier
+DROP FUNCTION IF EXISTS get_effective_quota_tier(BIGINT, BIGINT);
+CREATE OR REPLACE FUNCTION get_effective_quota_tier(
+    p_user_id BIGINT,
+    p_chat_id BIGINT
+)
+RETURNS TABLE(
+    tier_name TEXT,
+    source TEXT,
+    description TEXT,
+    pro_daily_limit INTEGER,
+    flash_daily_limit INTEGER,
+    image_daily_limit INTEGER,
+    daily_token_budget INTEGER
+) AS $$
+BEGIN
+    RETURN QUERY
+    SELECT
+        qt.name,
+        CASE
+            WHEN qa.chat_id = p_chat_id AND qa.user_id = p_user_id THEN 'user_chat'
+            WHEN qa.chat_id = 0 AND qa.user_id = p_user_id THEN 'user_global'
+            ELSE 'chat'
+        END,
+        qt.description,
+        qt.pro_daily_limit,
+        qt.flash_daily_limit,
+        qt.image_daily_limit,
+        qt.daily_token_budget
+    FROM quota_assignments qa
+    JOIN quota_tiers qt ON qt.name = qa.tier
+    WHERE (qa.chat_id = p_chat_id AND qa.user_id = p_user_id)
+       OR (qa.chat_id = 0 AND qa.user_id = p_user_id)
+       OR (qa.chat_id = p_chat_id AND qa.user_id = 0)
+    ORDER BY
+        CASE
+            WHEN qa.chat_id = p_chat_id AND qa.user_id = p_user_id THEN 1
+            WHEN qa.chat_id = 0 AND qa.user_id = p_user_id THEN 2
+            ELSE 3
+        END
+    LIMIT 1;
+
+    -- No assignment: fall back to the default tier
+    IF NOT FOUND THEN
+        RETURN QUERY
+        SELECT qt.name, 'default'::TEXT, qt.description, qt.pro_daily_limit, qt.flash_daily_limit, qt.image_daily_limit, qt.daily_token_budget
+        FROM quota_tiers qt
+        WHERE qt.name = 'default';
+    END IF;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON FUNCTION get_effective_quota_tier IS 'Resolves the quota tier that applies to a user in a chat';
+
+-- =============================================================================
+-- TOKEN ACCOUNTING
+-- =============================================================================
+
+-- Token usage and cost of user requests
+ALTER TABLE request_logs ADD COLUMN IF NOT EXISTS prompt_tokens INTEGER DEFAULT 0;
+ALTER TABLE request_logs ADD COLUMN IF NOT EXISTS candidates_tokens INTEGER DEFAULT 0;
+ALTER TABLE request_logs ADD COLUMN IF NOT EXISTS total_tokens INTEGER DEFAULT 0;
+ALTER TABLE request_logs ADD COLUMN IF NOT EXISTS cost_usd NUMERIC(12, 6) DEFAULT 0;
+
+COMMENT ON COLUMN request_logs.prompt_tokens IS 'Input tokens reported by Gemini usage metadata';
+COMMENT ON COLUMN request_logs.candidates_tokens IS 'Output tokens reported by Gemini usage metadata';
+COMMENT ON COLUMN request_logs.cost_usd IS 'Estimated request cost in USD based on model prices';
+
+-- Daily token counter for per-user token budgets
+ALTER TABLE daily_limits ADD COLUMN IF NOT EXISTS tokens_used BIGINT DEFAULT 0;
+
+COMMENT ON COLUMN daily_limits.tokens_used IS 'Tokens consumed by user requests during the day';
+
+-- Table: token_usage
+-- Token usage of background Gemini calls (summaries, embeddings)
+CREATE TABLE IF NOT EXISTS token_usage (
+    id BIGSERIAL PRIMARY KEY,
+    component TEXT NOT NULL,                    -- 'summary' or 'embeddings'
+    model TEXT NOT NULL,                        -- Gemini model name
+    chat_id BIGINT,                             -- Telegram Chat ID (0 if not bound to a chat)
+    user_id BIGINT,                             -- Telegram User ID (0 for background jobs)
+    prompt_tokens INTEGER DEFAULT 0,
+    candidates_tokens INTEGER DEFAULT 0,
+    total_tokens INTEGER DEFAULT 0,
+    cost_usd NUMERIC(12, 6) DEFAULT 0,
+    estimated BOOLEAN DEFAULT FALSE,            -- TRUE when tokens were estimated from text length
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_token_usage_created_at ON token_usage(created_at);
+CREATE INDEX IF NOT EXISTS idx_token_usage_component ON token_usage(component, created_at);
+
+COMMENT ON TABLE token_usage IS 'Token usage and cost of background Gemini calls';
+
+-- Function: Add consumed tokens to the user's daily counter
+CREATE OR REPLACE FUNCTION add_daily_tokens(
+    p_user_id BIGINT,
+    p_date DATE,
+    p_tokens INTEGER
+)
+RETURNS BIGINT AS $$
+DECLARE
+    v_tokens_used BIGINT;
+BEGIN
+    INSERT INTO daily_limits (user_id, date, tokens_used)
+    VALUES (p_user_id, p_date, p_tokens)
+    ON CONFLICT (user_id, date)
+    DO UPDATE SET
+        tokens_used = COALESCE(daily_limits.tokens_used, 0) + p_tokens,
+        updated_at = NOW()
+    RETURNING tokens_used INTO v_tokens_used;
+
+    RETURN v_tokens_used;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function: Get current daily counts including tokens (replaces the version above)
+DROP FUNCTION IF EXISTS get_daily_limit(BIGINT, DATE);
+CREATE OR REPLACE FUNCTION get_daily_limit(
+    p_user_id BIGINT,
+    p_date DATE
+)
+RETURNS TABLE(pro_count INTEGER, flash_count INTEGER, tokens_used BIGINT) AS $$
+BEGIN
+    RETURN QUERY
+    SELECT
+        COALESCE(dl.pro_requests_count, 0) as pro_count,
+        COALESCE(dl.flash_requests_count, 0) as flash_count,
+        COALESCE(dl.tokens_used, 0)::BIGINT as tokens_used
+    FROM daily_limits dl
+    WHERE dl.user_id = p_user_id AND dl.date = p_date;
+
+    -- If no record found, return zeros
+    IF NOT FOUND THEN
+        RETURN QUERY SELECT 0, 0, 0::BIGINT;
+    END IF;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON FUNCTION add_daily_tokens IS 'Atomically adds tokens to the daily token counter of a user';
+
+-- =============================================================================
+-- CHAT REQUEST POOL
+-- =============================================================================
+
+-- Table: chat_daily_usage
+-- Text requests of each user per chat and day, counted against the chat-wide pool
+CREATE TABLE IF NOT EXISTS chat_daily_usage (
+    id BIGSERIAL PRIMARY KEY,
+    chat_id BIGINT NOT NULL,                    -- Telegram Chat ID
+    user_id BIGINT NOT NULL,                    -- Telegram User ID
+    date DATE NOT NULL,                         -- Date in bot timezone
+    pro_count INTEGER DEFAULT 0,                -- Pro requests made in this chat
+    flash_count INTEGER DEFAULT 0,              -- Flash requests made in this chat
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+
+    CONSTRAINT unique_chat_user_date UNIQUE(chat_id, user_id, date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_daily_usage_chat_date ON chat_daily_usage(chat_id, date);
+
+COMMENT ON TABLE chat_daily_usage IS 'Per-user text requests in a chat, used for the chat-wide pool and fairness share';
+
+-- Function: Count a text request against the chat pool
+CREATE OR REPLACE FUNCTION increment_chat_usage(
+    p_chat_id BIGINT,
+    p_user_id BIGINT,
+    p_date DATE,
+    p_model_type TEXT
+)
+RETURNS TABLE(pro_count INTEGER, flash_count INTEGER) AS $$
+BEGIN
+    INSERT INTO chat_daily_usage (chat_id, user_id, date, pro_count, flash_count)
+    VALUES (
+        p_chat_id,
+        p_user_id,
+        p_date,
+        CASE WHEN p_model_type = 'pro' THEN 1 ELSE 0 END,
+        CASE WHEN p_model_type = 'pro' THEN 0 ELSE 1 END
+    )
+    ON CONFLICT (chat_id, user_id, date)
+    DO UPDATE SET
+        pro_count = chat_daily_usage.pro_count + CASE WHEN p_model_type = 'pro' THEN 1 ELSE 0 END,
+        flash_count = chat_daily_usage.flash_count + CASE WHEN p_model_type = 'pro' THEN 0 ELSE 1 END,
+        updated_at = NOW();
+
+    RETURN QUERY
+    SELECT cdu.pro_count, cdu.flash_count
+    FROM chat_daily_usage cdu
+    WHERE cdu.chat_id = p_chat_id AND cdu.user_id = p_user_id AND cdu.date = p_date;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function: Get per-user usage of the chat pool, most active users first
+CREATE OR REPLACE FUNCTION get_chat_usage(
+    p_chat_id BIGINT,
+    p_date DATE
+)
+RETURNS TABLE(
+    user_id BIGINT,
+    username TEXT,
+    first_name TEXT,
+    pro_count INTEGER,
+    flash_count INTEGER
+) AS $$
+BEGIN
+    RETURN QUERY
+    SELECT
+        cdu.user_id,
+        rl.username,
+        rl.first_name,
+        cdu.pro_count,
+        cdu.flash_count
+    FROM chat_daily_usage cdu
+    LEFT JOIN LATERAL (
+        SELECT r.username, r.first_name
+        FROM request_logs r
+        WHERE r.user_id = cdu.user_id AND r.chat_id = cdu.chat_id
+        ORDER BY r.created_at DESC
+        LIMIT 1
+    ) rl ON TRUE
+    WHERE cdu.chat_id = p_chat_id AND cdu.date = p_date
+    ORDER BY (cdu.pro_count + cdu.flash_count) DESC, cdu.pro_count DESC;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON FUNCTION increment_chat_usage IS 'Atomically counts a text request of a user against the chat pool';
+COMMENT ON FUNCTION get_chat_usage IS 'Returns per-user usage of the chat pool for a date';
+
+-- =============================================================================
+-- USAGE ANALYTICS
+-- =============================================================================
+
+-- Index for per-chat analytics over request_logs
+CREATE INDEX IF NOT EXISTS idx_request_logs_chat_created ON request_logs(chat_id, created_at);
+
+-- Function: Users with the most bot requests in a chat
+CREATE OR REPLACE FUNCTION analytics_top_askers(
+    p_chat_id BIGINT,
+    p_since TIMESTAMPTZ,
+    p_limit INTEGER DEFAULT 10
+)
+RETURNS TABLE(user_id BIGINT, username TEXT, first_name TEXT, count BIGINT) AS $$
+BEGIN
+    RETURN QUERY
+    SELECT
+        rl.user_id,
+        (ARRAY_AGG(rl.username ORDER BY rl.created_at DESC))[1],
+        (ARRAY_AGG(rl.first_name ORDER BY rl.created_at DESC))[1],
+        COUNT(*)
+    FROM request_logs rl
+    WHERE rl.chat_id = p_chat_id AND rl.created_at >= p_since
+    GROUP BY rl.user_id
+    ORDER BY COUNT(*) DESC
+    LIMIT p_limit;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function: Users with the most messages in a chat
+CREATE OR REPLACE FUNCTION analytics_top_chatters(
+    p_chat_id BIGINT,
+    p_since TIMESTAMPTZ,
+    p_limit INTEGER DEFAULT 10
+)
+RETURNS TABLE(user_id BIGINT, username TEXT, first_name TEXT, count BIGINT) AS $$
+BEGIN
+    RETURN QUERY
+    SELECT
+        cm.user_id,
+        (ARRAY_AGG(cm.username ORDER BY cm.created_at DESC))[1],
+        (ARRAY_AGG(cm.first_name ORDER BY cm.created_at DESC))[1],
+        COUNT(*)
+    FROM chat_messages cm
+    WHERE cm.chat_id = p_chat_id AND cm.created_at >= p_since
+    GROUP BY cm.user_id
+    ORDER BY COUNT(*) DESC
+    LIMIT p_limit;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function: Requests, errors, latency and cost per model in a chat
+CREATE OR REPLACE FUNCTION analytics_model_usage(
+    p_chat_id BIGINT,
+    p_since TIMESTAMPTZ
+)
+RETURNS TABLE(
+    model_used TEXT,
+    requests BIGINT,
+    errors BIGINT,
+    avg_latency_ms DOUBLE PRECISION,
+    total_tokens BIGINT,
+    cost_usd DOUBLE PRECISION
+) AS $$
+BEGIN
+    RETURN QUERY
+    SELECT
+        rl.model_used,
+        COUNT(*),
+        COUNT(*) FILTER (WHERE rl.error_message IS NOT NULL AND rl.error_message <> ''),
+        COALESCE(AVG(rl.execution_time_ms), 0)::DOUBLE PRECISION,
+        COALESCE(SUM(rl.total_tokens), 0)::BIGINT,
+        COALESCE(SUM(rl.cost_usd), 0)::DOUBLE PRECISION
+    FROM request_logs rl
+    WHERE rl.chat_id = p_chat_id AND rl.created_at >= p_since
+    GROUP BY rl.model_used
+    ORDER BY COUNT(*) DESC;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function: Weekly requests, messages and active users for the last N weeks
+-- Weeks start on Monday in the chat's timezone, like the other /usage periods
+DROP FUNCTION IF EXISTS analytics_weekly_trend(BIGINT, INTEGER);
+CREATE OR REPLACE FUNCTION analytics_weekly_trend(
+    p_chat_id BIGINT,
+    p_weeks INTEGER DEFAULT 4,
+    p_timezone TEXT DEFAULT 'Europe/Moscow'
+)
+RETURNS TABLE(week_start TEXT, requests BIGINT, messages BIGINT, active_users BIGINT) AS $$
+BEGIN
+    RETURN QUERY
+    WITH weeks AS (
+        SELECT generate_series(
+            date_trunc('week', NOW() AT TIME ZONE p_timezone) - ((p_weeks - 1) * INTERVAL '1 week'),
+            date_trunc('week', NOW() AT TIME ZONE p_timezone),
+            INTERVAL '1 week'
+        ) AS ws
+    )
+    SELECT
+        TO_CHAR(w.ws, 'YYYY-MM-DD'),
+        (SELECT COUNT(*) FROM request_logs rl
+         WHERE rl.chat_id = p_chat_id
+           AND rl.created_at AT TIME ZONE p_timezone >= w.ws
+           AND rl.created_at AT TIME ZONE p_timezone < w.ws + INTERVAL '1 week'),
+        (SELECT COUNT(*) FROM chat_messages cm
+         WHERE cm.chat_id = p_chat_id
+           AND cm.created_at AT TIME ZONE p_timezone >= w.ws
+           AND cm.created_at AT TIME ZONE p_timezone < w.ws + INTERVAL '1 week'),
+        (SELECT COUNT(DISTINCT cm.user_id) FROM chat_messages cm
+         WHERE cm.chat_id = p_chat_id
+           AND cm.created_at AT TIME ZONE p_timezone >= w.ws
+           AND cm.created_at AT TIME ZONE p_timezone < w.ws + INTERVAL '1 week')
+    FROM weeks w
+    ORDER BY w.ws;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function: Images generated in a chat since a time
+-- Counts image_generations (see IMAGE GENERATIONS below), daily_limits counts images of a user across all chats
+DROP FUNCTION IF EXISTS analytics_image_generations(BIGINT, DATE);
+CREATE OR REPLACE FUNCTION analytics_image_generations(
+    p_chat_id BIGINT,
+    p_since TIMESTAMPTZ
+)
+RETURNS TABLE(count BIGINT) AS $$
+BEGIN
+    RETURN QUERY
+    SELECT COUNT(*)
+    FROM image_generations ig
+    WHERE ig.chat_id = p_chat_id AND ig.created_at >= p_since;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON FUNCTION analytics_top_askers IS 'Top users by bot requests in a chat (/top)';
+COMMENT ON FUNCTION analytics_top_chatters IS 'Top users by chat messages (/top)';
+COMMENT ON FUNCTION analytics_model_usage IS 'Model split, latency, error rate and cost in a chat (/usage)';
+COMMENT ON FUNCTION analytics_weekly_trend IS 'Weekly activity trend of a chat (/usage)';
+COMMENT ON FUNCTION analytics_image_generations IS 'Images generated in a chat (/usage)';
+
+-- ============================================================================
+-- JOB QUEUE
+-- ============================================================================
+
+-- Table: bot_jobs
+-- Durable queue of LLM and image requests, so they survive restarts and redeploys
+-- Workers claim jobs with a lease; jobs of a crashed worker are reclaimed after the lease expires
+CREATE TABLE IF NOT EXISTS bot_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    kind TEXT NOT NULL, -- 'mention', 'draw'
+    chat_id BIGINT NOT NULL,
+    message_id BIGINT, -- Message the request came from, NULL for jobs not created by a message
+    user_id BIGINT NOT NULL,
+    payload JSONB NOT NULL,
+    status TEXT NOT NULL DEFAULT 'queued' CHECK (status IN ('queued', 'running', 'done', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 3,
+    run_after TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    lease_owner TEXT,
+    lease_expires_at TIMESTAMPTZ,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_bot_jobs_claim ON bot_jobs(status, run_after) WHERE status IN ('queued', 'running');
+CREATE INDEX IF NOT EXISTS idx_bot_jobs_completed ON bot_jobs(completed_at) WHERE status IN ('done', 'failed');
+-- Enqueueing is retried on network errors, a retry after a lost response must not add the request twice
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bot_jobs_message ON bot_jobs(chat_id, message_id, kind) WHERE message_id IS NOT NULL;
+
+COMMENT ON TABLE bot_jobs IS 'Durable queue of LLM and image generation requests';
+
+-- Function: Enqueue a job and return its ID
+-- A job already queued for the same message is kept and its ID returned
+CREATE OR REPLACE FUNCTION enqueue_bot_job(
+    p_kind TEXT,
+    p_chat_id BIGINT,
+    p_message_id BIGINT,
+    p_user_id BIGINT,
+    p_payload JSONB,
+    p_max_attempts INTEGER
+)
+RETURNS BIGINT AS $$
+DECLARE
+    v_id BIGINT;
+BEGIN
+    INSERT INTO bot_jobs (kind, chat_id, message_id, user_id, payload, max_attempts)
+    VALUES (p_kind, p_chat_id, p_message_id, p_user_id, p_payload, p_max_attempts)
+    ON CONFLICT (chat_id, message_id, kind) WHERE message_id IS NOT NULL DO NOTHING
+    RETURNING id INTO v_id;
+
+    IF v_id IS NULL THEN
+        SELECT id INTO v_id
+        FROM bot_jobs
+        WHERE chat_id = p_chat_id AND message_id = p_message_id AND kind = p_kind;
+    END IF;
+
+    RETURN v_id;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function: Claim due jobs for a worker
+-- Queued jobs and running jobs with an expired lease are claimed in FIFO order.
+-- SKIP LOCKED lets several bot instances claim concurrently without blocking each other
+CREATE OR REPLACE FUNCTION claim_bot_jobs(
+    p_owner TEXT,
+    p_limit INTEGER,
+    p_lease_seconds INTEGER
+)
+RETURNS SETOF bot_jobs AS $$
+BEGIN
+    -- Jobs whose last attempt was abandoned by a crashed worker are not retried
+    UPDATE bot_jobs
+    SET status = 'failed',
+        last_error = COALESCE(last_error, 'lease expired'),
+        lease_owner = NULL,
+        lease_expires_at = NULL,
+        updated_at = NOW(),
+        completed_at = NOW()
+    WHERE status = 'running'
+      AND lease_expires_at < NOW()
+      AND attempts >= max_attempts;
+
+    RETURN QUERY
+    UPDATE bot_jobs j
+    SET status = 'running',
+        attempts = j.attempts + 1,
+        lease_owner = p_owner,
+        lease_expires_at = NOW() + make_interval(secs => p_lease_seconds),
+        updated_at = NOW()
+    WHERE j.id IN (
+        SELECT c.id FROM bot_jobs c
+        WHERE (c.status = 'queued' AND c.run_after <= NOW())
+           OR (c.status = 'running' AND c.lease_expires_at < NOW())
+        ORDER BY c.id
+        LIMIT p_limit
+        FOR UPDATE SKIP LOCKED
+    )
+    RETURNING j.*;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function: Extend the lease of a running job
+-- Returns false if the lease was lost to another worker
+CREATE OR REPLACE FUNCTION extend_bot_job_lease(
+    p_id BIGINT,
+    p_owner TEXT,
+    p_lease_seconds INTEGER
+)
+RETURNS BOOLEAN AS $$
+BEGIN
+    UPDATE bot_jobs
+    SET lease_expires_at = NOW() + make_interval(secs => p_lease_seconds),
+        updated_at = NOW()
+    WHERE id = p_id AND lease_owner = p_owner AND status = 'running';
+
+    RETURN FOUND;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function: Mark a running job as done
+CREATE OR REPLACE FUNCTION complete_bot_job(
+    p_id BIGINT,
+    p_owner TEXT
+)
+RETURNS BOOLEAN AS $$
+BEGIN
+    UPDATE bot_jobs
+    SET status = 'done',
+        lease_owner = NULL,
+        lease_expires_at = NULL,
+        last_error = NULL,
+        updated_at = NOW(),
+        completed_at = NOW()
+    WHERE id = p_id AND lease_owner = p_owner AND status = 'running';
+
+    RETURN FOUND;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function: Record a failed attempt of a running job
+-- The job is requeued after p_retry_delay_seconds, or marked failed if the delay is NULL
+CREATE OR REPLACE FUNCTION fail_bot_job(
+    p_id BIGINT,
+    p_owner TEXT,
+    p_error TEXT,
+    p_retry_delay_seconds INTEGER DEFAULT NULL
+)
+RETURNS BOOLEAN AS $$
+BEGIN
+    UPDATE bot_jobs
+    SET status = CASE WHEN p_retry_delay_seconds IS NULL THEN 'failed' ELSE 'queued' END,
+        run_after = NOW() + make_interval(secs => COALESCE(p_retry_delay_seconds, 0)),
+        lease_owner = NULL,
+        lease_expires_at = NULL,
+        last_error = p_error,
+        updated_at = NOW(),
+        completed_at = CASE WHEN p_retry_delay_seconds IS NULL THEN NOW() ELSE NULL END
+    WHERE id = p_id AND lease_owner = p_owner AND status = 'running';
+
+    RETURN FOUND;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function: Return an interrupted job to the queue without counting the attempt
+-- Used when a worker shuts down before finishing a job
+CREATE OR REPLACE FUNCTION release_bot_job(
+    p_id BIGINT,
+    p_owner TEXT
+)
+RETURNS BOOLEAN AS $$
+BEGIN
+    UPDATE bot_jobs
+    SET status = 'queued',
+        attempts = GREATEST(attempts - 1, 0),
+        run_after = NOW(),
+        lease_owner = NULL,
+        lease_expires_at = NULL,
+        updated_at = NOW()
+    WHERE id = p_id AND lease_owner = p_owner AND status = 'running';
+
+    RETURN FOUND;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function: Delete finished jobs older than p_keep_days
+CREATE OR REPLACE FUNCTION cleanup_bot_jobs(p_keep_days INTEGER)
+RETURNS INTEGER AS $$
+DECLARE
+    v_deleted INTEGER;
+BEGIN
+    DELETE FROM bot_jobs
+    WHERE status IN ('done', 'failed')
+      AND completed_at < NOW() - make_interval(days => p_keep_days);
+
+    GET DIAGNOSTICS v_deleted = ROW_COUNT;
+    RETURN v_deleted;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON FUNCTION enqueue_bot_job IS 'Add an LLM or image request to the job queue once per message';
+COMMENT ON FUNCTION claim_bot_jobs IS 'Lease due jobs to a worker (FOR UPDATE SKIP LOCKED)';
+COMMENT ON FUNCTION extend_bot_job_lease IS 'Heartbeat of a running job';
+COMMENT ON FUNCTION complete_bot_job IS 'Mark a running job as done';
+COMMENT ON FUNCTION fail_bot_job IS 'Requeue a failed job with backoff or mark it failed';
+COMMENT ON FUNCTION release_bot_job IS 'Return an interrupted job to the queue on shutdown';
+COMMENT ON FUNCTION cleanup_bot_jobs IS 'Delete finished jobs older than N days';
+
+-- ============================================================================
+-- CHAT SCHEDULES
+-- ============================================================================
+
+-- Table: chat_schedules
+-- Per-chat cron schedules of scheduled jobs, overriding the global defaults
+-- A disabled row turns the job off for the chat
+CREATE TABLE IF NOT EXISTS chat_schedules (
+    chat_id BIGINT NOT NULL,
+    job TEXT NOT NULL,                -- 'summary', 'weekly' or 'monthly'
+    cron_expr TEXT NOT NULL DEFAULT '', -- Standard 5-field cron expression or descriptor (@daily)
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_by BIGINT,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (chat_id, job)
+);
+
+COMMENT ON TABLE chat_schedules IS 'Per-chat cron schedules of scheduled jobs (/schedule)';
+
+-- ============================================================================
+-- CHAT AND USER SETTINGS
+-- ============================================================================
+
+-- Table: chat_settings
+-- Per-chat settings. NULL values fall back to the global configuration
+CREATE TABLE IF NOT EXISTS chat_settings (
+    chat_id BIGINT PRIMARY KEY,
+    timezone TEXT,                    -- IANA timezone, e.g. 'Europe/Berlin'
+    updated_by BIGINT,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Table: user_settings
+-- Per-user settings. A user timezone overrides the chat timezone for personal limits
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id BIGINT PRIMARY KEY,
+    timezone TEXT,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE chat_settings IS 'Per-chat settings such as timezone (/timezone)';
+COMMENT ON TABLE user_settings IS 'Per-user settings such as timezone (/mytimezone)';
+
+-- ============================================================================
+-- WEEKLY AND MONTHLY DIGESTS
+-- ============================================================================
+
+-- Table: chat_digests
+-- Weekly and monthly digests built from daily summaries and sampled messages
+CREATE TABLE IF NOT EXISTS chat_digests (
+    id BIGSERIAL PRIMARY KEY,
+    chat_id BIGINT NOT NULL,
+    period TEXT NOT NULL CHECK (period IN ('week', 'month')),
+    period_start DATE NOT NULL,               -- First day of the period (chat timezone)
+    period_end DATE NOT NULL,                 -- Last day of the period, inclusive
+    summary_text TEXT NOT NULL,               -- Formatted digest as posted to the chat
+    topics TEXT[] NOT NULL DEFAULT '{}',      -- Main topics, compared with the next digest for trends
+    message_count INT NOT NULL,
+    active_users INT NOT NULL,
+    new_users INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+
+    CONSTRAINT unique_chat_digest UNIQUE(chat_id, period, period_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_digests_chat_period ON chat_digests(chat_id, period, period_start DESC);
+
+-- Function: Messages and active users per day of a chat, including days without messages
+CREATE OR REPLACE FUNCTION digest_daily_activity(
+    p_chat_id BIGINT,
+    p_start DATE,
+    p_end DATE,
+    p_timezone TEXT
+)
+RETURNS TABLE(date TEXT, messages BIGINT, active_users BIGINT) AS $$
+BEGIN
+    RETURN QUERY
+    WITH days AS (
+        SELECT generate_series(p_start, p_end, INTERVAL '1 day')::DATE AS d
+    ),
+    activity AS (
+        SELECT
+            (cm.created_at AT TIME ZONE p_timezone)::DATE AS d,
+            COUNT(*) AS messages,
+            COUNT(DISTINCT cm.user_id) AS active_users
+        FROM chat_messages cm
+        WHERE cm.chat_id = p_chat_id
+          AND cm.created_at >= (p_start::TIMESTAMP AT TIME ZONE p_timezone)
+          AND cm.created_at < ((p_end + 1)::TIMESTAMP AT TIME ZONE p_timezone)
+        GROUP BY 1
+    )
+    SELECT TO_CHAR(days.d, 'YYYY-MM-DD'), COALESCE(a.messages, 0), COALESCE(a.active_users, 0)
+    FROM days
+    LEFT JOIN activity a ON a.d = days.d
+    ORDER BY days.d;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function: Participants of a period with the time of their first message in the chat
+CREATE OR REPLACE FUNCTION digest_participants(
+    p_chat_id BIGINT,
+    p_since TIMESTAMPTZ,
+    p_until TIMESTAMPTZ
+)
+RETURNS TABLE(user_id BIGINT, username TEXT, first_name TEXT, count BIGINT, first_seen_at TIMESTAMPTZ) AS $$
+BEGIN
+    RETURN QUERY
+    SELECT
+        cm.user_id,
+        (ARRAY_AGG(cm.username ORDER BY cm.created_at DESC))[1],
+        (ARRAY_AGG(cm.first_name ORDER BY cm.created_at DESC))[1],
+        COUNT(*) FILTER (WHERE cm.created_at >= p_since),
+        MIN(cm.created_at)
+    FROM chat_messages cm
+    WHERE cm.chat_id = p_chat_id AND cm.created_at < p_until
+    GROUP BY cm.user_id
+    HAVING COUNT(*) FILTER (WHERE cm.created_at >= p_since) > 0
+    ORDER BY 4 DESC;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function: Random sample of messages of a period in chronological order
+CREATE OR REPLACE FUNCTION sample_chat_messages(
+    p_chat_id BIGINT,
+    p_since TIMESTAMPTZ,
+    p_until TIMESTAMPTZ,
+    p_limit INTEGER
+)
+RETURNS TABLE(
+    id BIGINT,
+    message_id BIGINT,
+    user_id BIGINT,
+    username TEXT,
+    first_name TEXT,
+    chat_id BIGINT,
+    message_text TEXT,
+    created_at TIMESTAMPTZ
+) AS $$
+BEGIN
+    RETURN QUERY
+    SELECT s.id, s.message_id, s.user_id, s.username, s.first_name, s.chat_id, s.message_text, s.created_at
+    FROM (
+        SELECT cm.id, cm.message_id, cm.user_id, cm.username, cm.first_name, cm.chat_id, cm.message_text, cm.created_at
+        FROM chat_messages cm
+        WHERE cm.chat_id = p_chat_id AND cm.created_at >= p_since AND cm.created_at < p_until
+        ORDER BY random()
+        LIMIT p_limit
+    ) s
+    ORDER BY s.created_at;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON TABLE chat_digests IS 'Weekly and monthly chat digests (/summary week|month)';
+COMMENT ON FUNCTION digest_daily_activity IS 'Daily messages and active users of a chat for digest activity charts';
+COMMENT ON FUNCTION digest_participants IS 'Participants of a period with their first message time, to detect new members';
+COMMENT ON FUNCTION sample_chat_messages IS 'Random chronological sample of chat messages for digests';
+
+-- ============================================================================
+-- STRUCTURED SUMMARIES
+-- ============================================================================
+
+-- Topics with descriptions, participants and message IDs as returned by the model
+ALTER TABLE daily_summaries ADD COLUMN IF NOT EXISTS summary_data JSONB;
+
+COMMENT ON COLUMN daily_summaries.summary_data IS 'Structured summary: {"topics": [{"emoji", "title", "description", "participants", "message_ids"}]}';
+
+-- ============================================================================
+-- DAILY SUMMARY REPORT
+-- ============================================================================
+
+-- Replies are counted to find the most replied message of the day
+ALTER TABLE chat_messages ADD COLUMN IF NOT EXISTS reply_to_message_id BIGINT;
+
+-- Sections of the daily summary per chat: {"quote": {"disabled": true}, "top": {"title": "..."}}
+ALTER TABLE chat_settings ADD COLUMN IF NOT EXISTS summary_report JSONB;
+
+COMMENT ON COLUMN chat_messages.reply_to_message_id IS 'Telegram ID of the message this message replies to';
+COMMENT ON COLUMN chat_settings.summary_report IS 'Daily summary sections toggled or renamed with /summaryconfig';
+
+-- ============================================================================
+-- SUMMARY DELIVERY
+-- ============================================================================
+
+-- Existing summaries were sent before delivery was tracked
+ALTER TABLE daily_summaries ADD COLUMN IF NOT EXISTS delivery_status TEXT NOT NULL DEFAULT 'sent'
+    CHECK (delivery_status IN ('pending', 'sent', 'failed'));
+ALTER TABLE daily_summaries ADD COLUMN IF NOT EXISTS delivery_attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE daily_summaries ADD COLUMN IF NOT EXISTS delivery_error TEXT;
+ALTER TABLE daily_summaries ADD COLUMN IF NOT EXISTS sent_message_id BIGINT;
+ALTER TABLE daily_summaries ADD COLUMN IF NOT EXISTS delivered_at TIMESTAMPTZ;
+
+-- The retry job only looks for undelivered summaries
+CREATE INDEX IF NOT EXISTS idx_daily_summaries_undelivered
+    ON daily_summaries(created_at) WHERE delivery_status <> 'sent';
+
+-- Forum topic and pinning of scheduled summaries per chat
+ALTER TABLE chat_settings ADD COLUMN IF NOT EXISTS summary_thread_id BIGINT;
+ALTER TABLE chat_settings ADD COLUMN IF NOT EXISTS summary_pin BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN daily_summaries.delivery_status IS 'pending, sent or failed; failed summaries are retried by the delivery job';
+COMMENT ON COLUMN daily_summaries.delivery_attempts IS 'Number of attempts to send the summary to the chat';
+COMMENT ON COLUMN daily_summaries.delivery_error IS 'Error of the last failed delivery attempt';
+COMMENT ON COLUMN daily_summaries.sent_message_id IS 'Telegram ID of the sent summary message, used to unpin it later';
+COMMENT ON COLUMN daily_summaries.delivered_at IS 'When the summary was sent to the chat';
+COMMENT ON COLUMN chat_settings.summary_thread_id IS 'Forum topic scheduled summaries and digests are posted to, NULL for the general chat';
+COMMENT ON COLUMN chat_settings.summary_pin IS 'Pin daily summaries, unpinning the previous one';
+
+-- ============================================================================
+-- SUMMARY SEARCH
+-- ============================================================================
+
+-- Embedding of the summary topics for /summaries search, NULL until the sync job indexes it
+ALTER TABLE daily_summaries ADD COLUMN IF NOT EXISTS embedding VECTOR(768);
+
+CREATE INDEX IF NOT EXISTS idx_daily_summaries_chat_date ON daily_summaries(chat_id, date DESC);
+CREATE INDEX IF NOT EXISTS idx_daily_summaries_unindexed ON daily_summaries(created_at) WHERE embedding IS NULL;
+
+-- Function: Batch update summary embeddings
+CREATE OR REPLACE FUNCTION batch_update_summary_embeddings(
+    p_summary_ids BIGINT[],
+    p_embeddings VECTOR(768)[]
+)
+RETURNS INT AS $$
+DECLARE
+    rows_updated INT := 0;
+    i INT;
+BEGIN
+    IF array_length(p_summary_ids, 1) != array_length(p_embeddings, 1) THEN
+        RAISE EXCEPTION 'Summary IDs and embeddings arrays must have same length';
+    END IF;
+
+    FOR i IN 1..array_length(p_summary_ids, 1) LOOP
+        UPDATE daily_summaries SET embedding = p_embeddings[i] WHERE id = p_summary_ids[i];
+
+        IF FOUND THEN
+            rows_updated := rows_updated + 1;
+        END IF;
+    END LOOP;
+
+    RETURN rows_updated;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function: Search summaries of a chat using vector similarity
+-- A chat has at most one summary per day, so no vector index is needed
+CREATE OR REPLACE FUNCTION search_similar_summaries(
+    query_embedding VECTOR(768),
+    target_chat_id BIGINT,
+    similarity_threshold FLOAT DEFAULT 0.6,
+    match_count INT DEFAULT 5
+)
+RETURNS TABLE (
+    id BIGINT,
+    chat_id BIGINT,
+    date DATE,
+    summary_text TEXT,
+    summary_data JSONB,
+    message_count INT,
+    similarity FLOAT
+) AS $$
+BEGIN
+    RETURN QUERY
+    SELECT
+        ds.id,
+        ds.chat_id,
+        ds.date,
+        ds.summary_text,
+        ds.summary_data,
+        ds.message_count,
+        1 - (ds.embedding <=> query_embedding) AS similarity
+    FROM daily_summaries ds
+    WHERE
+        ds.chat_id = target_chat_id
+        AND ds.embedding IS NOT NULL
+        AND (1 - (ds.embedding <=> query_embedding)) >= similarity_threshold
+    ORDER BY ds.embedding <=> query_embedding
+    LIMIT match_count;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON COLUMN daily_summaries.embedding IS 'Embedding of the summary topics (text-embedding-004, 768 dimensions) for /summaries search';
+COMMENT ON FUNCTION batch_update_summary_embeddings IS 'Batch updates daily summaries with embeddings (sync job)';
+COMMENT ON FUNCTION search_similar_summaries IS 'Searches daily summaries of a chat using cosine similarity on embeddings';
+
+-- ============================================================================
+-- LOCALES
+-- ============================================================================
+
+-- Language of bot messages and summaries ('ru', 'en'); NULL falls back to DEFAULT_LOCALE
+ALTER TABLE chat_settings ADD COLUMN IF NOT EXISTS locale TEXT;
+-- NULL falls back to the Telegram language of the user, then to the chat locale
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS locale TEXT;
+
+COMMENT ON COLUMN chat_settings.locale IS 'Language of the chat set with /language';
+COMMENT ON COLUMN user_settings.locale IS 'Language of the user set with /mylanguage';
+
+-- ============================================================================
+-- SUMMARY TEMPLATES
+-- ============================================================================
+
+-- Go text/template of the daily summary; NULL uses the default template
+ALTER TABLE chat_settings ADD COLUMN IF NOT EXISTS summary_template TEXT;
+
+COMMENT ON COLUMN chat_settings.summary_template IS 'Daily summary template set with /summarytemplate';
+
+-- ============================================================================
+-- IMAGE GENERATIONS
+-- ============================================================================
+
+-- Function: Record image generations (replaces the version above, /draw --n generates several images at once)
+DROP FUNCTION IF EXISTS record_image_generation(BIGINT, BIGINT, DATE);
+CREATE OR REPLACE FUNCTION record_image_generation(
+    p_user_id BIGINT,
+    p_chat_id BIGINT,
+    p_date DATE,
+    p_count INTEGER DEFAULT 1
+)
+RETURNS BOOLEAN AS $$
+BEGIN
+    INSERT INTO daily_limits (user_id, date, image_generations_used)
+    VALUES (p_user_id, p_date, p_count)
+    ON CONFLICT (user_id, date)
+    DO UPDATE SET
+        image_generations_used = daily_limits.image_generations_used + p_count,
+        updated_at = NOW();
+
+    RETURN TRUE;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON FUNCTION record_image_generation IS 'Records image generations for a user, incrementing their daily counter by the number of images';
+
+-- Table: image_generations
+-- Generated images with the parameters that reproduce them
+CREATE TABLE IF NOT EXISTS image_generations (
+    id BIGSERIAL PRIMARY KEY,
+    chat_id BIGINT NOT NULL,                    -- Telegram Chat ID
+    user_id BIGINT NOT NULL,                    -- Telegram User ID of the requester
+    message_id INTEGER NOT NULL,                -- Telegram message of the sent photo
+    file_id TEXT,                               -- Telegram file ID of the sent photo
+    prompt TEXT NOT NULL,
+    negative_prompt TEXT,                       -- /draw --no, comma-separated
+    aspect_ratio TEXT NOT NULL,                 -- /draw --ar, e.g. '16:9'
+    model TEXT NOT NULL,                        -- Name of the model from IMAGE_MODELS
+    seed BIGINT NOT NULL,                       -- /draw --seed
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_image_generations_message ON image_generations(chat_id, message_id);
+CREATE INDEX IF NOT EXISTS idx_image_generations_user ON image_generations(user_id, created_at);
+
+COMMENT ON TABLE image_generations IS 'Images generated with /draw and the parameters to reproduce them';
+
+-- ============================================================================
+-- IMAGE EDITING
+-- ============================================================================
+
+-- Photo a generation edits or varies (/draw or /vary as a reply to a photo); NULL for images drawn from a prompt
+ALTER TABLE image_generations ADD COLUMN IF NOT EXISTS source_file_id TEXT;
+-- Edits without --ar keep the proportions of the photo and have no aspect ratio
+ALTER TABLE image_generations ALTER COLUMN aspect_ratio DROP NOT NULL;
+
+COMMENT ON COLUMN image_generations.source_file_id IS 'Telegram file ID of the edited or varied photo';
+
+-- ============================================================================
+-- IMAGE PROMPT ENHANCEMENT
+-- ============================================================================
+
+-- Prompt sent to the image model after Flash rewrote it; NULL with --raw, for edits or if enhancement failed
+ALTER TABLE image_generations ADD COLUMN IF NOT EXISTS enhanced_prompt TEXT;
+
+COMMENT ON COLUMN image_generations.enhanced_prompt IS 'Detailed English prompt the /draw description was expanded into';
+COMMENT ON COLUMN token_usage.component IS 'summary, embeddings or image_prompt';
+
+-- ============================================================================
+-- THINKING TOKENS
+-- ============================================================================
+
+-- Thinking tokens of thinking models (total - prompt - candidates), billed at the output price and included in cost_usd
+ALTER TABLE token_usage ADD COLUMN IF NOT EXISTS thoughts_tokens INTEGER DEFAULT 0;
+
+COMMENT ON COLUMN token_usage.thoughts_tokens IS 'Thinking tokens, priced as output tokens';
+
+-- ============================================================================
+-- JOB PAYLOAD UPDATES
+-- ============================================================================
+
+-- Function: Replace the payload of a running job, e.g. with the enhanced prompt of a /draw request,
+-- so retries reuse it instead of computing it again
+CREATE OR REPLACE FUNCTION update_bot_job_payload(
+    p_id BIGINT,
+    p_owner TEXT,
+    p_payload JSONB
+)
+RETURNS BOOLEAN AS $$
+BEGIN
+    UPDATE bot_jobs
+    SET payload = p_payload,
+        updated_at = NOW()
+    WHERE id = p_id AND lease_owner = p_owner AND status = 'running';
+
+    RETURN FOUND;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON FUNCTION update_bot_job_payload IS 'Store results of a job attempt in its payload for retries';
@@ -0,0 +1,225 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/telegram-llm-bot/internal/charts"
+	"github.com/telegram-llm-bot/internal/i18n"
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+const (
+	// analyticsTopLimit is the number of users shown in /top lists
+	analyticsTopLimit = 10
+
+	// analyticsChartKeyword requests a PNG chart in addition to the text report
+	analyticsChartKeyword = "chart"
+)
+
+// analyticsPeriods maps period arguments to their length in days and trend weeks
+// Labels are the "analytics.period.<name>" messages
+var analyticsPeriods = map[string]struct {
+	days  int
+	weeks int
+}{
+	"day":   {days: 1, weeks: 4},
+	"week":  {days: 7, weeks: 4},
+	"month": {days: 30, weeks: 8},
+}
+
+// handleTopCommand handles /top command - shows top askers and most active chatters
+// Usage: /top [day|week|month] [chart]
+func (b *Bot) handleTopCommand(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	tr := b.tr(ctx, message)
+
+	if !b.config.IsAllowedChat(chatID) {
+		b.sendMessage(chatID, tr.T("common.chat_not_allowed"))
+		return
+	}
+
+	period, withChart, err := b.parseAnalyticsArgs(ctx, message.Chat.ID, message.CommandArguments(), tr)
+	if err != nil {
+		b.sendMessage(chatID, tr.T("analytics.top_usage"))
+		return
+	}
+
+	askers, err := b.storage.GetTopAskers(ctx, chatID, period.Since, analyticsTopLimit)
+	if err != nil {
+		b.sendErrorMessage(chatID, tr.T("stats.failed"))
+		return
+	}
+
+	chatters, err := b.storage.GetTopChatters(ctx, chatID, period.Since, analyticsTopLimit)
+	if err != nil {
+		b.sendErrorMessage(chatID, tr.T("stats.failed"))
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(tr.T("analytics.top_title", period.Label))
+	sb.WriteString(tr.T("analytics.top_askers"))
+	writeActivityList(&sb, askers, tr.T("analytics.unit_requests"), tr)
+	sb.WriteString(tr.T("analytics.top_chatters"))
+	writeActivityList(&sb, chatters, tr.T("analytics.unit_messages"), tr)
+
+	b.sendMessage(chatID, sb.String())
+
+	if withChart && len(chatters) > 0 {
+		bars := make([]charts.Bar, 0, len(chatters))
+		for _, c := range chatters {
+			bars = append(bars, charts.Bar{Label: c.DisplayName(), Value: float64(c.Count)})
+		}
+		b.SendChart(chatID, tr.T("analytics.chart_messages", period.Label), bars)
+	}
+}
+
+// handleUsageCommand handles /usage command - shows model split, latency, error rate and weekly trends
+// Usage: /usage [day|week|month] [chart]
+func (b *Bot) handleUsageCommand(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	tr := b.tr(ctx, message)
+
+	if !b.config.IsAllowedChat(chatID) {
+		b.sendMessage(chatID, tr.T("common.chat_not_allowed"))
+		return
+	}
+
+	period, withChart, err := b.parseAnalyticsArgs(ctx, message.Chat.ID, message.CommandArguments(), tr)
+	if err != nil {
+		b.sendMessage(chatID, tr.T("analytics.usage_usage"))
+		return
+	}
+
+	report := &models.UsageReport{Period: *period}
+
+	report.Models, err = b.storage.GetModelUsage(ctx, chatID, period.Since)
+	if err != nil {
+		b.sendErrorMessage(chatID, tr.T("stats.failed"))
+		return
+	}
+
+	report.Weekly, err = b.storage.GetWeeklyTrend(ctx, chatID, period.Weeks, period.Since.Location())
+	if err != nil {
+		b.sendErrorMessage(chatID, tr.T("stats.failed"))
+		return
+	}
+
+	report.ImageGenerations, err = b.storage.GetImageGenerationsSince(ctx, chatID, period.Since)
+	if err != nil {
+		// Image stats are optional, show the rest of the report
+		b.logger.Warn().Err(err).Int64("chat_id", chatID).Msg("Failed to get image generation stats")
+	}
+
+	b.sendMessage(chatID, formatUsageReport(report, tr))
+
+	if withChart && len(report.Weekly) > 0 {
+		bars := make([]charts.Bar, 0, len(report.Weekly))
+		for _, w := range report.Weekly {
+			bars = append(bars, charts.Bar{Label: w.WeekStart, Value: float64(w.Requests)})
+		}
+		b.SendChart(chatID, tr.T("analytics.chart_requests"), bars)
+	}
+}
+
+// parseAnalyticsArgs parses the period and chart flag of analytics commands
+// The period defaults to a week, its label is in the language of tr
+func (b *Bot) parseAnalyticsArgs(ctx context.Context, chatID int64, args string, tr *i18n.Localizer) (*models.AnalyticsPeriod, bool, error) {
+	name := "week"
+	withChart := false
+
+	for _, arg := range strings.Fields(strings.ToLower(args)) {
+		if arg == analyticsChartKeyword {
+			withChart = true
+			continue
+		}
+		if _, ok := analyticsPeriods[arg]; !ok {
+			return nil, false, fmt.Errorf("unknown period: %s", arg)
+		}
+		name = arg
+	}
+
+	loc := b.settings.ChatLocation(ctx, chatID)
+
+	// Periods start at midnight so "day" means "today" in the chat timezone
+	now := time.Now().In(loc)
+	spec := analyticsPeriods[name]
+	since := time.Date(now.Year(), now.Month(), now.Day()-(spec.days-1), 0, 0, 0, 0, loc)
+
+	return &models.AnalyticsPeriod{
+		Name:  name,
+		Label: tr.T("analytics.period." + name),
+		Since: since,
+		Weeks: spec.weeks,
+	}, withChart && b.config.ChartsEnabled, nil
+}
+
+// formatUsageReport formats a usage report as a Telegram message
+func formatUsageReport(report *models.UsageReport, tr *i18n.Localizer) string {
+	var sb strings.Builder
+	sb.WriteString(tr.T("analytics.usage_title", report.Period.Label))
+
+	total := report.TotalRequests()
+	if total == 0 {
+		sb.WriteString(tr.T("analytics.no_requests"))
+	} else {
+		sb.WriteString(tr.T("analytics.total", total))
+		sb.WriteString(tr.T("analytics.latency", report.AvgLatencyMs()/1000))
+		sb.WriteString(tr.T("analytics.errors", report.TotalErrors(), report.ErrorRate()))
+		sb.WriteString(tr.T("analytics.cost", report.TotalCostUSD()))
+
+		sb.WriteString(tr.T("analytics.by_model"))
+		for _, m := range report.Models {
+			share := float64(m.Requests) / float64(total) * 100
+			sb.WriteString(tr.T("analytics.model_line",
+				m.Model, m.Requests, share, m.AvgLatencyMs/1000, m.Errors))
+		}
+	}
+
+	if report.ImageGenerations > 0 {
+		sb.WriteString(tr.T("analytics.images", report.ImageGenerations))
+	}
+
+	if len(report.Weekly) > 0 {
+		sb.WriteString(tr.T("analytics.by_week"))
+		for _, w := range report.Weekly {
+			sb.WriteString(tr.T("analytics.week_line",
+				w.WeekStart, w.Requests, w.Messages, w.ActiveUsers))
+		}
+	}
+
+	return sb.String()
+}
+
+// writeActivityList writes a numbered list of user activity
+func writeActivityList(sb *strings.Builder, entries []models.UserActivity, unit string, tr *i18n.Localizer) {
+	if len(entries) == 0 {
+		sb.WriteString(tr.T("analytics.no_data"))
+		return
+	}
+	for i, entry := range entries {
+		sb.WriteString(fmt.Sprintf("%d. %s — %d %s\n", i+1, entry.DisplayName(), entry.Count, unit))
+	}
+}
+
+// SendChart renders a bar chart and sends it as a photo
+func (b *Bot) SendChart(chatID int64, title string, bars []charts.Bar) {
+	data, err := charts.BarChart(title, bars)
+	if err != nil {
+		b.logger.Error().Err(err).Int64("chat_id", chatID).Msg("Failed to render chart")
+		return
+	}
+
+	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileBytes{
+		Name:  "chart.png",
+		Bytes: data,
+	})
+
+	if _, err := b.api.Send(photo); err != nil {
+		b.logger.Error().Err(err).Int64("chat_id", chatID).Msg("Failed to send chart")
+	}
+}
@@ -2,19 +2,29 @@ package bot
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/rs/zerolog"
+	"github.com/telegram-llm-bot/internal/health"
+	"github.com/telegram-llm-bot/internal/images"
+	"github.com/telegram-llm-bot/internal/jobs"
 	"github.com/telegram-llm-bot/internal/llm"
+	"github.com/telegram-llm-bot/internal/metrics"
 	"github.com/telegram-llm-bot/internal/models"
 	"github.com/telegram-llm-bot/internal/rag"
 	"github.com/telegram-llm-bot/internal/ratelimit"
+	"github.com/telegram-llm-bot/internal/settings"
 	"github.com/telegram-llm-bot/internal/storage"
 )
 
+// abortGracePeriod is how long aborted handlers get to notify users after the shutdown deadline
+const abortGracePeriod = 5 * time.Second
+
 // Bot represents the Telegram bot
 type Bot struct {
 	api             *tgbotapi.BotAPI
@@ -23,10 +33,24 @@ type Bot struct {
 	llmClient       *llm.Client
 	ragSearcher     *rag.Searcher
 	limiter         *ratelimit.Limiter
+	settings        *settings.Service
 	logger          zerolog.Logger
-	wg              sync.WaitGroup // Tracks active handlers for graceful shutdown
-	summaryCallback func(chatID int64) error
-	syncCallback    func() error
+	dispatcher      *dispatcher
+	chatScheduler   ChatScheduler      // Per-chat schedules for /schedule, nil if not set
+	summaryRenderer SummaryRenderer    // Summary templates for /summarytemplate, nil if not set
+	jobRunner       *jobs.Runner       // Durable queue for mentions and /draw, nil if disabled
+	imageGenerator  *images.Generator  // Image models for /draw, nil if not set
+	wg              sync.WaitGroup     // Tracks background work outside of the dispatcher (busy replies, /sync)
+	workCtx         context.Context    // Context of handlers, outlives the intake context during shutdown
+	cancelWork      context.CancelFunc // Aborts in-flight handlers when the shutdown deadline expires
+	running         atomic.Bool        // True while the update loop is running
+	lastUpdate      atomic.Int64       // Unix time of the last received update
+	webhookUpdates  chan tgbotapi.Update
+	stopOnce        sync.Once     // Stopping the update receiver twice panics
+	loopDone        chan struct{} // Closed when the update loop of Start exits
+	summaryCallback func(ctx context.Context, chatID int64, period string) error
+	rangeSummary    RangeSummaryCallback // On-demand summaries of message ranges, nil if not set
+	syncCallback    func(ctx context.Context) error
 }
 
 // New creates a new bot instance
@@ -36,6 +60,7 @@ func New(
 	llmClient *llm.Client,
 	ragSearcher *rag.Searcher,
 	limiter *ratelimit.Limiter,
+	settings *settings.Service,
 	logger zerolog.Logger,
 ) (*Bot, error) {
 	// Create Telegram bot API client
@@ -52,60 +77,212 @@ func New(
 		Int64("id", api.Self.ID).
 		Msg("Telegram bot authorized")
 
-	return &Bot{
-		api:         api,
-		config:      config,
-		storage:     storage,
-		llmClient:   llmClient,
-		ragSearcher: ragSearcher,
-		limiter:     limiter,
-		logger:      logger.With().Str("component", "bot").Logger(),
-	}, nil
+	b := &Bot{
+		api:            api,
+		config:         config,
+		storage:        storage,
+		llmClient:      llmClient,
+		ragSearcher:    ragSearcher,
+		limiter:        limiter,
+		settings:       settings,
+		logger:         logger.With().Str("component", "bot").Logger(),
+		webhookUpdates: make(chan tgbotapi.Update, webhookBufferSize),
+		loopDone:       make(chan struct{}),
+	}
+	b.workCtx, b.cancelWork = context.WithCancel(context.Background())
+	b.dispatcher = newDispatcher(
+		config.WorkerPoolSize,
+		config.WorkerQueueSize,
+		config.WorkerChatQueueSize,
+		b.handleUpdate,
+		b.logger,
+	)
+
+	return b, nil
 }
 
-// Start starts the bot
+// Start starts the bot and receives updates until the context is cancelled
+// Handlers run with a separate context, call Shutdown to drain them
 func (b *Bot) Start(ctx context.Context) error {
-	b.logger.Info().Msg("Starting bot...")
+	defer close(b.loopDone)
 
-	// Configure update settings
-	u := tgbotapi.NewUpdate(0)
-	u.Timeout = 60
+	b.logger.Info().Str("mode", b.config.TelegramMode).Msg("Starting bot...")
 
 	// Get updates channel
-	updates := b.api.GetUpdatesChan(u)
+	updates, err := b.receiveUpdates()
+	if err != nil {
+		return err
+	}
+
+	b.dispatcher.start(b.workCtx)
 
 	b.logger.Info().Msg("Bot started, waiting for messages...")
+	b.running.Store(true)
+	defer b.running.Store(false)
 
 	// Process updates
 	for {
 		select {
 		case <-ctx.Done():
-			b.logger.Info().Msg("Shutting down bot...")
-			b.api.StopReceivingUpdates()
-
-			// Wait for all active handlers to complete
-			b.logger.Info().Msg("Waiting for active handlers to complete...")
-			b.wg.Wait()
-			b.logger.Info().Msg("All handlers completed")
-
+			b.logger.Info().Msg("Stopped receiving updates")
+			b.stopReceivingUpdates()
 			return nil
 
 		case update := <-updates:
-			// Track this handler in WaitGroup
-			b.wg.Add(1)
-			// Process update in a goroutine to not block
-			go func(upd tgbotapi.Update) {
-				defer b.wg.Done()
-				b.handleUpdate(ctx, upd)
-			}(update)
+			b.lastUpdate.Store(time.Now().Unix())
+			b.dispatch(update)
 		}
 	}
 }
 
-// Stop stops the bot
-func (b *Bot) Stop() {
-	b.logger.Info().Msg("Stopping bot...")
-	b.api.StopReceivingUpdates()
+// dispatch queues an update for the worker pool
+// Commands and mentions are rejected with a busy reply when the queues are full.
+// Other updates are always queued, and rejected mentions are still saved, so chat history is saved completely
+func (b *Bot) dispatch(update tgbotapi.Update) {
+	chatID, interactive := b.classifyUpdate(update)
+	if b.dispatcher.submit(chatID, update, !interactive) {
+		return
+	}
+
+	if message := update.Message; message != nil && interactive && !message.IsCommand() && message.From != nil {
+		b.dispatcher.submitFunc(chatID, func() {
+			b.saveChatMessage(b.workCtx, message)
+		})
+	}
+
+	b.logger.Warn().
+		Int64("chat_id", chatID).
+		Int("update_id", update.UpdateID).
+		Msg("Update queue is full, rejecting request")
+	if !interactive {
+		return
+	}
+	metrics.UpdatesRejected.Inc()
+
+	// Reply outside of the receive loop so it isn't blocked by Telegram API calls
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.sendReply(update.Message, b.replyTr(update.Message).T("bot.busy"))
+	}()
+}
+
+// classifyUpdate returns the chat of an update and whether a user waits for a reply to it
+// (commands and mentions)
+func (b *Bot) classifyUpdate(update tgbotapi.Update) (int64, bool) {
+	// Button presses are cheap and always queued in the order of the chat
+	if query := update.CallbackQuery; query != nil && query.Message != nil {
+		return query.Message.Chat.ID, false
+	}
+
+	message := update.Message
+	if message == nil {
+		return 0, false
+	}
+
+	chatID := message.Chat.ID
+	return chatID, message.IsCommand() || (b.config.IsAllowedChat(chatID) && b.isMentioned(message))
+}
+
+// receiveUpdates starts receiving updates by long polling or webhook depending on configuration
+func (b *Bot) receiveUpdates() (<-chan tgbotapi.Update, error) {
+	if b.config.TelegramMode == models.TelegramModeWebhook {
+		if err := b.setWebhook(); err != nil {
+			return nil, err
+		}
+		return b.webhookUpdates, nil
+	}
+
+	// A webhook left from a previous run blocks getUpdates
+	if err := b.deleteWebhook(); err != nil {
+		b.logger.Warn().Err(err).Msg("Failed to delete webhook before polling")
+	}
+
+	// Configure update settings
+	u := tgbotapi.NewUpdate(0)
+	u.Timeout = 60
+
+	return b.api.GetUpdatesChan(u), nil
+}
+
+// stopReceivingUpdates stops long polling or deletes the webhook (only once)
+func (b *Bot) stopReceivingUpdates() {
+	b.stopOnce.Do(func() {
+		if b.config.TelegramMode == models.TelegramModeWebhook {
+			if err := b.deleteWebhook(); err != nil {
+				b.logger.Error().Err(err).Msg("Failed to delete webhook on shutdown")
+			}
+			return
+		}
+
+		b.api.StopReceivingUpdates()
+	})
+}
+
+// HealthCheck reports whether the update loop is running and when the last update arrived
+func (b *Bot) HealthCheck(ctx context.Context) health.Result {
+	details := map[string]interface{}{}
+	if ts := b.lastUpdate.Load(); ts > 0 {
+		lastUpdate := time.Unix(ts, 0).UTC()
+		details["last_update_at"] = lastUpdate.Format(time.RFC3339)
+		details["seconds_since_last_update"] = int(time.Since(lastUpdate).Seconds())
+	}
+
+	if !b.running.Load() {
+		return health.Result{Status: health.StatusFail, Error: "update loop is not running", Details: details}
+	}
+
+	return health.Result{Status: health.StatusOK, Details: details}
+}
+
+// Shutdown stops receiving updates and lets queued and in-flight handlers finish until ctx expires
+// Then in-flight handlers are aborted, and users of unfinished requests are notified
+func (b *Bot) Shutdown(ctx context.Context) {
+	b.logger.Info().Msg("Shutting down bot...")
+	b.stopReceivingUpdates()
+
+	// No updates are dispatched after the loop exits
+	select {
+	case <-b.loopDone:
+	case <-ctx.Done():
+	}
+
+	b.logger.Info().Msg("Waiting for active handlers to complete...")
+	dropped := b.dispatcher.close(ctx)
+
+	background := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(background)
+	}()
+	select {
+	case <-background:
+	case <-ctx.Done():
+	}
+
+	if ctx.Err() != nil {
+		b.logger.Warn().
+			Int("dropped_updates", len(dropped)).
+			Msg("Shutdown deadline exceeded, aborting in-flight handlers")
+	}
+
+	// Abort whatever is still running, handlers notify their users on cancellation
+	b.cancelWork()
+	abortCtx, cancel := context.WithTimeout(context.Background(), abortGracePeriod)
+	defer cancel()
+	b.dispatcher.wait(abortCtx)
+	select {
+	case <-background:
+	case <-abortCtx.Done():
+	}
+
+	for _, update := range dropped {
+		if _, interactive := b.classifyUpdate(update); interactive {
+			b.sendReply(update.Message, b.replyTr(update.Message).T("bot.dropped"))
+		}
+	}
+
+	b.logger.Info().Msg("All handlers completed")
 }
 
 // GetUsername returns bot username
@@ -113,38 +290,97 @@ func (b *Bot) GetUsername() string {
 	return b.api.Self.UserName
 }
 
-// SendDailySummary sends a daily summary message to a chat
-func (b *Bot) SendDailySummary(chatID int64, summaryText string) error {
-	b.logger.Info().
-		Int64("chat_id", chatID).
-		Msg("Sending daily summary")
+// DeliverSummary posts a scheduled summary or digest to a chat and returns the sent message ID
+// The message goes to the forum topic of the delivery if set. If pinning is requested,
+// the previous summary is unpinned and the new one pinned; pin failures are only logged
+func (b *Bot) DeliverSummary(ctx context.Context, delivery *models.SummaryDelivery) (int64, error) {
+	logger := b.logger.With().
+		Int64("chat_id", delivery.ChatID).
+		Int64("thread_id", delivery.ThreadID).
+		Logger()
 
-	msg := tgbotapi.NewMessage(chatID, summaryText)
-	msg.ParseMode = "Markdown"
+	logger.Info().Msg("Sending daily summary")
 
-	_, err := b.api.Send(msg)
+	messageID, err := b.sendToThread(delivery.ChatID, delivery.ThreadID, delivery.Text, "Markdown")
 	if err != nil {
-		b.logger.Error().
-			Err(err).
-			Int64("chat_id", chatID).
-			Msg("Failed to send daily summary")
-		return fmt.Errorf("failed to send daily summary: %w", err)
+		// Model output may break Markdown, the summary is still worth sending as plain text
+		logger.Warn().Err(err).Msg("Failed to send summary with Markdown, sending as plain text")
+		messageID, err = b.sendToThread(delivery.ChatID, delivery.ThreadID, delivery.Text, "")
+	}
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to send daily summary")
+		return 0, fmt.Errorf("failed to send daily summary: %w", err)
 	}
 
-	b.logger.Info().
-		Int64("chat_id", chatID).
+	if delivery.Pin {
+		b.pinSummary(delivery, messageID)
+	}
+
+	logger.Info().
+		Int64("message_id", messageID).
 		Msg("Daily summary sent successfully")
 
-	return nil
+	return messageID, nil
+}
+
+// sendToThread sends a message to a forum topic of the chat, or to the chat itself if threadID is 0
+// The Telegram library has no forum support, so the request is made directly
+func (b *Bot) sendToThread(chatID, threadID int64, text, parseMode string) (int64, error) {
+	params := tgbotapi.Params{}
+	params.AddNonZero64("chat_id", chatID)
+	params.AddNonZero64("message_thread_id", threadID)
+	params.AddNonEmpty("text", text)
+	params.AddNonEmpty("parse_mode", parseMode)
+
+	resp, err := b.api.MakeRequest("sendMessage", params)
+	if err != nil {
+		return 0, err
+	}
+
+	var message tgbotapi.Message
+	if err := json.Unmarshal(resp.Result, &message); err != nil {
+		return 0, fmt.Errorf("failed to parse sent message: %w", err)
+	}
+
+	return int64(message.MessageID), nil
+}
+
+// pinSummary unpins the previous summary and pins the sent one without notifying members
+func (b *Bot) pinSummary(delivery *models.SummaryDelivery, messageID int64) {
+	if delivery.UnpinMessageID != 0 {
+		unpin := tgbotapi.UnpinChatMessageConfig{ChatID: delivery.ChatID, MessageID: int(delivery.UnpinMessageID)}
+		if _, err := b.api.Request(unpin); err != nil {
+			b.logger.Warn().
+				Err(err).
+				Int64("chat_id", delivery.ChatID).
+				Int64("message_id", delivery.UnpinMessageID).
+				Msg("Failed to unpin previous summary")
+		}
+	}
+
+	pin := tgbotapi.PinChatMessageConfig{ChatID: delivery.ChatID, MessageID: int(messageID), DisableNotification: true}
+	if _, err := b.api.Request(pin); err != nil {
+		b.logger.Warn().
+			Err(err).
+			Int64("chat_id", delivery.ChatID).
+			Int64("message_id", messageID).
+			Msg("Failed to pin summary, the bot may lack the pin permission")
+	}
 }
 
 // SetSummaryCallback sets the callback function for manual summary generation
-func (b *Bot) SetSummaryCallback(callback func(chatID int64) error) {
+// The period is empty for yesterday's summary, or a digest period (week, month)
+func (b *Bot) SetSummaryCallback(callback func(ctx context.Context, chatID int64, period string) error) {
 	b.summaryCallback = callback
 }
 
+// SetImageGenerator sets the image generator used by /draw
+func (b *Bot) SetImageGenerator(generator *images.Generator) {
+	b.imageGenerator = generator
+}
+
 // SetSyncCallback sets the callback function for manual RAG sync
-func (b *Bot) SetSyncCallback(callback func() error) {
+func (b *Bot) SetSyncCallback(callback func(ctx context.Context) error) {
 	b.syncCallback = callback
 }
 
@@ -165,6 +401,9 @@ func (b *Bot) saveChatMessage(ctx context.Context, message *tgbotapi.Message) {
 		MessageText: message.Text,
 		CreatedAt:   time.Unix(int64(message.Date), 0).UTC(),
 	}
+	if message.ReplyToMessage != nil {
+		chatMsg.ReplyToMessageID = int64(message.ReplyToMessage.MessageID)
+	}
 
 	// Save to database (non-blocking, log errors but don't fail)
 	if err := b.storage.SaveChatMessage(ctx, chatMsg); err != nil {
@@ -0,0 +1,203 @@
+package bot
+
+import (
+	"context"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog"
+	"github.com/telegram-llm-bot/internal/metrics"
+)
+
+// dispatchItem is a queued update, or a function run in the order of the chat,
+// e.g. a claimed job of the job queue
+type dispatchItem struct {
+	update tgbotapi.Update
+	run    func() // Set instead of update
+}
+
+// dispatcher processes updates with a bounded pool of workers
+// Updates of the same chat are handled one at a time in arrival order,
+// updates of different chats are handled in parallel
+type dispatcher struct {
+	handle       func(ctx context.Context, update tgbotapi.Update)
+	workers      int
+	maxPending   int // Limit of queued updates across all chats
+	maxChatQueue int // Limit of queued updates per chat
+	logger       zerolog.Logger
+	mu           sync.Mutex
+	cond         *sync.Cond
+	queues       map[int64][]dispatchItem // Queued updates by chat
+	scheduled    map[int64]bool           // Chats that are ready or being processed
+	ready        []int64                  // Chats with queued updates waiting for a worker
+	pending      int
+	closed       bool
+	wg           sync.WaitGroup
+}
+
+// newDispatcher creates a dispatcher with the given pool size and queue limits
+func newDispatcher(
+	workers, maxPending, maxChatQueue int,
+	handle func(ctx context.Context, update tgbotapi.Update),
+	logger zerolog.Logger,
+) *dispatcher {
+	d := &dispatcher{
+		handle:       handle,
+		workers:      workers,
+		maxPending:   maxPending,
+		maxChatQueue: maxChatQueue,
+		logger:       logger,
+		queues:       make(map[int64][]dispatchItem),
+		scheduled:    make(map[int64]bool),
+	}
+	d.cond = sync.NewCond(&d.mu)
+	return d
+}
+
+// start launches the workers. They exit after close once all queued updates are handled
+func (d *dispatcher) start(ctx context.Context) {
+	for i := 0; i < d.workers; i++ {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			d.work(ctx)
+		}()
+	}
+
+	d.logger.Info().
+		Int("workers", d.workers).
+		Int("max_pending", d.maxPending).
+		Int("max_chat_queue", d.maxChatQueue).
+		Msg("Update dispatcher started")
+}
+
+// submit queues an update of the chat
+// Returns false if the queue limits are reached, unless force is set.
+// Forced updates are cheap (e.g. saving a plain message) and must not be lost
+func (d *dispatcher) submit(chatID int64, update tgbotapi.Update, force bool) bool {
+	return d.enqueue(chatID, dispatchItem{update: update}, force)
+}
+
+// submitFunc queues a function of the chat behind the updates of the chat received before
+// Functions are always queued, they are claimed jobs or other work that must not be lost.
+// Returns false if the dispatcher is closed
+func (d *dispatcher) submitFunc(chatID int64, run func()) bool {
+	return d.enqueue(chatID, dispatchItem{run: run}, true)
+}
+
+// enqueue adds an item to the queue of the chat and schedules the chat
+func (d *dispatcher) enqueue(chatID int64, item dispatchItem, force bool) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return false
+	}
+	if !force && (d.pending >= d.maxPending || len(d.queues[chatID]) >= d.maxChatQueue) {
+		return false
+	}
+
+	d.queues[chatID] = append(d.queues[chatID], item)
+	d.pending++
+	metrics.DispatcherQueueDepth.Set(float64(d.pending))
+
+	if !d.scheduled[chatID] {
+		d.scheduled[chatID] = true
+		d.ready = append(d.ready, chatID)
+		d.cond.Signal()
+	}
+
+	return true
+}
+
+// work handles updates of ready chats until the dispatcher is closed and drained
+func (d *dispatcher) work(ctx context.Context) {
+	for {
+		d.mu.Lock()
+		for len(d.ready) == 0 && !d.closed {
+			d.cond.Wait()
+		}
+		if len(d.ready) == 0 {
+			d.mu.Unlock()
+			return
+		}
+
+		// Take the next update of the chat. The chat stays scheduled,
+		// so no other worker picks up its updates meanwhile
+		chatID := d.ready[0]
+		d.ready = d.ready[1:]
+		item := d.queues[chatID][0]
+		d.queues[chatID] = d.queues[chatID][1:]
+		d.pending--
+		metrics.DispatcherQueueDepth.Set(float64(d.pending))
+		d.mu.Unlock()
+
+		if item.run != nil {
+			item.run()
+		} else {
+			d.handle(ctx, item.update)
+		}
+
+		d.mu.Lock()
+		if len(d.queues[chatID]) > 0 {
+			// Requeue the chat at the end so busy chats don't starve others
+			d.ready = append(d.ready, chatID)
+			d.cond.Signal()
+		} else {
+			delete(d.queues, chatID)
+			delete(d.scheduled, chatID)
+		}
+		d.mu.Unlock()
+	}
+}
+
+// close stops accepting updates and waits for the workers to handle queued ones
+// If ctx expires first, updates that were not started yet are removed from the queues
+// and returned; workers exit after their current update. Removed functions are started anyway,
+// the job runner is shutting down too and returns interrupted jobs to the queue
+func (d *dispatcher) close(ctx context.Context) []tgbotapi.Update {
+	d.mu.Lock()
+	d.closed = true
+	d.cond.Broadcast()
+	d.mu.Unlock()
+
+	if d.wait(ctx) {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var dropped []tgbotapi.Update
+	for _, queue := range d.queues {
+		for _, item := range queue {
+			if item.run != nil {
+				go item.run()
+			} else {
+				dropped = append(dropped, item.update)
+			}
+		}
+	}
+	d.queues = make(map[int64][]dispatchItem)
+	d.ready = nil
+	d.pending = 0
+	metrics.DispatcherQueueDepth.Set(0)
+
+	return dropped
+}
+
+// wait waits for the workers to exit. Returns false if ctx expired first
+func (d *dispatcher) wait(ctx context.Context) bool {
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
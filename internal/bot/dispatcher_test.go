@@ -0,0 +1,237 @@
+package bot
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog"
+)
+
+func TestDispatcherPerChatOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		workers int
+		chats   int
+		updates int // Updates per chat
+	}{
+		{name: "one worker", workers: 1, chats: 3, updates: 20},
+		{name: "more workers than chats", workers: 8, chats: 3, updates: 50},
+		{name: "more chats than workers", workers: 2, chats: 10, updates: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			handled := make(map[int64][]int)
+			active := make(map[int64]bool)
+			overlap := false
+
+			handle := func(_ context.Context, update tgbotapi.Update) {
+				chatID := update.Message.Chat.ID
+				mu.Lock()
+				if active[chatID] {
+					overlap = true
+				}
+				active[chatID] = true
+				mu.Unlock()
+
+				time.Sleep(100 * time.Microsecond)
+
+				mu.Lock()
+				active[chatID] = false
+				handled[chatID] = append(handled[chatID], update.UpdateID)
+				mu.Unlock()
+			}
+
+			d := newDispatcher(tt.workers, tt.chats*tt.updates, tt.updates, handle, zerolog.Nop())
+			d.start(context.Background())
+
+			// Interleave the chats as updates arrive from Telegram
+			for i := 0; i < tt.updates; i++ {
+				for chat := 1; chat <= tt.chats; chat++ {
+					if !d.submit(int64(chat), testUpdate(int64(chat), i), false) {
+						t.Fatalf("update %d of chat %d rejected", i, chat)
+					}
+				}
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if dropped := d.close(ctx); dropped != nil {
+				t.Fatalf("close dropped %d updates", len(dropped))
+			}
+
+			if overlap {
+				t.Error("updates of the same chat were handled concurrently")
+			}
+			for chat := 1; chat <= tt.chats; chat++ {
+				got := handled[int64(chat)]
+				if len(got) != tt.updates {
+					t.Fatalf("chat %d: handled %d updates, want %d", chat, len(got), tt.updates)
+				}
+				for i, id := range got {
+					if id != i {
+						t.Fatalf("chat %d: updates handled in order %v", chat, got)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDispatcherQueueLimits(t *testing.T) {
+	type submission struct {
+		chatID int64
+		force  bool
+		want   bool
+	}
+
+	tests := []struct {
+		name         string
+		maxPending   int
+		maxChatQueue int
+		submissions  []submission
+	}{
+		{
+			name:         "chat queue limit",
+			maxPending:   10,
+			maxChatQueue: 2,
+			submissions: []submission{
+				{chatID: 1, want: true},
+				{chatID: 1, want: true},
+				{chatID: 1, want: false},
+				{chatID: 2, want: true},
+			},
+		},
+		{
+			name:         "total limit",
+			maxPending:   2,
+			maxChatQueue: 10,
+			submissions: []submission{
+				{chatID: 1, want: true},
+				{chatID: 2, want: true},
+				{chatID: 3, want: false},
+				{chatID: 1, want: false},
+			},
+		},
+		{
+			name:         "forced updates ignore limits",
+			maxPending:   1,
+			maxChatQueue: 1,
+			submissions: []submission{
+				{chatID: 1, want: true},
+				{chatID: 1, force: true, want: true},
+				{chatID: 2, force: true, want: true},
+				{chatID: 2, want: false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Workers are not started, so submitted updates stay queued
+			d := newDispatcher(1, tt.maxPending, tt.maxChatQueue, func(context.Context, tgbotapi.Update) {}, zerolog.Nop())
+
+			for i, s := range tt.submissions {
+				if got := d.submit(s.chatID, testUpdate(s.chatID, i), s.force); got != s.want {
+					t.Errorf("submission %d to chat %d = %v, want %v", i, s.chatID, got, s.want)
+				}
+			}
+		})
+	}
+}
+
+func TestDispatcherJobsKeepChatOrder(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	record := func(item string) {
+		mu.Lock()
+		order = append(order, item)
+		mu.Unlock()
+	}
+
+	handle := func(_ context.Context, update tgbotapi.Update) {
+		record(update.Message.Text)
+	}
+
+	// Jobs are queued even when the chat queue is full
+	d := newDispatcher(4, 10, 1, handle, zerolog.Nop())
+	d.submit(1, testUpdate(1, 1), false)
+	d.submitFunc(1, func() { record("job 1") })
+	d.submit(1, testUpdate(1, 2), true)
+	d.submitFunc(1, func() { record("job 2") })
+	d.start(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	d.close(ctx)
+
+	want := []string{"update 1", "job 1", "update 2", "job 2"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("handled %v, want %v", order, want)
+	}
+
+	if d.submitFunc(1, func() {}) {
+		t.Error("closed dispatcher accepted a job")
+	}
+}
+
+func TestDispatcherCloseDropsQueuedUpdates(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	handle := func(_ context.Context, update tgbotapi.Update) {
+		if update.UpdateID == 0 {
+			close(started)
+			<-release
+		}
+	}
+
+	d := newDispatcher(1, 10, 10, handle, zerolog.Nop())
+	d.start(context.Background())
+	d.submit(1, testUpdate(1, 0), false)
+	<-started
+
+	// Queued behind the blocked update
+	d.submit(1, testUpdate(1, 1), false)
+	d.submit(2, testUpdate(2, 2), false)
+	jobStarted := make(chan struct{})
+	d.submitFunc(2, func() { close(jobStarted) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	dropped := d.close(ctx)
+
+	if len(dropped) != 2 {
+		t.Errorf("close dropped %d updates, want 2", len(dropped))
+	}
+
+	// Dropped jobs are started so the job runner can release them
+	select {
+	case <-jobStarted:
+	case <-time.After(5 * time.Second):
+		t.Error("dropped job was not started")
+	}
+
+	close(release)
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer waitCancel()
+	if !d.wait(waitCtx) {
+		t.Error("workers did not exit")
+	}
+}
+
+// testUpdate returns a message update of the chat
+func testUpdate(chatID int64, id int) tgbotapi.Update {
+	return tgbotapi.Update{
+		UpdateID: id,
+		Message: &tgbotapi.Message{
+			Chat: &tgbotapi.Chat{ID: chatID},
+			Text: "update " + strconv.Itoa(id),
+		},
+	}
+}
@@ -0,0 +1,131 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/telegram-llm-bot/internal/i18n"
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+// maxSourceImageSize limits downloads of photos to edit, Telegram compresses photos well below it
+const maxSourceImageSize = 10 << 20
+
+// replyPhoto returns the file ID of the largest size of the photo a message replies to, empty if there is none
+func replyPhoto(message *tgbotapi.Message) string {
+	if message.ReplyToMessage == nil || len(message.ReplyToMessage.Photo) == 0 {
+		return ""
+	}
+	photo := message.ReplyToMessage.Photo
+	return photo[len(photo)-1].FileID
+}
+
+// editModelError returns the message explaining why a photo can't be edited with the requested model,
+// empty if it can
+func (b *Bot) editModelError(tr *i18n.Localizer, model string) string {
+	editModels := b.imageGenerator.EditModels()
+	if len(editModels) == 0 {
+		return tr.T("draw.edit_not_configured")
+	}
+	if model == "" || b.imageGenerator.CanEdit(model) {
+		return ""
+	}
+
+	names := make([]string, 0, len(editModels))
+	for _, editModel := range editModels {
+		names = append(names, editModel.Name)
+	}
+	if !b.imageGenerator.HasModel(model) {
+		return tr.T("draw.unknown_model", model, strings.Join(names, ", "))
+	}
+	return tr.T("draw.edit_unsupported_model", model, strings.Join(names, ", "))
+}
+
+// handleVaryCommand handles /vary command - generates variations of the photo the command replies to
+func (b *Bot) handleVaryCommand(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	tr := b.tr(ctx, message)
+
+	if b.imageGenerator == nil || len(b.imageGenerator.Models()) == 0 {
+		b.sendMessage(chatID, tr.T("draw.not_configured"))
+		return
+	}
+
+	source := replyPhoto(message)
+	if source == "" {
+		b.sendMessage(chatID, tr.T("vary.no_photo"))
+		return
+	}
+
+	// Variations take the options of /draw, an instruction belongs to /draw
+	prompt, opts, err := parseDrawArgs(message.CommandArguments(), tr)
+	if err != nil {
+		b.sendMessage(chatID, err.Error())
+		return
+	}
+	if prompt != "" {
+		b.sendMessage(chatID, tr.T("vary.prompt"))
+		return
+	}
+
+	if msg := b.editModelError(tr, opts.Model); msg != "" {
+		b.sendMessage(chatID, msg)
+		return
+	}
+	opts.Source = source
+
+	b.logger.Info().
+		Int64("user_id", message.From.ID).
+		Str("username", message.From.UserName).
+		Str("model", opts.Model).
+		Int("count", opts.count()).
+		Msg("Processing /vary command")
+
+	b.submitJob(ctx, models.JobKindDraw, message, &drawJob{
+		Message: message,
+		Options: opts,
+	}, b.runDrawJob)
+}
+
+// downloadFile downloads a file sent to the bot through the Bot API
+func (b *Bot) downloadFile(ctx context.Context, fileID string) ([]byte, error) {
+	fileURL, err := b.api.GetFileDirectURL(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := b.api.Client.Do(req)
+	if err != nil {
+		// The URL holds the bot token, keep it out of logs
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download file: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSourceImageSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if len(data) > maxSourceImageSize {
+		return nil, fmt.Errorf("file exceeds %d bytes", maxSourceImageSize)
+	}
+	return data, nil
+}
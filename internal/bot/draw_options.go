@@ -0,0 +1,136 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/telegram-llm-bot/internal/i18n"
+	"github.com/telegram-llm-bot/internal/images"
+)
+
+const (
+	// drawOptionPrefix starts /draw options, e.g. --model flux-schnell
+	drawOptionPrefix = "--"
+
+	// maxDrawPromptLength is the maximum length of a /draw prompt and of its negative prompt in characters
+	maxDrawPromptLength = 500
+)
+
+// drawOptions are the options of a /draw request, stored with the queued job
+type drawOptions struct {
+	Model          string `json:"model,omitempty"`           // Image model to try first, empty for the configured order
+	AspectRatio    string `json:"aspect_ratio,omitempty"`    // --ar, empty for images.DefaultAspectRatio
+	NegativePrompt string `json:"negative_prompt,omitempty"` // --no, comma-separated
+	Seed           *int64 `json:"seed,omitempty"`            // --seed, random if nil
+	Count          int    `json:"count,omitempty"`           // --n, one image if zero
+	Raw            bool   `json:"raw,omitempty"`             // --raw, send the prompt without enhancement
+
+	// Telegram file ID of the photo to edit as the prompt instructs, or to vary with /vary; not an option
+	Source string `json:"source,omitempty"`
+}
+
+// count returns the number of images to generate
+func (o drawOptions) count() int {
+	return max(o.Count, 1)
+}
+
+// parseDrawArgs splits /draw arguments into the prompt and options
+// Options may appear anywhere, as "--name value" or "--name=value"; the remaining words are the prompt
+// --no takes a comma-separated list and may be repeated, e.g. --no text,watermark; --raw takes no value
+func parseDrawArgs(args string, tr *i18n.Localizer) (string, drawOptions, error) {
+	var opts drawOptions
+	var prompt []string
+
+	words := strings.Fields(args)
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		if !strings.HasPrefix(word, drawOptionPrefix) || len(word) == len(drawOptionPrefix) {
+			prompt = append(prompt, word)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(word, "=")
+		name = strings.ToLower(name)
+		if name == "--raw" {
+			if hasValue {
+				return "", opts, errors.New(tr.T("draw.flag_value", name))
+			}
+			opts.Raw = true
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(words) {
+				return "", opts, errors.New(tr.T("draw.option_value", name))
+			}
+			i++
+			value = words[i]
+		}
+		if value == "" {
+			return "", opts, errors.New(tr.T("draw.option_value", name))
+		}
+
+		switch name {
+		case "--model":
+			opts.Model = strings.ToLower(value)
+		case "--ar":
+			if !images.SupportedAspectRatio(value) {
+				return "", opts, errors.New(tr.T("draw.invalid_aspect_ratio", value, strings.Join(images.AspectRatios, ", ")))
+			}
+			opts.AspectRatio = value
+		case "--no":
+			for _, term := range strings.Split(value, ",") {
+				if term = strings.TrimSpace(term); term == "" {
+					continue
+				}
+				if opts.NegativePrompt != "" {
+					opts.NegativePrompt += ", "
+				}
+				opts.NegativePrompt += term
+			}
+			if len([]rune(opts.NegativePrompt)) > maxDrawPromptLength {
+				return "", opts, errors.New(tr.T("draw.negative_too_long", maxDrawPromptLength))
+			}
+		case "--seed":
+			seed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || seed < 0 || seed > images.MaxSeed {
+				return "", opts, errors.New(tr.T("draw.invalid_seed", images.MaxSeed))
+			}
+			opts.Seed = &seed
+		case "--n":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 || count > images.MaxCount {
+				return "", opts, errors.New(tr.T("draw.invalid_count", images.MaxCount))
+			}
+			opts.Count = count
+		default:
+			return "", opts, errors.New(tr.T("draw.unknown_option", name))
+		}
+	}
+
+	return strings.Join(prompt, " "), opts, nil
+}
+
+// formatDrawArgs formats the options that reproduce generated images, in the syntax of parseDrawArgs
+// The model and seed are the ones actually used, so fallbacks and random seeds are reproduced too
+// Edits without --ar keep the proportions of the photo, so they have no aspect ratio to show
+func formatDrawArgs(model string, opts drawOptions, seed int64) string {
+	args := []string{"--model " + model}
+	if opts.AspectRatio != "" {
+		args = append(args, "--ar "+opts.AspectRatio)
+	} else if opts.Source == "" {
+		args = append(args, "--ar "+images.DefaultAspectRatio)
+	}
+	if opts.NegativePrompt != "" {
+		args = append(args, "--no "+strings.ReplaceAll(opts.NegativePrompt, ", ", ","))
+	}
+	args = append(args, fmt.Sprintf("--seed %d", seed))
+	if opts.count() > 1 {
+		args = append(args, fmt.Sprintf("--n %d", opts.count()))
+	}
+	if opts.Raw {
+		args = append(args, "--raw")
+	}
+	return strings.Join(args, " ")
+}
@@ -0,0 +1,200 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/telegram-llm-bot/internal/i18n"
+	"github.com/telegram-llm-bot/internal/images"
+)
+
+func TestParseDrawArgs(t *testing.T) {
+	tr := i18n.New(i18n.LocaleEn)
+	seed := int64(42)
+
+	tests := []struct {
+		name       string
+		args       string
+		wantPrompt string
+		wantOpts   drawOptions
+		wantErr    string
+	}{
+		{
+			name:       "prompt only",
+			args:       "a red  fox in the snow",
+			wantPrompt: "a red fox in the snow",
+		},
+		{
+			name:       "options anywhere",
+			args:       "--ar 16:9 a red fox --seed 42 in the snow --n 2",
+			wantPrompt: "a red fox in the snow",
+			wantOpts:   drawOptions{AspectRatio: "16:9", Seed: &seed, Count: 2},
+		},
+		{
+			name:       "values after equals sign",
+			args:       "fox --model=FLUX-Schnell --ar=3:4",
+			wantPrompt: "fox",
+			wantOpts:   drawOptions{Model: "flux-schnell", AspectRatio: "3:4"},
+		},
+		{
+			name:       "repeated no joins terms",
+			args:       "fox --no text,, watermark --no=blur",
+			wantPrompt: "fox watermark",
+			wantOpts:   drawOptions{NegativePrompt: "text, blur"},
+		},
+		{
+			name:       "raw flag",
+			args:       "fox --RAW",
+			wantPrompt: "fox",
+			wantOpts:   drawOptions{Raw: true},
+		},
+		{
+			name:       "bare prefix is part of the prompt",
+			args:       "fox -- cat",
+			wantPrompt: "fox -- cat",
+		},
+		{
+			name:    "raw with value",
+			args:    "fox --raw=yes",
+			wantErr: tr.T("draw.flag_value", "--raw"),
+		},
+		{
+			name:    "missing value",
+			args:    "fox --seed",
+			wantErr: tr.T("draw.option_value", "--seed"),
+		},
+		{
+			name:    "empty value",
+			args:    "fox --ar=",
+			wantErr: tr.T("draw.option_value", "--ar"),
+		},
+		{
+			name:    "unsupported aspect ratio",
+			args:    "fox --ar 5:4",
+			wantErr: tr.T("draw.invalid_aspect_ratio", "5:4", strings.Join(images.AspectRatios, ", ")),
+		},
+		{
+			name:    "negative seed",
+			args:    "fox --seed -1",
+			wantErr: tr.T("draw.invalid_seed", images.MaxSeed),
+		},
+		{
+			name:    "seed above maximum",
+			args:    "fox --seed 2147483648",
+			wantErr: tr.T("draw.invalid_seed", images.MaxSeed),
+		},
+		{
+			name:    "too many images",
+			args:    "fox --n 5",
+			wantErr: tr.T("draw.invalid_count", images.MaxCount),
+		},
+		{
+			name:    "zero images",
+			args:    "fox --n 0",
+			wantErr: tr.T("draw.invalid_count", images.MaxCount),
+		},
+		{
+			name:    "negative prompt too long",
+			args:    "fox --no " + strings.Repeat("a", maxDrawPromptLength) + " --no b",
+			wantErr: tr.T("draw.negative_too_long", maxDrawPromptLength),
+		},
+		{
+			name:    "unknown option",
+			args:    "fox --style anime",
+			wantErr: tr.T("draw.unknown_option", "--style"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompt, opts, err := parseDrawArgs(tt.args, tr)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("parseDrawArgs(%q) error = %v, want %q", tt.args, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseDrawArgs(%q) unexpected error: %v", tt.args, err)
+			}
+			if prompt != tt.wantPrompt {
+				t.Errorf("prompt = %q, want %q", prompt, tt.wantPrompt)
+			}
+			if !equalDrawOptions(opts, tt.wantOpts) {
+				t.Errorf("options = %+v, want %+v", opts, tt.wantOpts)
+			}
+		})
+	}
+}
+
+func TestFormatDrawArgs(t *testing.T) {
+	tests := []struct {
+		name  string
+		model string
+		opts  drawOptions
+		seed  int64
+		want  string
+	}{
+		{
+			name:  "defaults",
+			model: "imagen",
+			seed:  7,
+			want:  "--model imagen --ar 1:1 --seed 7",
+		},
+		{
+			name:  "all options",
+			model: "flux-schnell",
+			opts:  drawOptions{AspectRatio: "16:9", NegativePrompt: "text, blur", Count: 3, Raw: true},
+			seed:  123,
+			want:  "--model flux-schnell --ar 16:9 --no text,blur --seed 123 --n 3 --raw",
+		},
+		{
+			name:  "edit keeps photo proportions",
+			model: "imagen",
+			opts:  drawOptions{Source: "file-id"},
+			seed:  1,
+			want:  "--model imagen --seed 1",
+		},
+		{
+			name:  "edit with aspect ratio",
+			model: "imagen",
+			opts:  drawOptions{Source: "file-id", AspectRatio: "4:3"},
+			seed:  1,
+			want:  "--model imagen --ar 4:3 --seed 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatDrawArgs(tt.model, tt.opts, tt.seed); got != tt.want {
+				t.Errorf("formatDrawArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFormatDrawArgsRoundTrip checks that formatted options parse back into the same request
+func TestFormatDrawArgsRoundTrip(t *testing.T) {
+	tr := i18n.New(i18n.LocaleEn)
+	opts := drawOptions{Model: "flux-schnell", AspectRatio: "2:3", NegativePrompt: "text, watermark", Count: 2, Raw: true}
+
+	_, parsed, err := parseDrawArgs("fox "+formatDrawArgs(opts.Model, opts, 99), tr)
+	if err != nil {
+		t.Fatalf("parseDrawArgs() unexpected error: %v", err)
+	}
+
+	seed := int64(99)
+	opts.Seed = &seed
+	if !equalDrawOptions(parsed, opts) {
+		t.Errorf("options = %+v, want %+v", parsed, opts)
+	}
+}
+
+// equalDrawOptions compares options by the seed value rather than the pointer
+func equalDrawOptions(a, b drawOptions) bool {
+	if (a.Seed == nil) != (b.Seed == nil) || (a.Seed != nil && *a.Seed != *b.Seed) {
+		return false
+	}
+	a.Seed, b.Seed = nil, nil
+	return a == b
+}
@@ -2,18 +2,27 @@ package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
+	"unicode/utf16"
 	"unicode/utf8"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/telegram-llm-bot/internal/i18n"
+	"github.com/telegram-llm-bot/internal/images"
+	"github.com/telegram-llm-bot/internal/jobs"
+	"github.com/telegram-llm-bot/internal/metrics"
 	"github.com/telegram-llm-bot/internal/models"
 )
 
 const (
 	// MaxQuestionLength is the maximum allowed length for a user question in characters
 	MaxQuestionLength = 2000
+
+	// maxCaptionLength is the Telegram limit of photo captions in UTF-16 code units
+	maxCaptionLength = 1024
 )
 
 // handleUpdate processes incoming update
@@ -23,6 +32,11 @@ func (b *Bot) handleUpdate(ctx context.Context, update tgbotapi.Update) {
 		// Handle message
 		if update.Message != nil {
 			b.handleMessage(ctx, update.Message)
+		} else if update.CallbackQuery != nil {
+			metrics.UpdatesProcessed.WithLabelValues("callback").Inc()
+			b.handleCallbackQuery(ctx, update.CallbackQuery)
+		} else {
+			metrics.UpdatesProcessed.WithLabelValues("other").Inc()
 		}
 	})
 }
@@ -31,6 +45,7 @@ func (b *Bot) handleUpdate(ctx context.Context, update tgbotapi.Update) {
 func (b *Bot) handleMessage(ctx context.Context, message *tgbotapi.Message) {
 	// Handle commands from any chat (including private messages)
 	if message.IsCommand() {
+		metrics.UpdatesProcessed.WithLabelValues("command").Inc()
 		b.handleCommand(ctx, message)
 		return
 	}
@@ -40,6 +55,7 @@ func (b *Bot) handleMessage(ctx context.Context, message *tgbotapi.Message) {
 		b.logger.Debug().
 			Int64("chat_id", message.Chat.ID).
 			Msg("Ignoring message from non-allowed chat")
+		metrics.UpdatesProcessed.WithLabelValues("ignored").Inc()
 		return
 	}
 
@@ -51,9 +67,28 @@ func (b *Bot) handleMessage(ctx context.Context, message *tgbotapi.Message) {
 
 	// Check if message contains bot mention
 	if b.isMentioned(message) {
+		metrics.UpdatesProcessed.WithLabelValues("mention").Inc()
 		b.handleMention(ctx, message)
 		return
 	}
+
+	metrics.UpdatesProcessed.WithLabelValues("message").Inc()
+}
+
+// handleCallbackQuery processes presses of inline keyboard buttons
+func (b *Bot) handleCallbackQuery(ctx context.Context, query *tgbotapi.CallbackQuery) {
+	// Buttons of inline mode messages have no chat
+	if query.Message == nil || !b.config.IsAllowedChat(query.Message.Chat.ID) {
+		b.answerCallback(query, "")
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(query.Data, summariesCallbackPrefix):
+		b.handleSummariesCallback(ctx, query, strings.TrimPrefix(query.Data, summariesCallbackPrefix))
+	default:
+		b.answerCallback(query, "")
+	}
 }
 
 // handleCommand processes bot commands
@@ -73,12 +108,42 @@ func (b *Bot) handleCommand(ctx context.Context, message *tgbotapi.Message) {
 		b.handleHelpCommand(ctx, message)
 	case "summary":
 		b.handleSummaryCommand(ctx, message)
+	case "summaries":
+		b.handleSummariesCommand(ctx, message)
+	case "summarytemplate":
+		b.handleSummaryTemplateCommand(ctx, message)
+	case "summaryconfig":
+		b.handleSummaryConfigCommand(ctx, message)
 	case "sync":
 		b.handleSyncCommand(ctx, message)
 	case "draw":
 		b.handleDrawCommand(ctx, message)
+	case "vary":
+		b.handleVaryCommand(ctx, message)
+	case "tier":
+		b.handleTierCommand(ctx, message)
+	case "settier":
+		b.handleSetTierCommand(ctx, message)
+	case "chattier":
+		b.handleChatTierCommand(ctx, message)
+	case "quota":
+		b.handleQuotaCommand(ctx, message)
+	case "top":
+		b.handleTopCommand(ctx, message)
+	case "usage":
+		b.handleUsageCommand(ctx, message)
+	case "schedule":
+		b.handleScheduleCommand(ctx, message)
+	case "timezone":
+		b.handleTimezoneCommand(ctx, message)
+	case "mytimezone":
+		b.handleMyTimezoneCommand(ctx, message)
+	case "language":
+		b.handleLanguageCommand(ctx, message)
+	case "mylanguage":
+		b.handleMyLanguageCommand(ctx, message)
 	default:
-		b.sendMessage(message.Chat.ID, "❓ Неизвестная команда. Используйте /help для списка команд.")
+		b.sendMessage(message.Chat.ID, b.tr(ctx, message).T("common.unknown_command"))
 	}
 }
 
@@ -87,34 +152,34 @@ func (b *Bot) handleStatsCommand(ctx context.Context, message *tgbotapi.Message)
 	userID := message.From.ID
 	username := message.From.UserName
 	firstName := message.From.FirstName
+	tr := b.tr(ctx, message)
 
 	// Get user stats
-	stats, err := b.limiter.GetUserStats(ctx, userID, username, firstName)
+	stats, err := b.limiter.GetUserStats(ctx, userID, message.Chat.ID, username, firstName)
 	if err != nil {
 		b.logger.Error().
 			Err(err).
 			Int64("user_id", userID).
 			Msg("Failed to get user stats")
-		b.sendErrorMessage(message.Chat.ID, "❌ Ошибка при получении статистики")
+		b.sendErrorMessage(message.Chat.ID, tr.T("stats.failed"))
 		return
 	}
 
+	// Format token usage line
+	tokensLine := tr.T("stats.tokens", stats.TokensUsed)
+	if stats.TokenBudget > 0 {
+		tokensLine = tr.T("stats.tokens_budget", stats.TokensUsed, stats.TokenBudget)
+	}
+
 	// Format stats message
-	statsMsg := fmt.Sprintf(
-		"📊 *Статистика для %s*\n\n"+
-			"🤖 *Gemini Pro (Thinking):*\n"+
-			"   Использовано: %d/%d\n"+
-			"   Осталось: %d\n\n"+
-			"⚡ *Gemini Flash:*\n"+
-			"   Использовано: %d/%d\n"+
-			"   Осталось: %d\n\n"+
-			"📈 *Всего запросов:* %d\n"+
-			"⏰ *Сброс лимитов через:* %d ч.",
+	statsMsg := tr.T("stats.text",
 		firstName,
+		stats.Tier,
 		stats.ProRequestsUsed, stats.ProRequestsLimit,
-		stats.ProRequestsLimit-stats.ProRequestsUsed,
+		max(stats.ProRequestsLimit-stats.ProRequestsUsed, 0),
 		stats.FlashRequestsUsed, stats.FlashRequestsLimit,
-		stats.FlashRequestsLimit-stats.FlashRequestsUsed,
+		max(stats.FlashRequestsLimit-stats.FlashRequestsUsed, 0),
+		tokensLine,
 		stats.TotalRequests,
 		stats.ResetsInHours,
 	)
@@ -124,44 +189,51 @@ func (b *Bot) handleStatsCommand(ctx context.Context, message *tgbotapi.Message)
 
 // handleHelpCommand handles /help and /start commands
 func (b *Bot) handleHelpCommand(ctx context.Context, message *tgbotapi.Message) {
-	helpMsg := fmt.Sprintf(
-		"👋 *Привет! Я бот с AI ассистентом*\n\n"+
-			"*Как использовать:*\n"+
-			"Просто упомяните меня (@%s) и задайте вопрос!\n\n"+
-			"*Доступные команды:*\n"+
-			"/stats - Посмотреть свою статистику\n"+
-			"/draw <запрос> - Сгенерировать изображение по описанию\n"+
-			"/summary - Сгенерировать саммари за вчерашний день\n"+
-			"/sync - Запустить синхронизацию RAG (индексация сообщений)\n"+
-			"/help - Показать это сообщение\n\n"+
-			"*Лимиты:*\n"+
-			"• Gemini Pro (думающая модель): %d запросов/день\n"+
-			"• Gemini Flash (быстрая модель): %d запросов/день\n"+
-			"• Генерация изображений: %d генераций/день\n\n"+
-			"Сначала используются запросы к Pro модели, затем к Flash.\n"+
-			"Лимиты сбрасываются в полночь по московскому времени.\n\n"+
-			"*Примеры:*\n"+
-			"• /draw красивый закат над океаном\n"+
-			"• /draw кот в космосе в стиле киберпанк\n\n"+
-			"*Автоматические задачи:*\n"+
-			"• 03:00 МСК - Синхронизация RAG (индексация embeddings)\n"+
-			"• 07:00 МСК - Ежедневное саммари",
+	// Show limits effective for the caller in this chat
+	limits := b.limiter.GetEffectiveLimits(ctx, message.From.ID, message.Chat.ID)
+
+	helpMsg := b.tr(ctx, message).T("help.text",
 		b.config.TelegramUsername,
-		b.config.ProDailyLimit,
-		b.config.FlashDailyLimit,
-		b.config.ImageGenerationDailyLimitPerUser,
+		limits.Tier,
+		limits.ProDailyLimit,
+		limits.FlashDailyLimit,
+		limits.ImageDailyLimit,
+		b.settings.LimitLocation().String(),
 	)
 
 	b.sendMessage(message.Chat.ID, helpMsg)
 }
 
-// handleSummaryCommand handles /summary command - generates summary for yesterday
+// summaryPeriods maps /summary arguments to digest periods, empty means yesterday's summary
+var summaryPeriods = map[string]string{
+	"":                       "",
+	"day":                    "",
+	models.DigestPeriodWeek:  models.DigestPeriodWeek,
+	models.DigestPeriodMonth: models.DigestPeriodMonth,
+}
+
+// handleSummaryCommand handles /summary command - generates summary for yesterday,
+// a digest of the last week or month, or an on-demand summary of a range
+// Usage: /summary [week|month|<duration>|<date>|since my last message]
 func (b *Bot) handleSummaryCommand(ctx context.Context, message *tgbotapi.Message) {
 	chatID := message.Chat.ID
+	tr := b.tr(ctx, message)
 
 	// Only allow in allowed chats
 	if !b.config.IsAllowedChat(chatID) {
-		b.sendMessage(chatID, "❌ Эта команда доступна только в разрешенных чатах.")
+		b.sendMessage(chatID, tr.T("common.chat_not_allowed"))
+		return
+	}
+
+	arg := strings.ToLower(strings.Join(strings.Fields(message.CommandArguments()), " "))
+	if catchUpArgs[arg] {
+		b.handleCatchUpSummary(ctx, message)
+		return
+	}
+
+	period, ok := summaryPeriods[arg]
+	if !ok {
+		b.handleRangeSummary(ctx, message, arg)
 		return
 	}
 
@@ -169,33 +241,43 @@ func (b *Bot) handleSummaryCommand(ctx context.Context, message *tgbotapi.Messag
 		Int64("chat_id", chatID).
 		Int64("user_id", message.From.ID).
 		Str("username", message.From.UserName).
+		Str("period", period).
 		Msg("Manual summary generation requested")
 
 	// Send "generating" message
-	b.sendMessage(chatID, "⏳ Генерирую саммари за вчерашний день...")
+	switch period {
+	case models.DigestPeriodWeek:
+		b.sendMessage(chatID, tr.T("summary.generating_week"))
+	case models.DigestPeriodMonth:
+		b.sendMessage(chatID, tr.T("summary.generating_month"))
+	default:
+		b.sendMessage(chatID, tr.T("summary.generating_day"))
+	}
 
 	// Trigger summary generation callback if available
 	if b.summaryCallback != nil {
-		if err := b.summaryCallback(chatID); err != nil {
+		if err := b.summaryCallback(ctx, chatID, period); err != nil {
 			b.logger.Error().
 				Err(err).
 				Int64("chat_id", chatID).
+				Str("period", period).
 				Msg("Failed to generate manual summary")
-			b.sendMessage(chatID, "❌ Ошибка при генерации саммари. Попробуйте позже.")
+			b.sendMessage(chatID, tr.T("summary.failed"))
 			return
 		}
 	} else {
-		b.sendMessage(chatID, "❌ Функция саммари не настроена.")
+		b.sendMessage(chatID, tr.T("summary.not_configured"))
 	}
 }
 
 // handleSyncCommand handles /sync command - manual RAG synchronization
 func (b *Bot) handleSyncCommand(ctx context.Context, message *tgbotapi.Message) {
 	chatID := message.Chat.ID
+	tr := b.tr(ctx, message)
 
 	// Only allow in allowed chats
 	if !b.config.IsAllowedChat(chatID) {
-		b.sendMessage(chatID, "❌ Эта команда доступна только в разрешенных чатах.")
+		b.sendMessage(chatID, tr.T("common.chat_not_allowed"))
 		return
 	}
 
@@ -206,24 +288,26 @@ func (b *Bot) handleSyncCommand(ctx context.Context, message *tgbotapi.Message)
 		Msg("Manual RAG sync requested")
 
 	// Send "starting" message
-	b.sendMessage(chatID, "🔄 Запускаю синхронизацию RAG...\n\nЭто может занять несколько минут.")
+	b.sendMessage(chatID, tr.T("sync.starting"))
 
 	// Trigger sync callback if available
 	if b.syncCallback != nil {
-		// Run in goroutine to not block
+		// Run in background to not block the chat, tracked for graceful shutdown
+		b.wg.Add(1)
 		go func() {
-			if err := b.syncCallback(); err != nil {
+			defer b.wg.Done()
+			if err := b.syncCallback(b.workCtx); err != nil {
 				b.logger.Error().
 					Err(err).
 					Int64("chat_id", chatID).
 					Msg("Failed to run manual sync")
-				b.sendMessage(chatID, "❌ Ошибка при синхронизации. Попробуйте позже.")
+				b.sendMessage(chatID, tr.T("sync.failed"))
 			} else {
-				b.sendMessage(chatID, "✅ Синхронизация завершена успешно!")
+				b.sendMessage(chatID, tr.T("sync.done"))
 			}
 		}()
 	} else {
-		b.sendMessage(chatID, "❌ Функция синхронизации не настроена.")
+		b.sendMessage(chatID, tr.T("sync.not_configured"))
 	}
 }
 
@@ -231,13 +315,13 @@ func (b *Bot) handleSyncCommand(ctx context.Context, message *tgbotapi.Message)
 func (b *Bot) handleMention(ctx context.Context, message *tgbotapi.Message) {
 	userID := message.From.ID
 	username := message.From.UserName
-	firstName := message.From.FirstName
 	chatID := message.Chat.ID
+	tr := b.tr(ctx, message)
 
 	// Extract question text (remove bot mention)
 	questionText := b.extractQuestion(message)
 	if questionText == "" {
-		b.sendMessage(chatID, "❓ Пожалуйста, задайте вопрос после упоминания.")
+		b.sendMessage(chatID, tr.T("mention.empty"))
 		return
 	}
 
@@ -252,10 +336,7 @@ func (b *Bot) handleMention(ctx context.Context, message *tgbotapi.Message) {
 		questionText = string(questionRunes[:MaxQuestionLength])
 
 		// Notify user about truncation
-		b.sendMessage(chatID, fmt.Sprintf(
-			"⚠️ Ваш вопрос был обрезан до %d символов. Пожалуйста, формулируйте вопросы короче.",
-			MaxQuestionLength,
-		))
+		b.sendMessage(chatID, tr.T("mention.truncated", MaxQuestionLength))
 	}
 
 	b.logger.Info().
@@ -264,29 +345,46 @@ func (b *Bot) handleMention(ctx context.Context, message *tgbotapi.Message) {
 		Int("question_length", len(questionRunes)).
 		Msg("Processing mention")
 
+	b.submitJob(ctx, models.JobKindMention, message, &mentionJob{
+		Message:  message,
+		Question: questionText,
+	}, b.runMentionJob)
+}
+
+// answerMention answers a question from a mention, it runs as a job of the queue
+// Returns an error if the attempt should be retried
+func (b *Bot) answerMention(ctx context.Context, job *models.Job, message *tgbotapi.Message, questionText string) error {
+	userID := message.From.ID
+	username := message.From.UserName
+	firstName := message.From.FirstName
+	chatID := message.Chat.ID
+	tr := b.tr(ctx, message)
+
 	// Send typing action
 	b.sendTypingAction(chatID)
 
 	// Check rate limits
-	limitResult, err := b.limiter.CheckLimit(ctx, userID)
+	limitResult, err := b.limiter.CheckLimit(ctx, userID, chatID, tr)
 	if err != nil {
 		b.logger.Error().
 			Err(err).
 			Int64("user_id", userID).
 			Msg("Failed to check rate limit")
-		b.sendErrorMessage(chatID, "❌ Ошибка при проверке лимитов")
-		return
+		if job.IsLastAttempt() {
+			b.sendErrorMessage(chatID, tr.T("common.limit_check_failed"))
+		}
+		return fmt.Errorf("failed to check rate limit: %w", err)
 	}
 
 	// If limit exceeded, send message and return
 	if !limitResult.Allowed {
 		b.sendMessage(chatID, limitResult.Message)
-		return
+		return nil
 	}
 
 	// Perform RAG search for relevant context
 	var ragContext string
-	ragResult, err := b.ragSearcher.Search(ctx, questionText, chatID)
+	ragResult, err := b.ragSearcher.Search(ctx, questionText, chatID, tr)
 	if err != nil {
 		b.logger.Warn().
 			Err(err).
@@ -314,6 +412,7 @@ func (b *Bot) handleMention(ctx context.Context, message *tgbotapi.Message) {
 		RAGContext:  ragContext,
 		ModelType:   limitResult.ModelToUse,
 		TimeoutSecs: b.config.GeminiTimeout,
+		Locale:      tr.Locale(),
 	}
 
 	// Generate response from LLM
@@ -327,22 +426,28 @@ func (b *Bot) handleMention(ctx context.Context, message *tgbotapi.Message) {
 			Str("model", llmResp.ModelUsed).
 			Msg("LLM request failed")
 
-		// Don't increment usage if request failed
-		b.sendErrorMessage(chatID, "❌ Извините, произошла ошибка при обработке вашего запроса. Попробуйте позже.")
+		// Don't increment usage if request failed, the job is retried unless it was the last attempt
+		if job.IsLastAttempt() {
+			b.sendErrorMessage(chatID, tr.T("mention.failed"))
+		}
 
 		// Log failed request
 		if err := b.storage.LogRequest(ctx, &models.RequestLog{
-			UserID:          userID,
-			Username:        username,
-			FirstName:       firstName,
-			ChatID:          chatID,
-			RequestText:     questionText,
-			ResponseText:    "",
-			ModelUsed:       llmResp.ModelUsed,
-			ResponseLength:  0,
-			ExecutionTimeMs: llmResp.ExecutionTimeMs,
-			ErrorMessage:    llmResp.Error.Error(),
-			CreatedAt:       time.Now().UTC(),
+			UserID:           userID,
+			Username:         username,
+			FirstName:        firstName,
+			ChatID:           chatID,
+			RequestText:      questionText,
+			ResponseText:     "",
+			ModelUsed:        llmResp.ModelUsed,
+			ResponseLength:   0,
+			ExecutionTimeMs:  llmResp.ExecutionTimeMs,
+			ErrorMessage:     llmResp.Error.Error(),
+			PromptTokens:     llmResp.Usage.PromptTokens,
+			CandidatesTokens: llmResp.Usage.CandidatesTokens,
+			TotalTokens:      llmResp.Usage.TotalTokens,
+			CostUSD:          llmResp.Usage.CostUSD,
+			CreatedAt:        time.Now().UTC(),
 		}); err != nil {
 			b.logger.Error().
 				Err(err).
@@ -350,11 +455,11 @@ func (b *Bot) handleMention(ctx context.Context, message *tgbotapi.Message) {
 				Msg("Failed to log failed request, but continuing")
 		}
 
-		return
+		return fmt.Errorf("llm request failed: %w", llmResp.Error)
 	}
 
-	// Increment usage
-	err = b.limiter.IncrementUsage(ctx, userID, limitResult.ModelToUse)
+	// Increment usage (request count and consumed tokens)
+	err = b.limiter.IncrementUsage(ctx, userID, chatID, limitResult.ModelToUse, llmResp.Usage.TotalTokens)
 	if err != nil {
 		b.logger.Error().
 			Err(err).
@@ -365,21 +470,25 @@ func (b *Bot) handleMention(ctx context.Context, message *tgbotapi.Message) {
 
 	// Log successful request
 	// Note: We use UTC for database timestamps to maintain consistency
-	// Rate limiter uses configured timezone (Moscow) for daily limits
+	// Rate limiter uses the user's or chat's timezone for daily limits
 	// This separation allows proper timezone-based limit resets while
 	// keeping database timestamps in universal format
 	if err := b.storage.LogRequest(ctx, &models.RequestLog{
-		UserID:          userID,
-		Username:        username,
-		FirstName:       firstName,
-		ChatID:          chatID,
-		RequestText:     questionText,
-		ResponseText:    llmResp.Text,
-		ModelUsed:       llmResp.ModelUsed,
-		ResponseLength:  llmResp.Length,
-		ExecutionTimeMs: llmResp.ExecutionTimeMs,
-		ErrorMessage:    "",
-		CreatedAt:       time.Now().UTC(),
+		UserID:           userID,
+		Username:         username,
+		FirstName:        firstName,
+		ChatID:           chatID,
+		RequestText:      questionText,
+		ResponseText:     llmResp.Text,
+		ModelUsed:        llmResp.ModelUsed,
+		ResponseLength:   llmResp.Length,
+		ExecutionTimeMs:  llmResp.ExecutionTimeMs,
+		ErrorMessage:     "",
+		PromptTokens:     llmResp.Usage.PromptTokens,
+		CandidatesTokens: llmResp.Usage.CandidatesTokens,
+		TotalTokens:      llmResp.Usage.TotalTokens,
+		CostUSD:          llmResp.Usage.CostUSD,
+		CreatedAt:        time.Now().UTC(),
 	}); err != nil {
 		b.logger.Error().
 			Err(err).
@@ -394,8 +503,7 @@ func (b *Bot) handleMention(ctx context.Context, message *tgbotapi.Message) {
 	}
 
 	// Send response
-	responseMsg := fmt.Sprintf(
-		"%s\n\n---\n%s _Модель: %s | Время: %dмс_",
+	responseMsg := tr.T("mention.footer",
 		llmResp.Text,
 		modelEmoji,
 		string(limitResult.ModelToUse),
@@ -403,6 +511,7 @@ func (b *Bot) handleMention(ctx context.Context, message *tgbotapi.Message) {
 	)
 
 	b.sendMessage(chatID, responseMsg)
+	return nil
 }
 
 // isMentioned checks if bot is mentioned in the message
@@ -432,24 +541,50 @@ func (b *Bot) isMentioned(message *tgbotapi.Message) bool {
 }
 
 // handleDrawCommand handles /draw command - generates an image from text prompt
+// Sent as a reply to a photo, the prompt is an instruction to edit the photo
 func (b *Bot) handleDrawCommand(ctx context.Context, message *tgbotapi.Message) {
 	chatID := message.Chat.ID
 	userID := message.From.ID
 	username := message.From.UserName
-	firstName := message.From.FirstName
+	tr := b.tr(ctx, message)
 
-	// Extract prompt text after /draw command
-	prompt := strings.TrimSpace(message.CommandArguments())
+	if b.imageGenerator == nil || len(b.imageGenerator.Models()) == 0 {
+		b.sendMessage(chatID, tr.T("draw.not_configured"))
+		return
+	}
+
+	// Extract prompt text and options after /draw command
+	prompt, opts, err := parseDrawArgs(message.CommandArguments(), tr)
+	if err != nil {
+		b.sendMessage(chatID, err.Error())
+		return
+	}
+
+	opts.Source = replyPhoto(message)
 
 	// Validate prompt is not empty
 	if prompt == "" {
-		b.sendMessage(chatID, "Укажите описание изображения. Пример: /draw красивый закат над океаном")
+		if opts.Source != "" {
+			b.sendMessage(chatID, tr.T("draw.edit_empty"))
+			return
+		}
+		b.sendMessage(chatID, tr.T("draw.empty")+tr.T("draw.models", b.imageModelList(tr)))
+		return
+	}
+
+	if opts.Source != "" {
+		if msg := b.editModelError(tr, opts.Model); msg != "" {
+			b.sendMessage(chatID, msg)
+			return
+		}
+	} else if opts.Model != "" && !b.imageGenerator.HasModel(opts.Model) {
+		b.sendMessage(chatID, tr.T("draw.unknown_model", opts.Model, b.imageModelList(tr)))
 		return
 	}
 
-	// Validate prompt length (max 500 characters as per spec)
-	if len([]rune(prompt)) > 500 {
-		b.sendMessage(chatID, "⚠️ Описание слишком длинное. Максимум 500 символов.")
+	// Validate prompt length, the negative prompt is limited by parseDrawArgs
+	if len([]rune(prompt)) > maxDrawPromptLength {
+		b.sendMessage(chatID, tr.T("draw.too_long"))
 		return
 	}
 
@@ -457,87 +592,297 @@ func (b *Bot) handleDrawCommand(ctx context.Context, message *tgbotapi.Message)
 		Int64("user_id", userID).
 		Str("username", username).
 		Int("prompt_length", len([]rune(prompt))).
+		Str("model", opts.Model).
+		Str("aspect_ratio", opts.AspectRatio).
+		Int("count", opts.count()).
+		Bool("edit", opts.Source != "").
 		Msg("Processing /draw command")
 
-	// Get current date in configured timezone for limit checking
-	loc, err := time.LoadLocation(b.config.Timezone)
-	if err != nil {
-		b.logger.Error().Err(err).Msg("Failed to load timezone, using UTC")
-		loc = time.UTC
+	b.submitJob(ctx, models.JobKindDraw, message, &drawJob{
+		Message: message,
+		Prompt:  prompt,
+		Options: opts,
+	}, b.runDrawJob)
+}
+
+// imageModelList lists the available image models, marking the default one
+func (b *Bot) imageModelList(tr *i18n.Localizer) string {
+	names := make([]string, 0, len(b.imageGenerator.Models()))
+	for i, model := range b.imageGenerator.Models() {
+		if i == 0 {
+			names = append(names, tr.T("draw.model_default", model.Name))
+			continue
+		}
+		names = append(names, model.Name)
 	}
-	currentDate := time.Now().In(loc).Format("2006-01-02")
+	return strings.Join(names, ", ")
+}
+
+// drawImage generates an image for /draw and /vary, it runs as a job of the queue
+// Edits and variations of a photo count against the same image limits as generated images
+// Returns an error if the attempt should be retried
+func (b *Bot) drawImage(ctx context.Context, job *models.Job, payload *drawJob) error {
+	message, prompt, opts := payload.Message, payload.Prompt, payload.Options
+	chatID := message.Chat.ID
+	userID := message.From.ID
+	username := message.From.UserName
+	firstName := message.From.FirstName
+	tr := b.tr(ctx, message)
+
+	// Image limits reset at midnight in the timezone of limit days, like other personal limits
+	currentDate := time.Now().In(b.settings.LimitLocation()).Format("2006-01-02")
+
+	// Resolve the user's tier-specific image limit
+	limits := b.limiter.GetEffectiveLimits(ctx, userID, chatID)
 
 	// Check image generation limits
-	allowed, remaining, err := b.storage.CheckImageGenerationLimit(ctx, userID, chatID, currentDate, b.config)
+	allowed, remaining, err := b.storage.CheckImageGenerationLimit(
+		ctx, userID, chatID, currentDate,
+		limits.ImageDailyLimit, b.config.ImageGenerationDailyLimitPerChat,
+	)
 	if err != nil {
 		b.logger.Error().
 			Err(err).
 			Int64("user_id", userID).
 			Msg("Failed to check image generation limit")
-		b.sendErrorMessage(chatID, "❌ Ошибка при проверке лимитов")
-		return
+		if job.IsLastAttempt() {
+			b.sendErrorMessage(chatID, tr.T("common.limit_check_failed"))
+		}
+		return fmt.Errorf("failed to check image generation limit: %w", err)
 	}
 
 	if !allowed {
-		b.sendMessage(chatID, fmt.Sprintf(
-			"❌ Вы исчерпали дневной лимит генераций (%d/день). Попробуйте завтра.",
-			b.config.ImageGenerationDailyLimitPerUser,
-		))
-		return
+		b.sendMessage(chatID, tr.T("draw.limit", limits.ImageDailyLimit))
+		return nil
 	}
 
-	// Send "generating" message
-	b.sendMessage(chatID, "🎨 Генерирую изображение...")
+	// Every image of --n counts against the limits
+	count := opts.count()
+	if remaining < count {
+		b.sendMessage(chatID, tr.T("draw.limit_count", count, remaining))
+		return nil
+	}
+
+	// Send "generating" message once, not on every retry
+	if job.IsFirstAttempt() {
+		if opts.Source != "" {
+			b.sendMessage(chatID, tr.T("draw.editing"))
+		} else {
+			b.sendMessage(chatID, tr.T("draw.generating"))
+		}
+	}
 	b.sendTypingAction(chatID)
 
-	// Generate image
-	imageData, err := b.llmClient.GenerateImage(ctx, prompt)
+	// Download the photo to edit, it is downloaded again on retries rather than stored with the job
+	var source []byte
+	if opts.Source != "" {
+		source, err = b.downloadFile(ctx, opts.Source)
+		if err != nil {
+			b.logger.Error().
+				Err(err).
+				Int64("user_id", userID).
+				Msg("Failed to download photo to edit")
+			if job.IsLastAttempt() {
+				b.sendErrorMessage(chatID, tr.T("draw.source_failed"))
+			}
+			return fmt.Errorf("failed to download photo to edit: %w", err)
+		}
+	}
+
+	// Expand text-to-image prompts into detailed English ones, edit instructions are sent as written
+	// The enhanced prompt is stored with the job, so retries send the same prompt without paying for it again
+	enhanced := payload.EnhancedPrompt
+	if enhanced == "" && opts.Source == "" && !opts.Raw && b.config.ImagePromptEnhancement {
+		if enhanced = b.enhanceImagePrompt(ctx, message, prompt); enhanced != "" {
+			payload.EnhancedPrompt = enhanced
+			b.saveJobPayload(ctx, job, payload)
+		}
+	}
+	imagePrompt := prompt
+	if enhanced != "" {
+		imagePrompt = enhanced
+	}
+
+	// Generate images, falling back to other models if the chosen one fails
+	result, err := b.imageGenerator.Generate(ctx, &images.Request{
+		Prompt:         imagePrompt,
+		NegativePrompt: opts.NegativePrompt,
+		AspectRatio:    opts.AspectRatio,
+		Seed:           opts.Seed,
+		Count:          count,
+		Model:          opts.Model,
+		Source:         source,
+	})
+	if errors.Is(err, images.ErrEditingUnsupported) {
+		// The configured models changed since the request was queued
+		b.sendMessage(chatID, tr.T("draw.edit_not_configured"))
+		return jobs.Permanent(err)
+	}
 	if err != nil {
 		b.logger.Error().
 			Err(err).
 			Int64("user_id", userID).
 			Str("prompt", prompt).
+			Str("model", opts.Model).
 			Msg("Failed to generate image")
 
-		b.sendErrorMessage(chatID, "⚠️ Сервис генерации временно недоступен. Попробуйте позже.")
-		return
+		if job.IsLastAttempt() {
+			b.sendErrorMessage(chatID, tr.T("draw.unavailable"))
+		}
+		return fmt.Errorf("failed to generate image: %w", err)
 	}
 
-	// Record usage
-	if err := b.storage.RecordImageGeneration(ctx, userID, chatID, currentDate); err != nil {
+	// Send images to user, the caption holds the options that reproduce them and the remaining count
+	remaining -= count
+	// An enhanced prompt is reproduced by sending it with --raw, a new enhancement would word it differently
+	reproduce := opts
+	if enhanced != "" {
+		reproduce.Raw = true
+	}
+	caption := tr.T("draw.caption", result.Model, formatDrawArgs(result.Model, reproduce, result.Images[0].Seed), remaining)
+	if enhanced != "" {
+		caption += tr.T("draw.enhanced_prompt", enhanced)
+	}
+
+	sent, err := b.sendDrawnImages(chatID, result.Images, truncateCaption(caption))
+	if err != nil {
 		b.logger.Error().
 			Err(err).
 			Int64("user_id", userID).
-			Msg("Failed to record image generation")
-		// Continue anyway, we already generated the image
+			Msg("Failed to send generated image")
+		b.sendErrorMessage(chatID, tr.T("draw.send_failed"))
+		// Nothing is counted yet; a retry would pay for the generation again, so the request is dropped
+		return jobs.Permanent(fmt.Errorf("failed to send generated image: %w", err))
 	}
 
-	// Send image to user
-	photoConfig := tgbotapi.NewPhoto(chatID, tgbotapi.FileBytes{
-		Name:  "generated_image.jpg",
-		Bytes: imageData,
-	})
-
-	// Add caption with remaining count
-	remaining-- // Decrement since we just used one
-	photoConfig.Caption = fmt.Sprintf("✨ Осталось генераций сегодня: %d", remaining)
+	// Record usage once the user has the images
+	if err := b.storage.RecordImageGeneration(ctx, userID, chatID, currentDate, count); err != nil {
+		b.logger.Error().
+			Err(err).
+			Int64("user_id", userID).
+			Msg("Failed to record image generation")
+		// Continue anyway, the images are already sent
+	}
 
-	_, err = b.api.Send(photoConfig)
-	if err != nil {
+	generations := make([]*models.ImageGeneration, 0, len(sent))
+	for i, photo := range sent {
+		if i >= len(result.Images) {
+			break
+		}
+		generation := &models.ImageGeneration{
+			ChatID:         chatID,
+			UserID:         userID,
+			MessageID:      photo.MessageID,
+			Prompt:         prompt,
+			EnhancedPrompt: enhanced,
+			NegativePrompt: opts.NegativePrompt,
+			AspectRatio:    opts.AspectRatio,
+			Model:          result.Model,
+			Seed:           result.Images[i].Seed,
+			SourceFileID:   opts.Source,
+		}
+		if generation.AspectRatio == "" && opts.Source == "" {
+			generation.AspectRatio = images.DefaultAspectRatio
+		}
+		if len(photo.Photo) > 0 {
+			generation.FileID = photo.Photo[len(photo.Photo)-1].FileID // Largest size
+		}
+		generations = append(generations, generation)
+	}
+	if err := b.storage.SaveImageGenerations(ctx, generations); err != nil {
 		b.logger.Error().
 			Err(err).
 			Int64("user_id", userID).
-			Msg("Failed to send generated image")
-		b.sendErrorMessage(chatID, "❌ Не удалось отправить изображение")
-		return
+			Msg("Failed to save image generations")
+		// Continue anyway, the images are already sent
 	}
 
 	b.logger.Info().
 		Int64("user_id", userID).
 		Str("username", username).
 		Str("first_name", firstName).
+		Str("model", result.Model).
+		Int("count", len(result.Images)).
+		Int64("seed", result.Images[0].Seed).
+		Bool("enhanced", enhanced != "").
 		Int("remaining", remaining).
 		Msg("Image generated and sent successfully")
+
+	return nil
+}
+
+// enhanceImagePrompt expands a /draw prompt into a detailed English prompt with Flash, empty if it fails
+// The call counts towards token usage statistics but not against the user's text request limits
+func (b *Bot) enhanceImagePrompt(ctx context.Context, message *tgbotapi.Message, prompt string) string {
+	enhanced, usage, err := b.llmClient.EnhanceImagePrompt(ctx, prompt)
+	if usage.TotalTokens > 0 {
+		if recordErr := b.storage.RecordTokenUsage(ctx, &models.UsageRecord{
+			Component: models.UsageComponentImagePrompt,
+			Model:     models.ModelFlash.String(),
+			ChatID:    message.Chat.ID,
+			UserID:    message.From.ID,
+			Usage:     usage,
+		}); recordErr != nil {
+			b.logger.Warn().Err(recordErr).Msg("Failed to record image prompt token usage")
+		}
+	}
+	if err != nil {
+		b.logger.Warn().
+			Err(err).
+			Int64("user_id", message.From.ID).
+			Msg("Failed to enhance image prompt, using it as written")
+		return ""
+	}
+	return enhanced
+}
+
+// truncateCaption cuts a photo caption to the Telegram limit, which counts UTF-16 code units
+// The end of the caption, the enhanced prompt, is replaced with an ellipsis
+func truncateCaption(caption string) string {
+	if len(utf16.Encode([]rune(caption))) <= maxCaptionLength {
+		return caption
+	}
+
+	length := 0
+	for i, r := range caption {
+		length += len(utf16.Encode([]rune{r}))
+		if length > maxCaptionLength-1 { // One unit for the ellipsis
+			return caption[:i] + "…"
+		}
+	}
+	return caption
+}
+
+// sendDrawnImages sends generated images as a photo, or as a media group if there are several
+// The caption is shown under the first image; returns the sent messages in the order of the images
+func (b *Bot) sendDrawnImages(chatID int64, generated []images.Image, caption string) ([]tgbotapi.Message, error) {
+	if len(generated) == 1 {
+		photoConfig := tgbotapi.NewPhoto(chatID, tgbotapi.FileBytes{
+			Name:  "generated_image.jpg",
+			Bytes: generated[0].Data,
+		})
+		photoConfig.Caption = caption
+
+		message, err := b.api.Send(photoConfig)
+		if err != nil {
+			return nil, err
+		}
+		return []tgbotapi.Message{message}, nil
+	}
+
+	media := make([]interface{}, 0, len(generated))
+	for i, image := range generated {
+		photo := tgbotapi.NewInputMediaPhoto(tgbotapi.FileBytes{
+			Name:  fmt.Sprintf("generated_image_%d.jpg", i+1),
+			Bytes: image.Data,
+		})
+		if i == 0 {
+			photo.Caption = caption
+		}
+		media = append(media, photo)
+	}
+
+	return b.api.SendMediaGroup(tgbotapi.NewMediaGroup(chatID, media))
 }
 
 // extractQuestion extracts the question text from message, removing bot mention
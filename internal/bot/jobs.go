@@ -0,0 +1,141 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/telegram-llm-bot/internal/jobs"
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+// mentionJob is the payload of a queued question from a mention
+type mentionJob struct {
+	Message  *tgbotapi.Message `json:"message"`
+	Question string            `json:"question"`
+}
+
+// drawJob is the payload of a queued /draw request
+type drawJob struct {
+	Message *tgbotapi.Message `json:"message"`
+	Prompt  string            `json:"prompt"`
+	Options drawOptions       `json:"options"`
+
+	// Set by the first attempt that enhanced the prompt, reused by retries
+	EnhancedPrompt string `json:"enhanced_prompt,omitempty"`
+}
+
+// SetJobRunner enables durable processing of mentions and /draw through the job queue
+func (b *Bot) SetJobRunner(runner *jobs.Runner) {
+	b.jobRunner = runner
+	runner.Register(models.JobKindMention, b.runMentionJob)
+	runner.Register(models.JobKindDraw, b.runDrawJob)
+	runner.SetInterruptCallback(b.notifyInterruptedJob)
+	runner.SetDispatcher(b.dispatchJob)
+}
+
+// dispatchJob runs a claimed job in the dispatcher queue of its chat,
+// so requests of a chat are answered in order whether they went through the job queue or not
+func (b *Bot) dispatchJob(job *models.Job, run func()) {
+	if !b.dispatcher.submitFunc(job.ChatID, run) {
+		// The dispatcher is closed on shutdown, the job runs on its own until the shutdown deadline
+		go run()
+	}
+}
+
+// notifyInterruptedJob tells the user that a request interrupted by shutdown will be resumed
+func (b *Bot) notifyInterruptedJob(job *models.Job) {
+	var payload struct {
+		Message *tgbotapi.Message `json:"message"`
+	}
+	if err := json.Unmarshal(job.Payload, &payload); err != nil || payload.Message == nil {
+		return
+	}
+
+	b.sendReply(payload.Message, b.replyTr(payload.Message).T("bot.job_interrupted"))
+}
+
+// submitJob adds a request to the job queue
+// Without a queue, or if the queue is unavailable, the request is processed right away
+func (b *Bot) submitJob(ctx context.Context, kind string, message *tgbotapi.Message, payload interface{}, handler jobs.Handler) {
+	if b.jobRunner != nil {
+		jobID, err := b.jobRunner.Enqueue(ctx, kind, message.Chat.ID, message.MessageID, message.From.ID, payload)
+		if err == nil {
+			b.logger.Debug().
+				Int64("job_id", jobID).
+				Str("kind", kind).
+				Int64("chat_id", message.Chat.ID).
+				Msg("Request queued")
+			return
+		}
+
+		b.logger.Warn().
+			Err(err).
+			Str("kind", kind).
+			Int64("chat_id", message.Chat.ID).
+			Msg("Failed to enqueue request, processing it directly")
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		b.logger.Error().Err(err).Str("kind", kind).Msg("Failed to encode job payload")
+		return
+	}
+
+	// A direct run is the only attempt
+	job := &models.Job{
+		Kind:        kind,
+		ChatID:      message.Chat.ID,
+		UserID:      message.From.ID,
+		Payload:     data,
+		Attempts:    1,
+		MaxAttempts: 1,
+	}
+	if err := handler(ctx, job); err != nil {
+		if b.workCtx.Err() != nil {
+			b.sendReply(message, b.replyTr(message).T("bot.dropped"))
+		}
+		b.logger.Error().
+			Err(err).
+			Str("kind", kind).
+			Int64("chat_id", message.Chat.ID).
+			Msg("Request failed")
+	}
+}
+
+// runMentionJob answers a queued mention
+func (b *Bot) runMentionJob(ctx context.Context, job *models.Job) error {
+	var payload mentionJob
+	if err := json.Unmarshal(job.Payload, &payload); err != nil || payload.Message == nil {
+		return jobs.Permanent(fmt.Errorf("invalid mention job payload: %v", err))
+	}
+
+	return b.answerMention(ctx, job, payload.Message, payload.Question)
+}
+
+// runDrawJob generates an image for a queued /draw request
+func (b *Bot) runDrawJob(ctx context.Context, job *models.Job) error {
+	var payload drawJob
+	if err := json.Unmarshal(job.Payload, &payload); err != nil || payload.Message == nil {
+		return jobs.Permanent(fmt.Errorf("invalid draw job payload: %v", err))
+	}
+
+	return b.drawImage(ctx, job, &payload)
+}
+
+// saveJobPayload stores an updated payload of a running job for its retries
+// A failure only costs the retries the saved work, so it is logged and ignored
+func (b *Bot) saveJobPayload(ctx context.Context, job *models.Job, payload interface{}) {
+	if b.jobRunner == nil {
+		return
+	}
+
+	if err := b.jobRunner.SavePayload(ctx, job, payload); err != nil {
+		b.logger.Warn().
+			Err(err).
+			Int64("job_id", job.ID).
+			Str("kind", job.Kind).
+			Msg("Failed to save job payload")
+	}
+}
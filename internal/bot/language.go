@@ -0,0 +1,137 @@
+package bot
+
+import (
+	"context"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/telegram-llm-bot/internal/i18n"
+)
+
+// tr returns the localizer of replies to the sender of a message
+func (b *Bot) tr(ctx context.Context, message *tgbotapi.Message) *i18n.Localizer {
+	if message.From == nil {
+		return b.chatTr(ctx, message.Chat.ID)
+	}
+	return i18n.New(b.settings.UserLocale(ctx, message.From.ID, message.Chat.ID, message.From.LanguageCode))
+}
+
+// replyTr returns the localizer of replies sent without loading settings: during shutdown or overload
+// Only the Telegram language code of the sender is used
+func (b *Bot) replyTr(message *tgbotapi.Message) *i18n.Localizer {
+	if message.From != nil {
+		if locale := i18n.Match(message.From.LanguageCode); locale != "" {
+			return i18n.New(locale)
+		}
+	}
+	return i18n.New(b.config.DefaultLocale)
+}
+
+// chatTr returns the localizer of messages to a whole chat
+func (b *Bot) chatTr(ctx context.Context, chatID int64) *i18n.Localizer {
+	return i18n.New(b.settings.ChatLocale(ctx, chatID))
+}
+
+// handleLanguageCommand handles /language command - shows or changes the language of the chat
+// Usage: /language [ru|en|default] (changes are admin only)
+func (b *Bot) handleLanguageCommand(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	tr := b.tr(ctx, message)
+
+	if !b.config.IsAllowedChat(chatID) {
+		b.sendMessage(chatID, tr.T("common.chat_not_allowed"))
+		return
+	}
+
+	arg := strings.ToLower(strings.TrimSpace(message.CommandArguments()))
+	if arg == "" {
+		b.showLanguage(ctx, message)
+		return
+	}
+
+	if !b.config.IsAdmin(message.From.ID) {
+		b.sendMessage(chatID, tr.T("language.admin_only"))
+		return
+	}
+
+	locale := arg
+	if isTimezoneReset(arg) {
+		locale = ""
+	}
+	if locale != "" && !i18n.Supported(locale) {
+		b.sendMessage(chatID, languageUsage(tr, "/language", timezoneResetKeyword))
+		return
+	}
+
+	if err := b.settings.SetChatLocale(ctx, chatID, locale, message.From.ID); err != nil {
+		b.sendErrorMessage(chatID, tr.T("language.save_failed"))
+		return
+	}
+
+	tr = b.tr(ctx, message)
+	b.sendMessage(chatID, tr.T("language.chat_updated"))
+	b.showLanguage(ctx, message)
+}
+
+// handleMyLanguageCommand handles /mylanguage command - shows or changes the personal language
+// Usage: /mylanguage [ru|en|reset]
+func (b *Bot) handleMyLanguageCommand(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	tr := b.tr(ctx, message)
+
+	arg := strings.ToLower(strings.TrimSpace(message.CommandArguments()))
+	if arg == "" {
+		b.showLanguage(ctx, message)
+		return
+	}
+
+	locale := arg
+	if isTimezoneReset(arg) {
+		locale = ""
+	}
+	if locale != "" && !i18n.Supported(locale) {
+		b.sendMessage(chatID, languageUsage(tr, "/mylanguage", timezoneResetAlias))
+		return
+	}
+
+	if err := b.settings.SetUserLocale(ctx, message.From.ID, locale); err != nil {
+		b.sendErrorMessage(chatID, tr.T("language.save_failed"))
+		return
+	}
+
+	tr = b.tr(ctx, message)
+	b.sendMessage(chatID, tr.T("language.user_updated"))
+	b.showLanguage(ctx, message)
+}
+
+// showLanguage sends the chat and personal languages with where they come from
+func (b *Bot) showLanguage(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	tr := b.tr(ctx, message)
+
+	chatLocale := b.settings.ChatLocale(ctx, chatID)
+	chatSource := tr.T("language.source_default")
+	if b.settings.ChatLocaleSetting(ctx, chatID) != "" {
+		chatSource = tr.T("language.source_chat")
+	}
+
+	userSource := tr.T("language.source_as_chat")
+	switch {
+	case b.settings.UserLocaleSetting(ctx, message.From.ID) != "":
+		userSource = tr.T("language.source_user")
+	case i18n.Match(message.From.LanguageCode) != "":
+		userSource = tr.T("language.source_telegram")
+	}
+
+	locales := strings.Join(i18n.Locales(), "|")
+	b.sendMessage(chatID, tr.T("language.text",
+		tr.T("language.name."+chatLocale), chatSource,
+		tr.T("language.name."+tr.Locale()), userSource,
+		locales, locales,
+	))
+}
+
+// languageUsage returns the usage hint of a language command
+func languageUsage(tr *i18n.Localizer, command, reset string) string {
+	return tr.T("language.usage", command, strings.Join(i18n.Locales(), "|"), reset)
+}
@@ -145,6 +145,18 @@ func (b *Bot) sendMessageWithContext(ctx context.Context, chatID int64, text str
 	}
 }
 
+// sendReply sends a plain text reply to a message
+func (b *Bot) sendReply(message *tgbotapi.Message, text string) {
+	msg := tgbotapi.NewMessage(message.Chat.ID, text)
+	msg.ReplyToMessageID = message.MessageID
+	if _, err := b.api.Send(msg); err != nil {
+		b.logger.Error().
+			Err(err).
+			Int64("chat_id", message.Chat.ID).
+			Msg("Failed to send reply")
+	}
+}
+
 // sendTypingAction sends typing action to the chat
 func (b *Bot) sendTypingAction(chatID int64) {
 	action := tgbotapi.NewChatAction(chatID, tgbotapi.ChatTyping)
@@ -0,0 +1,77 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/telegram-llm-bot/internal/i18n"
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+// quotaTopConsumers is the number of most active users shown by /quota
+const quotaTopConsumers = 5
+
+// handleQuotaCommand handles /quota command - shows the chat's remaining pool and top consumers
+func (b *Bot) handleQuotaCommand(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	tr := b.tr(ctx, message)
+
+	pool, err := b.limiter.GetChatPoolStats(ctx, chatID)
+	if err != nil {
+		b.logger.Error().
+			Err(err).
+			Int64("chat_id", chatID).
+			Msg("Failed to get chat pool stats")
+		b.sendErrorMessage(chatID, tr.T("quota.failed"))
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(tr.T("quota.title"))
+	sb.WriteString(formatPoolLine(tr, "🤖 Pro", pool.ProUsed, pool.ProPool, pool.UserCap(pool.ProPool)))
+	sb.WriteString(formatPoolLine(tr, "⚡ Flash", pool.FlashUsed, pool.FlashPool, pool.UserCap(pool.FlashPool)))
+
+	if pool.ProPool > 0 || pool.FlashPool > 0 {
+		userPro, userFlash := pool.UserUsage(message.From.ID)
+		sb.WriteString(tr.T("quota.share", pool.MaxUserShare))
+		sb.WriteString(tr.T("quota.yours", userPro, userFlash))
+	}
+
+	if len(pool.Consumers) == 0 {
+		sb.WriteString(tr.T("quota.nobody"))
+	} else {
+		sb.WriteString(tr.T("quota.top"))
+		for i, entry := range pool.Consumers {
+			if i >= quotaTopConsumers {
+				break
+			}
+			sb.WriteString(fmt.Sprintf("%d. %s — Pro %d, Flash %d\n",
+				i+1, formatConsumerName(entry), entry.ProCount, entry.FlashCount))
+		}
+	}
+
+	sb.WriteString(tr.T("quota.resets", pool.ResetsInHours))
+
+	b.sendMessage(chatID, sb.String())
+}
+
+// formatPoolLine formats usage of one model pool
+func formatPoolLine(tr *i18n.Localizer, label string, used, pool, userCap int) string {
+	if pool <= 0 {
+		return tr.T("quota.pool_unlimited", label, used)
+	}
+	return tr.T("quota.pool", label, used, pool, max(pool-used, 0), userCap)
+}
+
+// formatConsumerName returns a display name of a pool consumer
+func formatConsumerName(entry models.ChatUsageEntry) string {
+	if entry.FirstName != "" {
+		return entry.FirstName
+	}
+	if entry.Username != "" {
+		return "@" + entry.Username
+	}
+	return fmt.Sprintf("id%d", entry.UserID)
+}
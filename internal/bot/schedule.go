@@ -0,0 +1,158 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/telegram-llm-bot/internal/i18n"
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+const (
+	// scheduleOffKeyword disables a scheduled job in the chat
+	scheduleOffKeyword = "off"
+
+	// scheduleDefaultKeyword resets a chat schedule to the global one
+	scheduleDefaultKeyword = "default"
+)
+
+// scheduleJobs are the jobs that can be scheduled per chat, in display order
+// Their display names are the "schedule.job.<job>" messages
+var scheduleJobs = []string{
+	models.ScheduleJobSummary,
+	models.ScheduleJobWeekly,
+	models.ScheduleJobMonthly,
+}
+
+// ChatScheduler manages per-chat schedules of scheduled jobs
+type ChatScheduler interface {
+	ValidateSchedule(expr string) error
+	ReloadChat(ctx context.Context, chatID int64) error
+	NextRun(chatID int64, job string) (time.Time, bool)
+	DefaultSchedule(job string) string
+}
+
+// SetChatScheduler sets the scheduler used by /schedule
+func (b *Bot) SetChatScheduler(scheduler ChatScheduler) {
+	b.chatScheduler = scheduler
+}
+
+// handleScheduleCommand handles /schedule command - shows or changes summary and digest schedules of the chat
+// Usage: /schedule [summary|weekly|monthly <cron|off|default>] (changes are admin only)
+func (b *Bot) handleScheduleCommand(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	tr := b.tr(ctx, message)
+
+	if !b.config.IsAllowedChat(chatID) {
+		b.sendMessage(chatID, tr.T("common.chat_not_allowed"))
+		return
+	}
+	if b.chatScheduler == nil {
+		b.sendMessage(chatID, tr.T("schedule.not_configured"))
+		return
+	}
+
+	args := strings.Fields(message.CommandArguments())
+	if len(args) == 0 {
+		b.showSchedule(ctx, chatID, tr)
+		return
+	}
+
+	if !b.config.IsAdmin(message.From.ID) {
+		b.sendMessage(chatID, tr.T("schedule.admin_only"))
+		return
+	}
+
+	job := ""
+	if len(args) >= 2 {
+		job = strings.ToLower(args[0])
+	}
+	if job != models.ScheduleJobSummary && job != models.ScheduleJobWeekly && job != models.ScheduleJobMonthly {
+		b.sendMessage(chatID, tr.T("schedule.usage"))
+		return
+	}
+
+	schedule := &models.ChatSchedule{
+		ChatID:    chatID,
+		Job:       job,
+		Enabled:   true,
+		UpdatedBy: message.From.ID,
+	}
+
+	var err error
+	expr := strings.Join(args[1:], " ")
+	switch strings.ToLower(expr) {
+	case scheduleDefaultKeyword:
+		err = b.storage.DeleteChatSchedule(ctx, chatID, job)
+	case scheduleOffKeyword:
+		schedule.Enabled = false
+		err = b.storage.SetChatSchedule(ctx, schedule)
+	default:
+		if validateErr := b.chatScheduler.ValidateSchedule(expr); validateErr != nil {
+			b.sendMessage(chatID, tr.T("schedule.invalid_cron", validateErr.Error()))
+			return
+		}
+		schedule.CronExpr = expr
+		err = b.storage.SetChatSchedule(ctx, schedule)
+	}
+
+	if err != nil {
+		b.sendErrorMessage(chatID, tr.T("schedule.save_failed"))
+		return
+	}
+
+	if err := b.chatScheduler.ReloadChat(ctx, chatID); err != nil {
+		b.logger.Error().Err(err).Int64("chat_id", chatID).Msg("Failed to reload chat schedule")
+		b.sendErrorMessage(chatID, tr.T("schedule.not_applied"))
+		return
+	}
+
+	b.sendMessage(chatID, tr.T("schedule.updated"))
+	b.showSchedule(ctx, chatID, tr)
+}
+
+// showSchedule sends the schedules of the chat with their next runs
+func (b *Bot) showSchedule(ctx context.Context, chatID int64, tr *i18n.Localizer) {
+	exprs := make(map[string]string, len(scheduleJobs))
+	for _, job := range scheduleJobs {
+		exprs[job] = tr.T("schedule.default", b.chatScheduler.DefaultSchedule(job))
+	}
+
+	schedules, err := b.storage.GetChatSchedules(ctx)
+	if err != nil {
+		b.sendErrorMessage(chatID, tr.T("schedule.failed"))
+		return
+	}
+	for _, schedule := range schedules {
+		if _, ok := exprs[schedule.Job]; !ok || schedule.ChatID != chatID {
+			continue
+		}
+		switch {
+		case !schedule.Enabled:
+			exprs[schedule.Job] = tr.T("schedule.off")
+		case schedule.CronExpr != "":
+			exprs[schedule.Job] = schedule.CronExpr
+		}
+	}
+
+	// Next runs are shown in the chat timezone
+	loc := b.settings.ChatLocation(ctx, chatID)
+
+	var sb strings.Builder
+	sb.WriteString(tr.T("schedule.title", loc.String()))
+	for _, job := range scheduleJobs {
+		sb.WriteString(fmt.Sprintf("%s: `%s`\n", tr.T("schedule.job."+job), exprs[job]))
+		if next, ok := b.chatScheduler.NextRun(chatID, job); ok {
+			sb.WriteString(tr.T("schedule.next_run", next.In(loc).Format("02.01.2006 15:04")))
+		}
+	}
+	sb.WriteString(tr.T("schedule.sync", b.config.SyncCronSchedule))
+	if next, ok := b.chatScheduler.NextRun(chatID, models.ScheduleJobSync); ok {
+		sb.WriteString(tr.T("schedule.next_run", next.In(loc).Format("02.01.2006 15:04")))
+	}
+
+	b.sendMessage(chatID, sb.String())
+}
@@ -0,0 +1,168 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/telegram-llm-bot/internal/i18n"
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+// maxSummarySectionTitleLength limits custom section headings
+const maxSummarySectionTitleLength = 64
+
+// handleSummaryConfigCommand handles /summaryconfig command - shows or changes sections of the daily summary
+// Usage: /summaryconfig [<section> on|off|title [text] | thread <id>|off | pin on|off | reset] (changes are admin only)
+func (b *Bot) handleSummaryConfigCommand(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	tr := b.tr(ctx, message)
+
+	if !b.config.IsAllowedChat(chatID) {
+		b.sendMessage(chatID, tr.T("common.chat_not_allowed"))
+		return
+	}
+
+	args := strings.Fields(message.CommandArguments())
+	if len(args) == 0 {
+		b.showSummaryConfig(ctx, chatID, tr)
+		return
+	}
+
+	if !b.config.IsAdmin(message.From.ID) {
+		b.sendMessage(chatID, tr.T("summaryconfig.admin_only"))
+		return
+	}
+
+	if len(args) == 1 && strings.ToLower(args[0]) == "reset" {
+		if err := b.settings.SetSummaryReport(ctx, chatID, nil, message.From.ID); err != nil {
+			b.sendErrorMessage(chatID, tr.T("summaryconfig.save_failed"))
+			return
+		}
+		b.sendMessage(chatID, tr.T("summaryconfig.reset"))
+		b.showSummaryConfig(ctx, chatID, tr)
+		return
+	}
+
+	if len(args) == 2 {
+		switch strings.ToLower(args[0]) {
+		case "thread", "pin":
+			b.handleSummaryDeliveryConfig(ctx, message, strings.ToLower(args[0]), strings.ToLower(args[1]), tr)
+			return
+		}
+	}
+
+	section := strings.ToLower(args[0])
+	if !slices.Contains(models.SummarySections, section) || len(args) < 2 {
+		b.sendMessage(chatID, tr.T("summaryconfig.usage"))
+		return
+	}
+
+	// Copy the cached report before changing it
+	report := make(models.SummaryReport)
+	for name, config := range b.settings.SummaryReport(ctx, chatID) {
+		report[name] = config
+	}
+	config := report[section]
+
+	switch strings.ToLower(args[1]) {
+	case "on":
+		config.Disabled = false
+	case "off":
+		config.Disabled = true
+	case "title":
+		title := strings.Join(args[2:], " ")
+		if len([]rune(title)) > maxSummarySectionTitleLength {
+			b.sendMessage(chatID, tr.T("summaryconfig.title_too_long", maxSummarySectionTitleLength))
+			return
+		}
+		config.Title = title
+	default:
+		b.sendMessage(chatID, tr.T("summaryconfig.usage"))
+		return
+	}
+
+	if config == (models.SummarySection{}) {
+		delete(report, section)
+	} else {
+		report[section] = config
+	}
+
+	if err := b.settings.SetSummaryReport(ctx, chatID, report, message.From.ID); err != nil {
+		b.sendErrorMessage(chatID, tr.T("summaryconfig.save_failed"))
+		return
+	}
+
+	b.sendMessage(chatID, tr.T("summaryconfig.updated"))
+	b.showSummaryConfig(ctx, chatID, tr)
+}
+
+// showSummaryConfig sends the sections of the daily summary with their state and headings
+// Default headings are in the chat's language, like in the summary itself
+func (b *Bot) showSummaryConfig(ctx context.Context, chatID int64, tr *i18n.Localizer) {
+	report := b.settings.SummaryReport(ctx, chatID)
+	chatTr := b.chatTr(ctx, chatID)
+
+	var sb strings.Builder
+	sb.WriteString(tr.T("summaryconfig.title"))
+	for _, section := range models.SummarySections {
+		state := "✅"
+		if !report.Enabled(section) {
+			state = "❌"
+		}
+		sb.WriteString(fmt.Sprintf("%s `%s` — %s\n",
+			state, section, report.Title(section, chatTr.T("summary.section."+section))))
+	}
+
+	threadID, pin := b.settings.SummaryDelivery(ctx, chatID)
+	sb.WriteString(tr.T("summaryconfig.delivery"))
+	if threadID != 0 {
+		sb.WriteString(tr.T("summaryconfig.thread", threadID))
+	} else {
+		sb.WriteString(tr.T("summaryconfig.thread_general"))
+	}
+	if pin {
+		sb.WriteString(tr.T("summaryconfig.pin_on"))
+	} else {
+		sb.WriteString(tr.T("summaryconfig.pin_off"))
+	}
+
+	sb.WriteString(tr.T("summaryconfig.hint"))
+
+	b.sendMessage(chatID, sb.String())
+}
+
+// handleSummaryDeliveryConfig changes the forum topic or pinning of scheduled summaries
+func (b *Bot) handleSummaryDeliveryConfig(ctx context.Context, message *tgbotapi.Message, option, value string, tr *i18n.Localizer) {
+	chatID := message.Chat.ID
+	threadID, pin := b.settings.SummaryDelivery(ctx, chatID)
+
+	switch {
+	case option == "thread" && value == "off":
+		threadID = 0
+	case option == "thread":
+		// The topic ID is the number after the chat in a link to a topic message: t.me/c/<chat>/<topic>/<message>
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || id <= 0 {
+			b.sendMessage(chatID, tr.T("summaryconfig.invalid_thread"))
+			return
+		}
+		threadID = id
+	case option == "pin" && (value == "on" || value == "off"):
+		pin = value == "on"
+	default:
+		b.sendMessage(chatID, tr.T("summaryconfig.usage"))
+		return
+	}
+
+	if err := b.settings.SetSummaryDelivery(ctx, chatID, threadID, pin, message.From.ID); err != nil {
+		b.sendErrorMessage(chatID, tr.T("summaryconfig.save_failed"))
+		return
+	}
+
+	b.sendMessage(chatID, tr.T("summaryconfig.updated"))
+	b.showSummaryConfig(ctx, chatID, tr)
+}
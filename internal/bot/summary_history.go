@@ -0,0 +1,227 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/telegram-llm-bot/internal/i18n"
+)
+
+const (
+	// summariesCallbackPrefix starts callback data of /summaries buttons, followed by the summary date
+	summariesCallbackPrefix = "summaries:"
+
+	// maxSummaryHistory limits how many past daily summaries can be browsed
+	maxSummaryHistory = 366
+
+	// maxSearchResultTopics is the number of topics shown per found summary
+	maxSearchResultTopics = 3
+
+	// maxSearchResultPreview truncates the text of found summaries without topics
+	maxSearchResultPreview = 200
+)
+
+// handleSummariesCommand handles /summaries command - browses stored daily summaries or searches them
+// Usage: /summaries [query]
+func (b *Bot) handleSummariesCommand(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	tr := b.tr(ctx, message)
+
+	if !b.config.IsAllowedChat(chatID) {
+		b.sendMessage(chatID, tr.T("common.chat_not_allowed"))
+		return
+	}
+
+	if query := strings.TrimSpace(message.CommandArguments()); query != "" {
+		b.searchSummaries(ctx, chatID, query, tr)
+		return
+	}
+
+	text, keyboard, err := b.renderSummaryPage(ctx, chatID, "", tr)
+	if err != nil {
+		b.sendErrorMessage(chatID, tr.T("summaries.load_failed"))
+		return
+	}
+
+	b.sendWithKeyboard(chatID, text, keyboard)
+}
+
+// handleSummariesCallback shows the summary of the date of a pressed /summaries button in place
+func (b *Bot) handleSummariesCallback(ctx context.Context, query *tgbotapi.CallbackQuery, date string) {
+	chatID := query.Message.Chat.ID
+	tr := i18n.New(b.settings.UserLocale(ctx, query.From.ID, chatID, query.From.LanguageCode))
+
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		b.answerCallback(query, "")
+		return
+	}
+
+	text, keyboard, err := b.renderSummaryPage(ctx, chatID, date, tr)
+	if err != nil {
+		b.answerCallback(query, tr.T("summaries.load_failed_short"))
+		return
+	}
+
+	b.answerCallback(query, "")
+	b.editWithKeyboard(chatID, query.Message.MessageID, text, keyboard)
+}
+
+// renderSummaryPage renders the stored summary of the date, the latest one if date is empty,
+// with buttons to the previous and next summaries
+func (b *Bot) renderSummaryPage(ctx context.Context, chatID int64, date string, tr *i18n.Localizer) (string, *tgbotapi.InlineKeyboardMarkup, error) {
+	dates, err := b.storage.GetSummaryDates(ctx, chatID, maxSummaryHistory)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(dates) == 0 {
+		return tr.T("summaries.empty"), nil, nil
+	}
+
+	page := 0
+	for i, d := range dates {
+		if d == date {
+			page = i
+			break
+		}
+	}
+
+	summary, err := b.storage.GetDailySummary(ctx, chatID, dates[page])
+	if err != nil {
+		return "", nil, err
+	}
+	if summary == nil {
+		return tr.T("summaries.not_found", formatSummaryDate(dates[page])), nil, nil
+	}
+
+	// Dates are newest first, so older summaries are further in the list
+	var row []tgbotapi.InlineKeyboardButton
+	if page+1 < len(dates) {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(
+			"◀️ "+formatSummaryDate(dates[page+1]), summariesCallbackPrefix+dates[page+1]))
+	}
+	row = append(row, tgbotapi.NewInlineKeyboardButtonData(
+		fmt.Sprintf("%d/%d", page+1, len(dates)), summariesCallbackPrefix+dates[page]))
+	if page > 0 {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(
+			formatSummaryDate(dates[page-1])+" ▶️", summariesCallbackPrefix+dates[page-1]))
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(row)
+	return summary.SummaryText, &keyboard, nil
+}
+
+// searchSummaries finds the daily summaries that discussed the query, with buttons to open them
+func (b *Bot) searchSummaries(ctx context.Context, chatID int64, query string, tr *i18n.Localizer) {
+	if b.ragSearcher == nil {
+		b.sendMessage(chatID, tr.T("summaries.search_not_configured"))
+		return
+	}
+
+	b.sendTypingAction(chatID)
+
+	summaries, err := b.ragSearcher.SearchSummaries(ctx, query, chatID)
+	if err != nil {
+		b.logger.Error().
+			Err(err).
+			Int64("chat_id", chatID).
+			Msg("Failed to search summaries")
+		b.sendErrorMessage(chatID, tr.T("summaries.search_failed"))
+		return
+	}
+
+	if len(summaries) == 0 {
+		b.sendMessage(chatID, tr.T("summaries.search_empty", query))
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(tr.T("summaries.search_title", query))
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, summary := range summaries {
+		sb.WriteString(tr.T("summaries.search_result", formatSummaryDate(summary.Date), summary.Similarity*100))
+
+		if summary.Data != nil && len(summary.Data.Topics) > 0 {
+			for _, topic := range summary.Data.Topics[:min(len(summary.Data.Topics), maxSearchResultTopics)] {
+				sb.WriteString("• " + topic.String() + "\n")
+			}
+		} else {
+			preview := []rune(summary.SummaryText)
+			if len(preview) > maxSearchResultPreview {
+				preview = append(preview[:maxSearchResultPreview], '…')
+			}
+			sb.WriteString(string(preview) + "\n")
+		}
+
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+			tr.T("summaries.open", formatSummaryDate(summary.Date)), summariesCallbackPrefix+summary.Date)))
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	b.sendWithKeyboard(chatID, sb.String(), &keyboard)
+}
+
+// formatSummaryDate formats a YYYY-MM-DD date as 02.01.2006
+func formatSummaryDate(date string) string {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+	return t.Format("02.01.2006")
+}
+
+// sendWithKeyboard sends a message with inline buttons, as plain text if Markdown fails
+func (b *Bot) sendWithKeyboard(chatID int64, text string, keyboard *tgbotapi.InlineKeyboardMarkup) {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	if keyboard != nil {
+		msg.ReplyMarkup = keyboard
+	}
+
+	if _, err := b.api.Send(msg); err == nil {
+		return
+	}
+
+	msg.ParseMode = ""
+	if _, err := b.api.Send(msg); err != nil {
+		b.logger.Error().
+			Err(err).
+			Int64("chat_id", chatID).
+			Msg("Failed to send message with keyboard")
+	}
+}
+
+// editWithKeyboard replaces the text and inline buttons of a sent message, as plain text if Markdown fails
+func (b *Bot) editWithKeyboard(chatID int64, messageID int, text string, keyboard *tgbotapi.InlineKeyboardMarkup) {
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	edit.ParseMode = "Markdown"
+	edit.ReplyMarkup = keyboard
+
+	_, err := b.api.Send(edit)
+	if err == nil || strings.Contains(err.Error(), "message is not modified") {
+		return
+	}
+
+	edit.ParseMode = ""
+	if _, err := b.api.Send(edit); err != nil && !strings.Contains(err.Error(), "message is not modified") {
+		b.logger.Error().
+			Err(err).
+			Int64("chat_id", chatID).
+			Int("message_id", messageID).
+			Msg("Failed to edit message")
+	}
+}
+
+// answerCallback stops the loading indicator of a pressed button, showing the text if set
+func (b *Bot) answerCallback(query *tgbotapi.CallbackQuery, text string) {
+	if _, err := b.api.Request(tgbotapi.NewCallback(query.ID, text)); err != nil {
+		b.logger.Debug().
+			Err(err).
+			Str("callback_id", query.ID).
+			Msg("Failed to answer callback query")
+	}
+}
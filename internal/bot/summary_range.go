@@ -0,0 +1,196 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/telegram-llm-bot/internal/i18n"
+)
+
+const (
+	// maxRangeSummaryPeriod limits on-demand summaries to keep them affordable
+	maxRangeSummaryPeriod = 7 * 24 * time.Hour
+
+	// catchUpMinAbsence is how long ago the user's last message must be for catch-up.
+	// Messages written right before the request (e.g. "what did I miss?") are not an absence
+	catchUpMinAbsence = 10 * time.Minute
+)
+
+// catchUpArgs are /summary arguments requesting a summary of what the user missed
+var catchUpArgs = map[string]bool{
+	"since my last message": true,
+	"since last message":    true,
+	"since last":            true,
+	"missed":                true,
+}
+
+// summaryRange is a range of messages for an on-demand summary
+type summaryRange struct {
+	since time.Time
+	until time.Time
+	title string
+}
+
+// errInvalidRange means the /summary argument is not a duration or a date
+var errInvalidRange = errors.New("invalid summary range")
+
+// parseSummaryRange parses a duration (3h, 90m, 2d) or a date (2025-10-01) in the chat's timezone
+func parseSummaryRange(arg string, now time.Time, tr *i18n.Localizer) (*summaryRange, error) {
+	if date, err := time.ParseInLocation("2006-01-02", arg, now.Location()); err == nil {
+		if date.After(now) {
+			return nil, fmt.Errorf("date %s is in the future: %w", arg, errInvalidRange)
+		}
+		return &summaryRange{
+			since: date,
+			until: date.AddDate(0, 0, 1),
+			title: tr.T("range.title_date", date.Format("02.01.2006")),
+		}, nil
+	}
+
+	duration, err := parseRangeDuration(arg)
+	if err != nil || duration <= 0 {
+		return nil, errInvalidRange
+	}
+	if duration > maxRangeSummaryPeriod {
+		return nil, fmt.Errorf("duration %s exceeds the limit: %w", arg, errInvalidRange)
+	}
+
+	return &summaryRange{
+		since: now.Add(-duration),
+		until: now,
+		title: tr.T("range.title_last", arg),
+	}, nil
+}
+
+// parseRangeDuration parses a Go duration with an additional day unit, e.g. 2d
+func parseRangeDuration(arg string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(arg, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(arg)
+}
+
+// RangeSummaryCallback generates the summary of chat messages written in [since, until) headed by the title
+// in the locale. It returns the formatted summary, or an empty text if there are no messages in the range
+type RangeSummaryCallback func(ctx context.Context, chatID int64, since, until time.Time, title, locale string) (string, error)
+
+// SetRangeSummaryCallback sets the callback generating on-demand summaries of message ranges
+func (b *Bot) SetRangeSummaryCallback(callback RangeSummaryCallback) {
+	b.rangeSummary = callback
+}
+
+// handleRangeSummary handles /summary with a duration or a date
+func (b *Bot) handleRangeSummary(ctx context.Context, message *tgbotapi.Message, arg string) {
+	chatID := message.Chat.ID
+	tr := b.tr(ctx, message)
+	now := time.Now().In(b.settings.ChatLocation(ctx, chatID))
+
+	requested, err := parseSummaryRange(arg, now, tr)
+	if err != nil {
+		b.sendMessage(chatID, tr.T("summary.usage"))
+		return
+	}
+
+	b.sendMessage(chatID, tr.T("range.generating"))
+
+	text, err := b.summarizeRange(ctx, chatID, requested, tr)
+	if err != nil {
+		b.sendErrorMessage(chatID, tr.T("summary.failed"))
+		return
+	}
+
+	b.sendMessage(chatID, text)
+}
+
+// handleCatchUpSummary summarizes what the user missed since their last message in the chat
+// The summary is sent privately if the user has a chat with the bot, otherwise to the group
+func (b *Bot) handleCatchUpSummary(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	userID := message.From.ID
+	tr := b.tr(ctx, message)
+	loc := b.settings.UserLocation(ctx, userID, chatID)
+	now := time.Now().In(loc)
+
+	if message.Chat.IsPrivate() {
+		b.sendMessage(chatID, tr.T("range.private_chat"))
+		return
+	}
+
+	last, err := b.storage.GetLastUserMessage(ctx, chatID, userID, now.Add(-catchUpMinAbsence))
+	if err != nil {
+		b.sendErrorMessage(chatID, tr.T("range.last_message_failed"))
+		return
+	}
+	if last == nil {
+		b.sendMessage(chatID, tr.T("range.no_messages_from_user"))
+		return
+	}
+
+	since := last.CreatedAt.In(loc)
+	if now.Sub(since) > maxRangeSummaryPeriod {
+		since = now.Add(-maxRangeSummaryPeriod)
+	}
+
+	missed := &summaryRange{
+		since: since,
+		until: now,
+		title: tr.T("range.title_missed", since.Format("02.01 15:04")),
+	}
+
+	text, err := b.summarizeRange(ctx, chatID, missed, tr)
+	if err != nil {
+		b.sendErrorMessage(chatID, tr.T("summary.failed"))
+		return
+	}
+
+	// Users who never started the bot can't receive private messages
+	if err := b.sendMessage(userID, text); err != nil {
+		b.logger.Debug().
+			Err(err).
+			Int64("user_id", userID).
+			Msg("Failed to send catch-up summary privately, sending to the chat")
+		b.sendMessage(chatID, text)
+		return
+	}
+
+	b.sendMessage(chatID, tr.T("range.sent_privately"))
+}
+
+// summarizeRange generates the summary of a range in the language of tr, or a notice if there were no messages
+func (b *Bot) summarizeRange(ctx context.Context, chatID int64, requested *summaryRange, tr *i18n.Localizer) (string, error) {
+	if b.rangeSummary == nil {
+		return "", errors.New("range summaries are not configured")
+	}
+
+	b.logger.Info().
+		Int64("chat_id", chatID).
+		Time("since", requested.since).
+		Time("until", requested.until).
+		Msg("Range summary requested")
+
+	text, err := b.rangeSummary(ctx, chatID, requested.since, requested.until, requested.title, tr.Locale())
+	if err != nil {
+		b.logger.Error().
+			Err(err).
+			Int64("chat_id", chatID).
+			Time("since", requested.since).
+			Time("until", requested.until).
+			Msg("Failed to generate range summary")
+		return "", err
+	}
+
+	if text == "" {
+		return tr.T("range.empty",
+			requested.since.Format("02.01 15:04"), requested.until.Format("02.01 15:04")), nil
+	}
+	return text, nil
+}
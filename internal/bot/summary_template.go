@@ -0,0 +1,122 @@
+package bot
+
+import (
+	"context"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/telegram-llm-bot/internal/i18n"
+)
+
+// SummaryRenderer renders daily summaries with per-chat templates for /summarytemplate
+type SummaryRenderer interface {
+	ValidateSummaryTemplate(text string) error
+	PreviewSummary(ctx context.Context, chatID int64, text string) (string, error)
+	DefaultSummaryTemplate() string
+}
+
+// SetSummaryRenderer sets the renderer used by /summarytemplate
+func (b *Bot) SetSummaryRenderer(renderer SummaryRenderer) {
+	b.summaryRenderer = renderer
+}
+
+// handleSummaryTemplateCommand handles /summarytemplate command - shows, previews or changes the daily summary template
+// Usage: /summarytemplate [preview [template] | set <template> | reset] (preview and changes are admin only)
+// Templates span several lines, so everything after the subcommand is taken as is
+func (b *Bot) handleSummaryTemplateCommand(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	tr := b.tr(ctx, message)
+
+	if !b.config.IsAllowedChat(chatID) {
+		b.sendMessage(chatID, tr.T("common.chat_not_allowed"))
+		return
+	}
+	if b.summaryRenderer == nil {
+		b.sendMessage(chatID, tr.T("summarytemplate.not_configured"))
+		return
+	}
+
+	args := strings.TrimSpace(message.CommandArguments())
+	if args == "" {
+		b.showSummaryTemplate(ctx, chatID, tr)
+		return
+	}
+
+	if !b.config.IsAdmin(message.From.ID) {
+		b.sendMessage(chatID, tr.T("summarytemplate.admin_only"))
+		return
+	}
+
+	subcommand, text := args, ""
+	if i := strings.IndexAny(args, " \n"); i >= 0 {
+		subcommand, text = args[:i], strings.TrimSpace(args[i+1:])
+	}
+
+	switch strings.ToLower(subcommand) {
+	case "preview":
+		b.previewSummaryTemplate(ctx, chatID, text, tr)
+	case "set":
+		if text == "" {
+			b.sendMessage(chatID, tr.T("summarytemplate.usage"))
+			return
+		}
+		if err := b.summaryRenderer.ValidateSummaryTemplate(text); err != nil {
+			b.sendMessage(chatID, tr.T("summarytemplate.invalid", err.Error()))
+			return
+		}
+		if err := b.settings.SetSummaryTemplate(ctx, chatID, text, message.From.ID); err != nil {
+			b.sendErrorMessage(chatID, tr.T("summarytemplate.save_failed"))
+			return
+		}
+		b.sendMessage(chatID, tr.T("summarytemplate.updated"))
+	case "reset":
+		if err := b.settings.SetSummaryTemplate(ctx, chatID, "", message.From.ID); err != nil {
+			b.sendErrorMessage(chatID, tr.T("summarytemplate.save_failed"))
+			return
+		}
+		b.sendMessage(chatID, tr.T("summarytemplate.reset"))
+	default:
+		b.sendMessage(chatID, tr.T("summarytemplate.usage"))
+	}
+}
+
+// showSummaryTemplate sends the template of the chat, or the default one if the chat has none
+func (b *Bot) showSummaryTemplate(ctx context.Context, chatID int64, tr *i18n.Localizer) {
+	text := b.settings.SummaryTemplate(ctx, chatID)
+	heading := tr.T("summarytemplate.custom")
+	if text == "" {
+		text = b.summaryRenderer.DefaultSummaryTemplate()
+		heading = tr.T("summarytemplate.default")
+	}
+
+	b.sendMessage(chatID, heading+"```\n"+text+"\n```\n"+tr.T("summarytemplate.hint"))
+}
+
+// previewSummaryTemplate renders the latest stored summary with the template, the chat's current one if empty
+func (b *Bot) previewSummaryTemplate(ctx context.Context, chatID int64, text string, tr *i18n.Localizer) {
+	if text != "" {
+		if err := b.summaryRenderer.ValidateSummaryTemplate(text); err != nil {
+			b.sendMessage(chatID, tr.T("summarytemplate.invalid", err.Error()))
+			return
+		}
+	}
+
+	b.sendTypingAction(chatID)
+
+	preview, err := b.summaryRenderer.PreviewSummary(ctx, chatID, text)
+	if err != nil {
+		b.logger.Error().
+			Err(err).
+			Int64("chat_id", chatID).
+			Msg("Failed to preview summary template")
+		b.sendErrorMessage(chatID, tr.T("summarytemplate.preview_failed"))
+		return
+	}
+	if preview == "" {
+		b.sendMessage(chatID, tr.T("summarytemplate.no_summary"))
+		return
+	}
+
+	b.sendMessage(chatID, tr.T("summarytemplate.preview"))
+	b.sendMessage(chatID, preview)
+}
@@ -0,0 +1,231 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/telegram-llm-bot/internal/i18n"
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+// tierResetKeyword removes an assignment instead of setting a tier
+const tierResetKeyword = "reset"
+
+// tierSourceKeys are the messages describing where an effective tier comes from
+var tierSourceKeys = map[string]string{
+	models.QuotaSourceUserChat:   "tier.source.user_chat",
+	models.QuotaSourceUserGlobal: "tier.source.user_global",
+	models.QuotaSourceChat:       "tier.source.chat",
+	models.QuotaSourceDefault:    "tier.source.default",
+}
+
+// handleTierCommand handles /tier command - shows caller's tier and all available tiers
+func (b *Bot) handleTierCommand(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	tr := b.tr(ctx, message)
+	limits := b.limiter.GetEffectiveLimits(ctx, message.From.ID, chatID)
+
+	var sb strings.Builder
+	sb.WriteString(tr.T("tier.yours", limits.Tier, tr.T(tierSourceKeys[limits.Source])))
+	sb.WriteString(tr.T("tier.limits", limits.ProDailyLimit, limits.FlashDailyLimit, limits.ImageDailyLimit))
+	if limits.TokenDailyBudget > 0 {
+		sb.WriteString(tr.T("tier.token_budget", limits.TokenDailyBudget))
+	}
+	sb.WriteString("\n")
+
+	tiers, err := b.storage.ListQuotaTiers(ctx)
+	if err != nil {
+		b.logger.Error().Err(err).Msg("Failed to list quota tiers")
+	} else if len(tiers) > 0 {
+		sb.WriteString(tr.T("tier.available"))
+		for _, tier := range tiers {
+			sb.WriteString(tr.T("tier.line",
+				tier.Name,
+				formatTierLimit(tier.ProDailyLimit, b.config.ProDailyLimit),
+				formatTierLimit(tier.FlashDailyLimit, b.config.FlashDailyLimit),
+				formatTierLimit(tier.ImageDailyLimit, b.config.ImageGenerationDailyLimitPerUser),
+			))
+			if tier.Description != "" {
+				sb.WriteString(" (" + tier.Description + ")")
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	if b.config.IsAdmin(message.From.ID) {
+		sb.WriteString(tr.T("tier.admin_help"))
+	}
+
+	b.sendMessage(chatID, sb.String())
+}
+
+// handleSetTierCommand handles /settier command - assigns a tier to a user (admin only)
+// Usage: /settier <tier|reset> [@username|user_id] [global], or as a reply to the user's message
+func (b *Bot) handleSetTierCommand(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	tr := b.tr(ctx, message)
+
+	if !b.config.IsAdmin(message.From.ID) {
+		b.sendMessage(chatID, tr.T("common.admin_only"))
+		return
+	}
+
+	args := strings.Fields(message.CommandArguments())
+	if len(args) == 0 {
+		b.sendMessage(chatID, tr.T("tier.settier_usage"))
+		return
+	}
+
+	tierName := strings.ToLower(args[0])
+	global := false
+	targetArg := ""
+	for _, arg := range args[1:] {
+		if strings.EqualFold(arg, "global") {
+			global = true
+		} else {
+			targetArg = arg
+		}
+	}
+
+	targetID, targetName, err := b.resolveTargetUser(ctx, message, targetArg, tr)
+	if err != nil {
+		b.sendMessage(chatID, "❌ "+err.Error())
+		return
+	}
+
+	scopeChatID := chatID
+	scopeLabel := tr.T("tier.scope_chat")
+	if global {
+		scopeChatID = 0
+		scopeLabel = tr.T("tier.scope_global")
+	}
+
+	if tierName == tierResetKeyword {
+		if err := b.storage.DeleteQuotaAssignment(ctx, scopeChatID, targetID); err != nil {
+			b.sendErrorMessage(chatID, tr.T("tier.reset_failed"))
+			return
+		}
+		b.sendMessage(chatID, tr.T("tier.user_reset", targetName, scopeLabel))
+		return
+	}
+
+	if !b.tierExists(ctx, tierName) {
+		b.sendMessage(chatID, tr.T("tier.not_found", tierName))
+		return
+	}
+
+	if err := b.storage.SetQuotaAssignment(ctx, &models.QuotaAssignment{
+		ChatID:     scopeChatID,
+		UserID:     targetID,
+		Tier:       tierName,
+		AssignedBy: message.From.ID,
+	}); err != nil {
+		b.sendErrorMessage(chatID, tr.T("tier.assign_failed"))
+		return
+	}
+
+	b.sendMessage(chatID, tr.T("tier.user_assigned", targetName, tierName, scopeLabel))
+}
+
+// handleChatTierCommand handles /chattier command - sets default tier for the whole chat (admin only)
+func (b *Bot) handleChatTierCommand(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	tr := b.tr(ctx, message)
+
+	if !b.config.IsAdmin(message.From.ID) {
+		b.sendMessage(chatID, tr.T("common.admin_only"))
+		return
+	}
+
+	tierName := strings.ToLower(strings.TrimSpace(message.CommandArguments()))
+	if tierName == "" {
+		b.sendMessage(chatID, tr.T("tier.chattier_usage"))
+		return
+	}
+
+	if tierName == tierResetKeyword {
+		if err := b.storage.DeleteQuotaAssignment(ctx, chatID, 0); err != nil {
+			b.sendErrorMessage(chatID, tr.T("tier.chat_reset_failed"))
+			return
+		}
+		b.sendMessage(chatID, tr.T("tier.chat_reset"))
+		return
+	}
+
+	if !b.tierExists(ctx, tierName) {
+		b.sendMessage(chatID, tr.T("tier.not_found", tierName))
+		return
+	}
+
+	if err := b.storage.SetQuotaAssignment(ctx, &models.QuotaAssignment{
+		ChatID:     chatID,
+		UserID:     0,
+		Tier:       tierName,
+		AssignedBy: message.From.ID,
+	}); err != nil {
+		b.sendErrorMessage(chatID, tr.T("tier.chat_assign_failed"))
+		return
+	}
+
+	b.sendMessage(chatID, tr.T("tier.chat_assigned", tierName))
+}
+
+// resolveTargetUser determines the user a command refers to: reply target, numeric ID or @username
+// Errors are user-facing, in the language of tr
+func (b *Bot) resolveTargetUser(ctx context.Context, message *tgbotapi.Message, arg string, tr *i18n.Localizer) (int64, string, error) {
+	if arg == "" {
+		if message.ReplyToMessage != nil && message.ReplyToMessage.From != nil {
+			from := message.ReplyToMessage.From
+			name := from.FirstName
+			if from.UserName != "" {
+				name = "@" + from.UserName
+			}
+			return from.ID, name, nil
+		}
+		return 0, "", errors.New(tr.T("user.not_specified"))
+	}
+
+	if id, err := strconv.ParseInt(arg, 10, 64); err == nil {
+		return id, arg, nil
+	}
+
+	if strings.HasPrefix(arg, "@") {
+		id, err := b.storage.FindUserIDByUsername(ctx, message.Chat.ID, arg)
+		if err != nil {
+			b.logger.Error().Err(err).Str("username", arg).Msg("Failed to look up user by username")
+			return 0, "", errors.New(tr.T("user.lookup_failed", arg))
+		}
+		if id == 0 {
+			return 0, "", errors.New(tr.T("user.not_seen", arg))
+		}
+		return id, arg, nil
+	}
+
+	return 0, "", errors.New(tr.T("user.unrecognized", arg))
+}
+
+// tierExists checks whether a tier with the given name is configured
+func (b *Bot) tierExists(ctx context.Context, name string) bool {
+	tiers, err := b.storage.ListQuotaTiers(ctx)
+	if err != nil {
+		b.logger.Error().Err(err).Msg("Failed to list quota tiers")
+		return false
+	}
+	for _, tier := range tiers {
+		if tier.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// formatTierLimit formats a tier limit, falling back to the global default for nil values
+func formatTierLimit(limit *int, fallback int) string {
+	if limit == nil {
+		return strconv.Itoa(fallback)
+	}
+	return strconv.Itoa(*limit)
+}
@@ -0,0 +1,128 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/telegram-llm-bot/internal/settings"
+)
+
+const (
+	// timezoneResetKeyword resets a chat or user timezone to the fallback one
+	timezoneResetKeyword = "default"
+
+	// timezoneResetAlias is an alternative reset keyword for /mytimezone
+	timezoneResetAlias = "reset"
+)
+
+// handleTimezoneCommand handles /timezone command - shows or changes the timezone of the chat
+// Usage: /timezone [<IANA timezone>|default] (changes are admin only)
+func (b *Bot) handleTimezoneCommand(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	tr := b.tr(ctx, message)
+
+	if !b.config.IsAllowedChat(chatID) {
+		b.sendMessage(chatID, tr.T("common.chat_not_allowed"))
+		return
+	}
+
+	arg := strings.TrimSpace(message.CommandArguments())
+	if arg == "" {
+		b.showTimezone(ctx, message)
+		return
+	}
+
+	if !b.config.IsAdmin(message.From.ID) {
+		b.sendMessage(chatID, tr.T("timezone.admin_only"))
+		return
+	}
+
+	timezone := arg
+	if isTimezoneReset(arg) {
+		timezone = ""
+	}
+	if err := settings.ValidateTimezone(timezone); err != nil {
+		b.sendMessage(chatID, tr.T("timezone.usage", "/timezone"))
+		return
+	}
+
+	if err := b.settings.SetChatTimezone(ctx, chatID, timezone, message.From.ID); err != nil {
+		b.sendErrorMessage(chatID, tr.T("timezone.save_failed"))
+		return
+	}
+
+	// Summaries of the chat are scheduled in its timezone
+	if b.chatScheduler != nil {
+		if err := b.chatScheduler.ReloadChat(ctx, chatID); err != nil {
+			b.logger.Error().Err(err).Int64("chat_id", chatID).Msg("Failed to reload chat schedule")
+		}
+	}
+
+	b.sendMessage(chatID, tr.T("timezone.chat_updated"))
+	b.showTimezone(ctx, message)
+}
+
+// handleMyTimezoneCommand handles /mytimezone command - shows or changes the personal timezone
+// The personal timezone only affects how times are shown to the user, daily limits reset in the global timezone
+// Usage: /mytimezone [<IANA timezone>|reset]
+func (b *Bot) handleMyTimezoneCommand(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	userID := message.From.ID
+	tr := b.tr(ctx, message)
+
+	arg := strings.TrimSpace(message.CommandArguments())
+	if arg == "" {
+		b.showTimezone(ctx, message)
+		return
+	}
+
+	timezone := arg
+	if isTimezoneReset(arg) {
+		timezone = ""
+	}
+	if err := settings.ValidateTimezone(timezone); err != nil {
+		b.sendMessage(chatID, tr.T("timezone.usage", "/mytimezone"))
+		return
+	}
+
+	if err := b.settings.SetUserTimezone(ctx, userID, timezone); err != nil {
+		b.sendErrorMessage(chatID, tr.T("timezone.save_failed"))
+		return
+	}
+
+	b.sendMessage(chatID, tr.T("timezone.user_updated"))
+	b.showTimezone(ctx, message)
+}
+
+// showTimezone sends the chat and personal timezones with the current local times
+func (b *Bot) showTimezone(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	tr := b.tr(ctx, message)
+	now := time.Now()
+
+	chatLoc := b.settings.ChatLocation(ctx, chatID)
+	chatSource := tr.T("timezone.source_default")
+	if b.settings.ChatTimezone(ctx, chatID) != "" {
+		chatSource = tr.T("timezone.source_chat")
+	}
+
+	userLoc := b.settings.UserLocation(ctx, message.From.ID, chatID)
+	userSource := tr.T("timezone.source_as_chat")
+	if b.settings.UserTimezone(ctx, message.From.ID) != "" {
+		userSource = tr.T("timezone.source_user")
+	}
+
+	b.sendMessage(chatID, tr.T("timezone.text",
+		chatLoc.String(), chatSource, now.In(chatLoc).Format("15:04"),
+		userLoc.String(), userSource, now.In(userLoc).Format("15:04"),
+		b.settings.LimitLocation().String(),
+	))
+}
+
+// isTimezoneReset reports whether the argument resets a timezone
+func isTimezoneReset(arg string) bool {
+	arg = strings.ToLower(arg)
+	return arg == timezoneResetKeyword || arg == timezoneResetAlias
+}
@@ -0,0 +1,102 @@
+package bot
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// webhookSecretHeader carries the secret token set via setWebhook
+	webhookSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+	// webhookBufferSize is the number of received updates waiting for dispatch
+	webhookBufferSize = 100
+
+	// maxWebhookBodySize limits the size of an incoming update
+	maxWebhookBodySize = 1 << 20
+)
+
+// WebhookHandler returns the HTTP handler receiving updates from Telegram in webhook mode
+// Updates are passed to the same dispatch loop as in polling mode
+func (b *Bot) WebhookHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		// Verify the secret token in constant time
+		secret := r.Header.Get(webhookSecretHeader)
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(b.config.WebhookSecretToken)) != 1 {
+			b.logger.Warn().
+				Str("remote_addr", r.RemoteAddr).
+				Msg("Rejected webhook request with invalid secret token")
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodySize))
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+
+		var update tgbotapi.Update
+		if err := json.Unmarshal(body, &update); err != nil {
+			b.logger.Warn().Err(err).Msg("Failed to decode webhook update")
+			http.Error(w, "invalid update", http.StatusBadRequest)
+			return
+		}
+
+		// Telegram retries the update if we don't answer in time
+		select {
+		case b.webhookUpdates <- update:
+			w.WriteHeader(http.StatusOK)
+		case <-r.Context().Done():
+			http.Error(w, "timeout", http.StatusServiceUnavailable)
+		}
+	})
+}
+
+// setWebhook registers the webhook URL and secret token with Telegram
+// A self-signed certificate is uploaded so Telegram can trust it
+func (b *Bot) setWebhook() error {
+	params := tgbotapi.Params{}
+	params["url"] = b.config.WebhookURL
+	params.AddNonEmpty("secret_token", b.config.WebhookSecretToken)
+
+	var err error
+	if b.config.WebhookSelfSigned && b.config.WebhookTLSCert != "" {
+		_, err = b.api.UploadFiles("setWebhook", params, []tgbotapi.RequestFile{{
+			Name: "certificate",
+			Data: tgbotapi.FilePath(b.config.WebhookTLSCert),
+		}})
+	} else {
+		_, err = b.api.MakeRequest("setWebhook", params)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set webhook: %w", err)
+	}
+
+	b.logger.Info().
+		Str("url", b.config.WebhookURL).
+		Bool("self_signed", b.config.WebhookSelfSigned).
+		Msg("Webhook registered")
+
+	return nil
+}
+
+// deleteWebhook removes the webhook so updates can be received by polling again
+func (b *Bot) deleteWebhook() error {
+	if _, err := b.api.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	b.logger.Info().Msg("Webhook deleted")
+	return nil
+}
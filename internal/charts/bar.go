@@ -0,0 +1,143 @@
+package charts
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strconv"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Chart layout in pixels
+const (
+	chartWidth    = 800
+	chartPadding  = 20
+	titleHeight   = 40
+	barHeight     = 28
+	barGap        = 10
+	labelWidth    = 220
+	valueWidth    = 80
+	fontSize      = 16
+	titleFontSize = 20
+	maxLabelRunes = 24
+)
+
+// Chart colors
+var (
+	backgroundColor = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	textColor       = color.RGBA{R: 0x22, G: 0x22, B: 0x22, A: 0xff}
+	barColor        = color.RGBA{R: 0x3b, G: 0x82, B: 0xf6, A: 0xff}
+)
+
+// Bar represents a single bar of a chart
+type Bar struct {
+	Label string
+	Value float64
+}
+
+// BarChart renders a horizontal bar chart as PNG
+// Go fonts are used because they cover Cyrillic user names
+func BarChart(title string, bars []Bar) ([]byte, error) {
+	if len(bars) == 0 {
+		return nil, fmt.Errorf("no data to draw")
+	}
+
+	textFace, err := newFace(fontSize)
+	if err != nil {
+		return nil, err
+	}
+	defer textFace.Close()
+
+	titleFace, err := newFace(titleFontSize)
+	if err != nil {
+		return nil, err
+	}
+	defer titleFace.Close()
+
+	height := chartPadding*2 + titleHeight + len(bars)*(barHeight+barGap)
+	img := image.NewRGBA(image.Rect(0, 0, chartWidth, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: backgroundColor}, image.Point{}, draw.Src)
+
+	drawText(img, titleFace, title, chartPadding, chartPadding+titleFontSize)
+
+	maxValue := 0.0
+	for _, bar := range bars {
+		maxValue = max(maxValue, bar.Value)
+	}
+
+	barAreaWidth := chartWidth - chartPadding*2 - labelWidth - valueWidth
+	for i, bar := range bars {
+		top := chartPadding + titleHeight + i*(barHeight+barGap)
+		baseline := top + barHeight/2 + fontSize/2 - 2
+
+		drawText(img, textFace, truncateLabel(bar.Label), chartPadding, baseline)
+
+		width := 0
+		if maxValue > 0 {
+			width = int(float64(barAreaWidth) * bar.Value / maxValue)
+		}
+		left := chartPadding + labelWidth
+		rect := image.Rect(left, top, left+max(width, 1), top+barHeight)
+		draw.Draw(img, rect, &image.Uniform{C: barColor}, image.Point{}, draw.Src)
+
+		drawText(img, textFace, formatValue(bar.Value), left+width+8, baseline)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode chart: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// newFace creates a Go Regular font face of the given size
+func newFace(size float64) (font.Face, error) {
+	parsed, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse font: %w", err)
+	}
+
+	face, err := opentype.NewFace(parsed, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create font face: %w", err)
+	}
+
+	return face, nil
+}
+
+// drawText draws a string with its baseline at (x, y)
+func drawText(img draw.Image, face font.Face, text string, x, y int) {
+	drawer := &font.Drawer{
+		Dst:  img,
+		Src:  &image.Uniform{C: textColor},
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	drawer.DrawString(text)
+}
+
+// truncateLabel shortens long labels so they fit the label column
+func truncateLabel(label string) string {
+	runes := []rune(label)
+	if len(runes) <= maxLabelRunes {
+		return label
+	}
+	return string(runes[:maxLabelRunes-1]) + "…"
+}
+
+// formatValue formats a bar value without trailing zeros
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
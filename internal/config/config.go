@@ -2,11 +2,15 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/joho/godotenv"
+	"github.com/robfig/cron/v3"
+	"github.com/telegram-llm-bot/internal/i18n"
 	"github.com/telegram-llm-bot/internal/models"
 )
 
@@ -21,6 +25,31 @@ func Load() (*models.BotConfig, error) {
 		TelegramToken:    getEnv("TELEGRAM_BOT_TOKEN", ""),
 		TelegramUsername: getEnv("TELEGRAM_BOT_USERNAME", ""),
 		AllowedChatIDs:   getEnvInt64List("TELEGRAM_ALLOWED_CHAT_IDS", nil),
+		AdminUserIDs:     getEnvInt64List("TELEGRAM_ADMIN_USER_IDS", nil),
+		TelegramMode:     getEnv("TELEGRAM_MODE", models.TelegramModePolling),
+
+		// Update worker pool
+		WorkerPoolSize:      getEnvInt("WORKER_POOL_SIZE", 8),
+		WorkerQueueSize:     getEnvInt("WORKER_QUEUE_SIZE", 200),
+		WorkerChatQueueSize: getEnvInt("WORKER_CHAT_QUEUE_SIZE", 20),
+
+		// Graceful shutdown
+		ShutdownTimeout: getEnvInt("SHUTDOWN_TIMEOUT", 30),
+
+		// Durable job queue
+		JobsEnabled:             getEnvBool("JOBS_ENABLED", true),
+		JobsConcurrency:         getEnvInt("JOBS_CONCURRENCY", 4),
+		JobsLeaseSeconds:        getEnvInt("JOBS_LEASE_SECONDS", 120),
+		JobsMaxAttempts:         getEnvInt("JOBS_MAX_ATTEMPTS", 3),
+		JobsPollIntervalSeconds: getEnvInt("JOBS_POLL_INTERVAL_SECONDS", 2),
+
+		// Webhook settings
+		WebhookURL:         getEnv("WEBHOOK_URL", ""),
+		WebhookListenAddr:  getEnv("WEBHOOK_LISTEN_ADDR", ":8443"),
+		WebhookSecretToken: getEnv("WEBHOOK_SECRET_TOKEN", ""),
+		WebhookTLSCert:     getEnv("WEBHOOK_TLS_CERT", ""),
+		WebhookTLSKey:      getEnv("WEBHOOK_TLS_KEY", ""),
+		WebhookSelfSigned:  getEnvBool("WEBHOOK_SELF_SIGNED", false),
 
 		// Gemini API settings
 		GeminiAPIKey:  getEnv("GEMINI_API_KEY", ""),
@@ -29,20 +58,46 @@ func Load() (*models.BotConfig, error) {
 		// Hugging Face API settings
 		HuggingFaceToken: getEnv("HUGGINGFACE_TOKEN", ""),
 
+		// Image generation
+		ImageTimeout:           getEnvInt("IMAGE_TIMEOUT", 60),
+		SDWebUIURL:             strings.TrimSuffix(getEnv("SD_WEBUI_URL", ""), "/"),
+		ImagePromptEnhancement: getEnvBool("IMAGE_PROMPT_ENHANCEMENT", true),
+
 		// Supabase settings
 		SupabaseURL:     getEnv("SUPABASE_URL", ""),
 		SupabaseKey:     getEnv("SUPABASE_KEY", ""),
 		SupabaseTimeout: getEnvInt("SUPABASE_TIMEOUT", 10),
 
 		// App settings
-		Timezone:    getEnv("TIMEZONE", "Europe/Moscow"),
-		LogLevel:    getEnv("LOG_LEVEL", "info"),
-		Environment: getEnv("ENVIRONMENT", "production"),
+		Timezone:      getEnv("TIMEZONE", "Europe/Moscow"),
+		DefaultLocale: getEnv("DEFAULT_LOCALE", i18n.DefaultLocale),
+		LogLevel:      getEnv("LOG_LEVEL", "info"),
+		Environment:   getEnv("ENVIRONMENT", "production"),
+
+		// Scheduler
+		SummaryCronSchedule:          getEnv("SUMMARY_CRON_SCHEDULE", "0 7 * * *"),
+		SyncCronSchedule:             getEnv("SYNC_CRON_SCHEDULE", "0 3 * * *"),
+		DigestWeeklyCronSchedule:     getEnv("DIGEST_WEEKLY_CRON_SCHEDULE", "0 10 * * 1"),
+		DigestMonthlyCronSchedule:    getEnv("DIGEST_MONTHLY_CRON_SCHEDULE", "0 10 1 * *"),
+		DigestSampleSize:             getEnvInt("DIGEST_SAMPLE_SIZE", 300),
+		SummaryChunkSize:             getEnvInt("SUMMARY_CHUNK_SIZE", 400),
+		SummaryMapConcurrency:        getEnvInt("SUMMARY_MAP_CONCURRENCY", 4),
+		SummaryTopChatters:           getEnvInt("SUMMARY_TOP_CHATTERS", 3),
+		SummaryDeliveryRetrySchedule: getEnv("SUMMARY_DELIVERY_RETRY_SCHEDULE", "*/15 * * * *"),
+		SummaryDeliveryMaxAttempts:   getEnvInt("SUMMARY_DELIVERY_MAX_ATTEMPTS", 5),
 
 		// Rate limits
 		ProDailyLimit:   getEnvInt("PRO_DAILY_LIMIT", 5),
 		FlashDailyLimit: getEnvInt("FLASH_DAILY_LIMIT", 25),
 
+		// Token budget
+		TokenDailyBudgetPerUser: getEnvInt("TOKEN_DAILY_BUDGET_PER_USER", 0),
+
+		// Chat-wide request pool
+		ChatProDailyPool:     getEnvInt("CHAT_PRO_DAILY_POOL", 0),
+		ChatFlashDailyPool:   getEnvInt("CHAT_FLASH_DAILY_POOL", 0),
+		ChatPoolMaxUserShare: getEnvInt("CHAT_POOL_MAX_USER_SHARE", 50),
+
 		// Image Generation Limits
 		ImageGenerationDailyLimitPerUser: getEnvInt("IMAGE_GENERATION_DAILY_LIMIT_PER_USER", 15),
 		ImageGenerationDailyLimitPerChat: getEnvInt("IMAGE_GENERATION_DAILY_LIMIT_PER_CHAT", 100),
@@ -62,7 +117,21 @@ func Load() (*models.BotConfig, error) {
 			EmbeddingsModel:     getEnv("RAG_EMBEDDINGS_MODEL", "text-embedding-004"),
 			EmbeddingsBatchSize: getEnvInt("RAG_EMBEDDINGS_BATCH_SIZE", 100),
 		},
+
+		// Analytics
+		ChartsEnabled: getEnvBool("CHARTS_ENABLED", true),
+
+		// HTTP server
+		HTTPAddr:       getEnv("HTTP_ADDR", ":8080"),
+		MetricsEnabled: getEnvBool("METRICS_ENABLED", false),
+		HealthEnabled:  getEnvBool("HEALTH_ENABLED", true),
+	}
+
+	imageModels, err := parseImageModels(getEnv("IMAGE_MODELS", defaultImageModels))
+	if err != nil {
+		return nil, fmt.Errorf("config validation failed: IMAGE_MODELS: %w", err)
 	}
+	config.ImageModels = imageModels
 
 	// Validate configuration
 	if err := validate(config); err != nil {
@@ -83,6 +152,9 @@ func validate(cfg *models.BotConfig) error {
 	if len(cfg.AllowedChatIDs) == 0 {
 		return fmt.Errorf("TELEGRAM_ALLOWED_CHAT_IDS is required (comma-separated list of chat IDs)")
 	}
+	if err := validateWebhook(cfg); err != nil {
+		return err
+	}
 	if cfg.GeminiAPIKey == "" {
 		return fmt.Errorf("GEMINI_API_KEY is required")
 	}
@@ -92,6 +164,9 @@ func validate(cfg *models.BotConfig) error {
 	if cfg.SupabaseKey == "" {
 		return fmt.Errorf("SUPABASE_KEY is required")
 	}
+	if !i18n.Supported(cfg.DefaultLocale) {
+		return fmt.Errorf("DEFAULT_LOCALE must be one of %s, got %q", strings.Join(i18n.Locales(), ", "), cfg.DefaultLocale)
+	}
 
 	// Validate positive values
 	if cfg.ProDailyLimit <= 0 {
@@ -100,13 +175,71 @@ func validate(cfg *models.BotConfig) error {
 	if cfg.FlashDailyLimit <= 0 {
 		return fmt.Errorf("FLASH_DAILY_LIMIT must be positive, got %d", cfg.FlashDailyLimit)
 	}
+	if cfg.TokenDailyBudgetPerUser < 0 {
+		return fmt.Errorf("TOKEN_DAILY_BUDGET_PER_USER must not be negative, got %d", cfg.TokenDailyBudgetPerUser)
+	}
+	if cfg.ChatProDailyPool < 0 || cfg.ChatFlashDailyPool < 0 {
+		return fmt.Errorf("CHAT_PRO_DAILY_POOL and CHAT_FLASH_DAILY_POOL must not be negative")
+	}
+	if cfg.ChatPoolMaxUserShare < 1 || cfg.ChatPoolMaxUserShare > 100 {
+		return fmt.Errorf("CHAT_POOL_MAX_USER_SHARE must be between 1 and 100, got %d", cfg.ChatPoolMaxUserShare)
+	}
+	if cfg.WorkerPoolSize <= 0 || cfg.WorkerQueueSize <= 0 || cfg.WorkerChatQueueSize <= 0 {
+		return fmt.Errorf("WORKER_POOL_SIZE, WORKER_QUEUE_SIZE and WORKER_CHAT_QUEUE_SIZE must be positive")
+	}
+	if cfg.ShutdownTimeout <= 0 {
+		return fmt.Errorf("SHUTDOWN_TIMEOUT must be positive, got %d", cfg.ShutdownTimeout)
+	}
+	if cfg.JobsEnabled {
+		if cfg.JobsConcurrency <= 0 || cfg.JobsMaxAttempts <= 0 || cfg.JobsPollIntervalSeconds <= 0 {
+			return fmt.Errorf("JOBS_CONCURRENCY, JOBS_MAX_ATTEMPTS and JOBS_POLL_INTERVAL_SECONDS must be positive")
+		}
+		// The lease must outlive a single Gemini call, it is extended every third of its length
+		if cfg.JobsLeaseSeconds < 30 {
+			return fmt.Errorf("JOBS_LEASE_SECONDS must be at least 30, got %d", cfg.JobsLeaseSeconds)
+		}
+	}
 	if cfg.GeminiTimeout <= 0 {
 		return fmt.Errorf("GEMINI_TIMEOUT must be positive, got %d", cfg.GeminiTimeout)
 	}
+	if cfg.ImageTimeout <= 0 {
+		return fmt.Errorf("IMAGE_TIMEOUT must be positive, got %d", cfg.ImageTimeout)
+	}
 	if cfg.SupabaseTimeout <= 0 {
 		return fmt.Errorf("SUPABASE_TIMEOUT must be positive, got %d", cfg.SupabaseTimeout)
 	}
 
+	if _, err := cron.ParseStandard(cfg.SummaryCronSchedule); err != nil {
+		return fmt.Errorf("SUMMARY_CRON_SCHEDULE is not a valid cron expression: %w", err)
+	}
+	if _, err := cron.ParseStandard(cfg.SyncCronSchedule); err != nil {
+		return fmt.Errorf("SYNC_CRON_SCHEDULE is not a valid cron expression: %w", err)
+	}
+	if _, err := cron.ParseStandard(cfg.DigestWeeklyCronSchedule); err != nil {
+		return fmt.Errorf("DIGEST_WEEKLY_CRON_SCHEDULE is not a valid cron expression: %w", err)
+	}
+	if _, err := cron.ParseStandard(cfg.DigestMonthlyCronSchedule); err != nil {
+		return fmt.Errorf("DIGEST_MONTHLY_CRON_SCHEDULE is not a valid cron expression: %w", err)
+	}
+	if _, err := cron.ParseStandard(cfg.SummaryDeliveryRetrySchedule); err != nil {
+		return fmt.Errorf("SUMMARY_DELIVERY_RETRY_SCHEDULE is not a valid cron expression: %w", err)
+	}
+	if cfg.SummaryDeliveryMaxAttempts <= 0 {
+		return fmt.Errorf("SUMMARY_DELIVERY_MAX_ATTEMPTS must be positive, got %d", cfg.SummaryDeliveryMaxAttempts)
+	}
+	if cfg.DigestSampleSize < 0 {
+		return fmt.Errorf("DIGEST_SAMPLE_SIZE must not be negative, got %d", cfg.DigestSampleSize)
+	}
+	if cfg.SummaryChunkSize < 50 {
+		return fmt.Errorf("SUMMARY_CHUNK_SIZE must be at least 50, got %d", cfg.SummaryChunkSize)
+	}
+	if cfg.SummaryMapConcurrency <= 0 {
+		return fmt.Errorf("SUMMARY_MAP_CONCURRENCY must be positive, got %d", cfg.SummaryMapConcurrency)
+	}
+	if cfg.SummaryTopChatters <= 0 {
+		return fmt.Errorf("SUMMARY_TOP_CHATTERS must be positive, got %d", cfg.SummaryTopChatters)
+	}
+
 	// Validate log level
 	validLogLevels := map[string]bool{
 		"debug": true,
@@ -121,6 +254,90 @@ func validate(cfg *models.BotConfig) error {
 	return nil
 }
 
+// validateWebhook checks Telegram mode and webhook settings
+func validateWebhook(cfg *models.BotConfig) error {
+	switch cfg.TelegramMode {
+	case models.TelegramModePolling:
+		return nil
+	case models.TelegramModeWebhook:
+	default:
+		return fmt.Errorf("TELEGRAM_MODE must be one of: polling, webhook; got %s", cfg.TelegramMode)
+	}
+
+	webhookURL, err := url.Parse(cfg.WebhookURL)
+	if cfg.WebhookURL == "" || err != nil || webhookURL.Scheme != "https" || webhookURL.Host == "" {
+		return fmt.Errorf("WEBHOOK_URL must be a valid https URL in webhook mode")
+	}
+	// Telegram allows 1-256 characters A-Z, a-z, 0-9, _ and -
+	if len(cfg.WebhookSecretToken) < 16 || len(cfg.WebhookSecretToken) > 256 {
+		return fmt.Errorf("WEBHOOK_SECRET_TOKEN must be 16-256 characters in webhook mode")
+	}
+	for _, r := range cfg.WebhookSecretToken {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return fmt.Errorf("WEBHOOK_SECRET_TOKEN may only contain A-Z, a-z, 0-9, _ and -")
+		}
+	}
+	if (cfg.WebhookTLSCert == "") != (cfg.WebhookTLSKey == "") {
+		return fmt.Errorf("WEBHOOK_TLS_CERT and WEBHOOK_TLS_KEY must be set together")
+	}
+	if cfg.WebhookSelfSigned && cfg.WebhookTLSCert == "" {
+		return fmt.Errorf("WEBHOOK_SELF_SIGNED requires WEBHOOK_TLS_CERT")
+	}
+	if cfg.WebhookListenAddr == cfg.HTTPAddr && cfg.WebhookTLSCert != "" {
+		return fmt.Errorf("WEBHOOK_LISTEN_ADDR must differ from HTTP_ADDR when TLS is enabled")
+	}
+
+	return nil
+}
+
+// defaultImageModels is FLUX.1-schnell, free and fast on the Hugging Face Inference API
+const defaultImageModels = "flux-schnell=huggingface:black-forest-labs/FLUX.1-schnell"
+
+// parseImageModels parses a comma-separated list of name=provider:model image models
+func parseImageModels(value string) ([]models.ImageModel, error) {
+	var result []models.ImageModel
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, spec, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q is not name=provider:model", part)
+		}
+		provider, id, _ := strings.Cut(spec, ":")
+
+		model := models.ImageModel{
+			Name:     strings.ToLower(strings.TrimSpace(name)),
+			Provider: strings.TrimSpace(provider),
+			ID:       strings.TrimSpace(id),
+		}
+		if model.Name == "" || strings.ContainsAny(model.Name, " \t") {
+			return nil, fmt.Errorf("%q has an invalid name", part)
+		}
+		if !slices.Contains(models.ImageProviders, model.Provider) {
+			return nil, fmt.Errorf("%q has unknown provider, must be one of %s", part, strings.Join(models.ImageProviders, ", "))
+		}
+		if model.ID == "" && model.Provider != models.ImageProviderSDWebUI {
+			return nil, fmt.Errorf("%q has no model", part)
+		}
+		if seen[model.Name] {
+			return nil, fmt.Errorf("model name %s is used twice", model.Name)
+		}
+		seen[model.Name] = true
+
+		result = append(result, model)
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("at least one model is required")
+	}
+	return result, nil
+}
+
 // getEnv retrieves environment variable or returns default value
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
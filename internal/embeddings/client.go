@@ -5,12 +5,19 @@ import (
 	"fmt"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/generative-ai-go/genai"
 	"github.com/rs/zerolog"
+	"github.com/telegram-llm-bot/internal/metrics"
+	"github.com/telegram-llm-bot/internal/models"
 	"google.golang.org/api/option"
 )
 
+// charsPerToken is a rough average used to estimate embedding tokens,
+// since the embeddings API does not report usage metadata
+const charsPerToken = 4
+
 // Client represents a Gemini Embeddings client
 type Client struct {
 	apiKey      string
@@ -20,6 +27,8 @@ type Client struct {
 	logger      zerolog.Logger
 	genaiClient *genai.Client
 	mu          sync.Mutex
+
+	usageCallback models.UsageCallback
 }
 
 // NewClient creates a new Gemini Embeddings client
@@ -52,6 +61,11 @@ func (c *Client) getClient(ctx context.Context) (*genai.Client, error) {
 	return c.genaiClient, nil
 }
 
+// SetUsageCallback sets the callback that receives estimated token usage of embedding batches
+func (c *Client) SetUsageCallback(callback models.UsageCallback) {
+	c.usageCallback = callback
+}
+
 // Close closes the embeddings client and releases resources
 func (c *Client) Close() error {
 	c.mu.Lock()
@@ -176,6 +190,7 @@ func (c *Client) processBatch(ctx context.Context, em *genai.EmbeddingModel, tex
 		// Generate embeddings
 		result, err := em.BatchEmbedContents(ctx, batch)
 		if err != nil {
+			metrics.EmbeddingBatches.WithLabelValues(metrics.StatusError).Inc()
 			lastErr = err
 			c.logger.Error().
 				Err(err).
@@ -206,12 +221,38 @@ func (c *Client) processBatch(ctx context.Context, em *genai.EmbeddingModel, tex
 			Dur("duration", time.Since(startTime)).
 			Msg("Embeddings generated successfully")
 
+		metrics.EmbeddingBatches.WithLabelValues(metrics.StatusSuccess).Inc()
+		metrics.EmbeddingBatchSize.Observe(float64(len(texts)))
+		c.recordUsage(ctx, texts)
+
 		return embeddings, nil
 	}
 
 	return nil, fmt.Errorf("failed to generate embeddings after %d attempts: %w", maxRetries+1, lastErr)
 }
 
+// recordUsage reports estimated token usage of a processed batch to the usage callback
+func (c *Client) recordUsage(ctx context.Context, texts []string) {
+	if c.usageCallback == nil {
+		return
+	}
+
+	chars := 0
+	for _, text := range texts {
+		chars += utf8.RuneCountInString(text)
+	}
+	tokens := (chars + charsPerToken - 1) / charsPerToken
+
+	usage := models.NewTokenUsage(c.model, tokens, 0, tokens)
+	usage.Estimated = true
+
+	c.usageCallback(ctx, &models.UsageRecord{
+		Component: models.UsageComponentEmbeddings,
+		Model:     c.model,
+		Usage:     usage,
+	})
+}
+
 // GetDimension returns the dimension of embeddings for this model
 // text-embedding-004 produces 768-dimensional vectors
 func (c *Client) GetDimension() int {
@@ -0,0 +1,152 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Check statuses. Degraded components are reported but do not fail the probe
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+	StatusFail     = "fail"
+)
+
+// checkTimeout limits the duration of a single check
+const checkTimeout = 5 * time.Second
+
+// Result represents the outcome of a single health check
+type Result struct {
+	Status  string                 `json:"status"`
+	Error   string                 `json:"error,omitempty"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// CheckFunc reports the health of a component
+type CheckFunc func(ctx context.Context) Result
+
+// Report represents the response of a health endpoint
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Registry holds liveness and readiness checks of the application
+type Registry struct {
+	mu        sync.RWMutex
+	liveness  map[string]CheckFunc
+	readiness map[string]CheckFunc
+}
+
+// NewRegistry creates an empty health check registry
+func NewRegistry() *Registry {
+	return &Registry{
+		liveness:  make(map[string]CheckFunc),
+		readiness: make(map[string]CheckFunc),
+	}
+}
+
+// AddLiveness registers a check used by /healthz
+// Liveness checks should only fail when the process must be restarted
+func (r *Registry) AddLiveness(name string, check CheckFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.liveness[name] = check
+}
+
+// AddReadiness registers a check used by /readyz
+func (r *Registry) AddReadiness(name string, check CheckFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.readiness[name] = check
+}
+
+// LivenessHandler returns the /healthz handler
+func (r *Registry) LivenessHandler() http.Handler {
+	return r.handler(func() map[string]CheckFunc { return r.liveness })
+}
+
+// ReadinessHandler returns the /readyz handler
+// Readiness includes liveness checks, since a dead component is never ready
+func (r *Registry) ReadinessHandler() http.Handler {
+	return r.handler(func() map[string]CheckFunc {
+		checks := make(map[string]CheckFunc, len(r.liveness)+len(r.readiness))
+		for name, check := range r.liveness {
+			checks[name] = check
+		}
+		for name, check := range r.readiness {
+			checks[name] = check
+		}
+		return checks
+	})
+}
+
+// handler runs checks concurrently and writes a JSON report
+// Responds with 503 if any check failed
+func (r *Registry) handler(checks func() map[string]CheckFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.mu.RLock()
+		selected := checks()
+		r.mu.RUnlock()
+
+		report := r.run(req.Context(), selected)
+
+		w.Header().Set("Content-Type", "application/json")
+		if report.Status == StatusFail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(report)
+	})
+}
+
+// run executes checks and aggregates their statuses
+func (r *Registry) run(ctx context.Context, checks map[string]CheckFunc) Report {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := make([]Result, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, check CheckFunc) {
+			defer wg.Done()
+			results[i] = check(ctx)
+		}(i, checks[name])
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(names))}
+	for i, name := range names {
+		result := results[i]
+		report.Checks[name] = result
+
+		switch result.Status {
+		case StatusFail:
+			report.Status = StatusFail
+		case StatusDegraded:
+			if report.Status == StatusOK {
+				report.Status = StatusDegraded
+			}
+		}
+	}
+
+	return report
+}
+
+// FromError converts an error into a check result
+func FromError(err error) Result {
+	if err != nil {
+		return Result{Status: StatusFail, Error: err.Error()}
+	}
+	return Result{Status: StatusOK}
+}
@@ -0,0 +1,88 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// shutdownTimeout limits graceful shutdown of the HTTP server
+const shutdownTimeout = 5 * time.Second
+
+// Server is an HTTP server for operational endpoints (metrics, health checks)
+type Server struct {
+	addr     string
+	mux      *http.ServeMux
+	certFile string
+	keyFile  string
+	logger   zerolog.Logger
+}
+
+// New creates a new HTTP server listening on addr
+func New(addr string, logger zerolog.Logger) *Server {
+	return &Server{
+		addr:   addr,
+		mux:    http.NewServeMux(),
+		logger: logger.With().Str("component", "httpserver").Logger(),
+	}
+}
+
+// EnableTLS makes the server serve HTTPS with the given certificate and key files
+func (s *Server) EnableTLS(certFile, keyFile string) {
+	s.certFile = certFile
+	s.keyFile = keyFile
+}
+
+// Handle registers a handler for the given path
+func (s *Server) Handle(path string, handler http.Handler) {
+	s.mux.Handle(path, handler)
+	s.logger.Debug().Str("path", path).Msg("HTTP handler registered")
+}
+
+// Start serves HTTP requests until the context is cancelled
+func (s *Server) Start(ctx context.Context) error {
+	server := &http.Server{
+		Addr:              s.addr,
+		Handler:           s.mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		s.logger.Info().
+			Str("addr", s.addr).
+			Bool("tls", s.certFile != "").
+			Msg("HTTP server started")
+
+		var err error
+		if s.certFile != "" {
+			err = server.ListenAndServeTLS(s.certFile, s.keyFile)
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errChan <- fmt.Errorf("http server failed: %w", err)
+		}
+		close(errChan)
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down http server: %w", err)
+	}
+
+	s.logger.Info().Msg("HTTP server stopped")
+	return nil
+}
@@ -0,0 +1,354 @@
+package i18n
+
+// en is the English catalog
+var en = Catalog{
+	Messages: map[string]string{
+		// Dates
+		"date.day_month": "%[2]s %[1]d",
+		"date.month.1":   "January",
+		"date.month.2":   "February",
+		"date.month.3":   "March",
+		"date.month.4":   "April",
+		"date.month.5":   "May",
+		"date.month.6":   "June",
+		"date.month.7":   "July",
+		"date.month.8":   "August",
+		"date.month.9":   "September",
+		"date.month.10":  "October",
+		"date.month.11":  "November",
+		"date.month.12":  "December",
+
+		// Summary prompts
+		"prompt.topic_rules":         "IMPORTANT:\n1. The topic title is short (5-7 words at most), the emoji is given separately\n2. The description is one sentence about what was discussed and what was agreed\n3. Participants are the names of the most active people in the topic, as written in the messages\n4. Message IDs are 1-3 most representative messages of the topic from the square brackets\n",
+		"prompt.quote_rule":          "Also choose the quote of the day (quote) - the funniest, sharpest or most memorable message: its ID from the square brackets and a short comment on why.\n",
+		"prompt.summary":             "Analyze the following group chat messages for %s and identify the 5-7 main discussion topics.\n\n",
+		"prompt.summary_rules":       "5. Focus on the most discussed and important topics\n6. If there are fewer than 5 topics, list only those\n\n",
+		"prompt.messages":            "Messages (message ID and time in square brackets):\n\n",
+		"prompt.language":            "Write titles, descriptions and comments in English.\n",
+		"prompt.range_label":         "the period from %s to %s",
+		"prompt.chunk":               "This is part %d of %d of a group chat conversation for %s (from %s to %s).\nIdentify the 3-5 main discussion topics of this part.\n\n",
+		"prompt.reduce":              "A group chat conversation for %s (%s) was split into parts by time.\nBelow are the topics of each part with its message count, participants and IDs of representative messages.\n\n",
+		"prompt.reduce_chunk":        "--- %s–%s (%s)\n",
+		"prompt.reduce_topic":        "%s: %s (participants: %s; messages: %v)\n",
+		"prompt.reduce_merge":        "\nMerge them into the 5-7 main topics of the whole period. Merge similar topics from different parts, put topics discussed longer and more actively first.\nTake participants and message IDs from the merged topics.\n\n",
+		"prompt.reduce_quotes":       "\nCandidates for the quote of the day from different parts:\n%s\nChoose one quote of the day (quote) from them with its ID and a short comment.\n",
+		"prompt.digest_period.week":  "a week",
+		"prompt.digest_period.month": "a month",
+		"prompt.digest":              "You are analyzing a group chat conversation over %s (from %s to %s).\nBelow are short results of individual days and a random sample of messages of this period.\n\n",
+		"prompt.digest_days":         "DAILY RESULTS:\n",
+		"prompt.digest_day":          "--- %s (%s)\n",
+		"prompt.digest_sample":       "MESSAGE SAMPLE (message ID and time in square brackets):\n",
+		"prompt.digest_previous":     "TOPICS OF THE PREVIOUS PERIOD:\n",
+		"prompt.digest_task":         "Identify the 5-7 main topics of the period.\n",
+		"prompt.digest_trends":       "Compare with the topics of the previous period: which topics are discussed more (rising, up to 3) and which are almost no longer discussed (fading, up to 3).\n",
+		"prompt.digest_ids":          "Only use message IDs from the message sample.\n",
+		"schema.quote.message_id":    "ID of the quoted message",
+		"schema.quote.comment":       "A short comment on why this message was chosen",
+		"schema.topic.emoji":         "One emoji matching the topic",
+		"schema.topic.title":         "Short topic title, 5-7 words",
+		"schema.topic.description":   "One sentence about the essence of the discussion",
+		"schema.topic.participants":  "Names of the key participants of the discussion",
+		"schema.topic.message_ids":   "IDs of representative messages of the topic",
+		"schema.digest.topics":       "Main topics of the period",
+		"schema.digest.rising":       "Topics discussed more than before",
+		"schema.digest.fading":       "Topics almost no longer discussed",
+
+		// Scheduled reports
+		"summary.title":            "📊 *Summary for %s*\n",
+		"summary.section.topics":   "Main topics",
+		"summary.section.stats":    "Statistics",
+		"summary.section.activity": "Activity by hour",
+		"summary.section.top":      "Most active members",
+		"summary.section.quote":    "Quote of the day",
+		"summary.section.replied":  "Most discussed message",
+		"summary.no_topics":        "There were no active discussions that day\n",
+		"summary.stats":            "💬 %s from %s, %s\n",
+		"summary.activity_peak":    "Peak: %02d:00–%02d:00 (%s)\n",
+		"report.no_discussions":    "*There were no active discussions*\n",
+		"report.activity":          "\n*Activity:* %s from %s",
+		"report.top":               "*Most active:* %s\n",
+		"digest.title_month":       "🗓 *Digest for %s %d*\n\n",
+		"digest.title_week":        "🗓 *Digest for the week %s – %s*\n\n",
+		"digest.topics":            "*Main topics:*\n\n",
+		"digest.rising":            "\n*Gaining momentum:*\n",
+		"digest.fading":            "\n*Fading:*\n",
+		"digest.change":            " (%+d%% from the previous period)",
+		"digest.and_more":          "and %d more",
+		"digest.new_users":         "*New participants:* %s\n",
+		"digest.chart_title":       "Messages by day",
+		"date.month_standalone.1":  "January",
+		"date.month_standalone.2":  "February",
+		"date.month_standalone.3":  "March",
+		"date.month_standalone.4":  "April",
+		"date.month_standalone.5":  "May",
+		"date.month_standalone.6":  "June",
+		"date.month_standalone.7":  "July",
+		"date.month_standalone.8":  "August",
+		"date.month_standalone.9":  "September",
+		"date.month_standalone.10": "October",
+		"date.month_standalone.11": "November",
+		"date.month_standalone.12": "December",
+		"date.weekday_short.0":     "Su",
+		"date.weekday_short.1":     "Mo",
+		"date.weekday_short.2":     "Tu",
+		"date.weekday_short.3":     "We",
+		"date.weekday_short.4":     "Th",
+		"date.weekday_short.5":     "Fr",
+		"date.weekday_short.6":     "Sa",
+
+		// Rate limits, chat history and answers
+		"limit.blocked":        "🚫 AI requests are not available to you. Contact the bot administrator.",
+		"limit.tokens":         "🚫 You have used up your daily token budget (%d/%d).\n\nLimits reset in %d h.",
+		"limit.daily":          "🚫 You have used up your daily request limit.\n\nLimits reset in %d h.\nPro: %d/%d\nFlash: %d/%d",
+		"limit.pool":           "🚫 %s\n\nLimits reset in %d h.\nDetails: /quota",
+		"limit.pool_exhausted": "The chat-wide %s limit is used up (%d/%d).",
+		"limit.pool_share":     "You have used up your share of the chat-wide %s limit (%d/%d, at most %d%%).",
+		"rag.header":           "RELEVANT INFORMATION FROM THE CHAT HISTORY:\n\n",
+		"rag.entry":            "%d. %s (%s, relevance: %s): \"%s\"\n",
+		"rag.truncated":        "\n[... %d more relevant messages are not shown due to the length limit]\n",
+		"time.just_now":        "just now",
+		"llm.prompt":           "Answer the following question. IMPORTANT: your answer must be at most 3500 characters. This is a strict limit for compatibility with Telegram.\n\nQuestion: %s",
+		"llm.prompt_rag":       "You are a helpful AI assistant. You have access to the chat history.\n\n%s\n\nUSER QUESTION:\n%s\n\nAnswer the question using information from the chat history if it is relevant. If the information from the history is incomplete or outdated, complete it with your own knowledge.\n\nIMPORTANT: your answer must be at most 3500 characters. This is a strict limit for compatibility with Telegram.",
+		"llm.truncated":        "\n\n...[the answer was cut off for exceeding the limit]",
+
+		// Commands and mentions
+		"common.chat_not_allowed":   "❌ This command is only available in allowed chats.",
+		"common.unknown_command":    "❓ Unknown command. Use /help for the list of commands.",
+		"common.limit_check_failed": "❌ Failed to check limits",
+		"stats.failed":              "❌ Failed to get statistics",
+		"stats.tokens":              "🔢 *Tokens today:* %d\n",
+		"stats.tokens_budget":       "🔢 *Tokens today:* %d/%d\n",
+		"stats.text":                "📊 *Statistics for %s*\n🏷 Tier: %s\n\n🤖 *Gemini Pro (Thinking):*\n   Used: %d/%d\n   Left: %d\n\n⚡ *Gemini Flash:*\n   Used: %d/%d\n   Left: %d\n\n%s📈 *Total requests:* %d\n⏰ *Limits reset in:* %d h.",
+		"help.text":                 "👋 *Hi! I'm a bot with an AI assistant*\n\n*How to use:*\nJust mention me (@%s) and ask a question!\n\n*Available commands:*\n/stats - Show your statistics\n/draw [--model <model>] [--ar 16:9] [--no text] [--seed 42] [--n 4] [--raw] <prompt> - Generate an image from a description, as a reply to a photo — edit it\n/vary [--n 4] - As a reply to a photo: variations of the photo\n/summary [week|month] - Yesterday's summary or the digest of the last week/month\n/summary <3h|2025-10-01> - Summary of the last hours or of a date\n/summary since my last message - What you missed since your last message\n/summaries [query] - Browse past summaries or search when something was discussed\n/sync - Run RAG synchronization (message indexing)\n/tier - Show your tier and the available tiers\n/quota - Chat-wide limit and the most active participants\n/top [day|week|month] [chart] - Most active participants\n/usage [day|week|month] [chart] - Bot usage statistics\n/schedule - Schedule of summaries, digests and synchronization\n/summaryconfig - Sections, topic and pinning of summaries\n/summarytemplate - Layout template of summaries\n/timezone - Chat timezone\n/mytimezone [zone] - Your personal timezone\n/language - Chat language\n/mylanguage [ru|en] - Your personal reply language\n/help - Show this message\n\n*Your limits (tier %s):*\n• Gemini Pro (thinking model): %d requests/day\n• Gemini Flash (fast model): %d requests/day\n• Image generation: %d generations/day\n\nPro model requests are used first, then Flash.\nLimits reset at midnight in the `%s` timezone.\n\n*Examples:*\n• /draw a beautiful sunset over the ocean\n• /draw a cat in space in cyberpunk style\n• /draw --ar 16:9 --no text,people --n 4 mountains at dawn\n\n*Scheduled tasks:*\n• RAG synchronization (embeddings indexing)\n• Daily summary\n• Weekly and monthly digest\nRun times: /schedule",
+		"summary.usage":             "Usage: /summary [week|month|<3h|90m|2d>|<2025-10-01>|since my last message]",
+		"summary.generating_week":   "⏳ Generating the digest of the last week...",
+		"summary.generating_month":  "⏳ Generating the digest of the last month...",
+		"summary.generating_day":    "⏳ Generating yesterday's summary...",
+		"summary.failed":            "❌ Failed to generate the summary. Try again later.",
+		"summary.not_configured":    "❌ Summaries are not configured.",
+		"sync.starting":             "🔄 Starting RAG synchronization...\n\nThis may take a few minutes.",
+		"sync.failed":               "❌ Synchronization failed. Try again later.",
+		"sync.done":                 "✅ Synchronization completed successfully!",
+		"sync.not_configured":       "❌ Synchronization is not configured.",
+		"mention.empty":             "❓ Please ask a question after the mention.",
+		"mention.truncated":         "⚠️ Your question was cut to %d characters. Please keep questions shorter.",
+		"mention.failed":            "❌ Sorry, an error occurred while processing your request. Try again later.",
+		"mention.footer":            "%s\n\n---\n%s _Model: %s | Time: %dms_",
+		"draw.empty":                "Describe the image. Example: /draw a beautiful sunset over the ocean",
+		"draw.too_long":             "⚠️ The description is too long. 500 characters at most.",
+		"draw.limit":                "❌ You have used up your daily generation limit (%d/day). Try again tomorrow.",
+		"draw.generating":           "🎨 Generating the image...",
+		"draw.unavailable":          "⚠️ The generation service is temporarily unavailable. Try again later.",
+		"draw.caption":              "🖼 %s\n🔁 %s\n✨ Generations left today: %d",
+		"draw.send_failed":          "❌ Failed to send the image",
+
+		// Languages
+		"language.name.ru":         "Russian",
+		"language.name.en":         "English",
+		"language.admin_only":      "❌ Only bot administrators can change the chat language.",
+		"language.save_failed":     "❌ Failed to save the language",
+		"language.chat_updated":    "✅ The chat language was updated.",
+		"language.user_updated":    "✅ Your language was updated.",
+		"language.source_default":  "default",
+		"language.source_chat":     "set for the chat",
+		"language.source_user":     "personal",
+		"language.source_telegram": "from Telegram settings",
+		"language.source_as_chat":  "same as the chat",
+		"language.text":            "🌐 *Language*\n\nChat: %s (%s)\nYours: %s (%s)\n\nSummaries and digests are posted in the chat language.\nThe bot replies to you in your language: answers to questions, commands and limit messages. Without a personal setting, the language of your Telegram is used.\n\nChange: /language <%s|default> (admins), /mylanguage <%s|reset>",
+		"language.usage":           "❌ Unknown language.\n\nUsage: %s <%s|%s>",
+
+		// Summary settings and on-demand summaries
+		"summaryconfig.usage":          "Usage:\n/summaryconfig - show summary sections\n/summaryconfig <section> on|off - enable or disable a section\n/summaryconfig <section> title [heading] - set the heading of a section (no text - the default one)\n/summaryconfig reset - restore all sections to defaults\n/summaryconfig thread <id>|off - post summaries to a forum topic or to the general chat\n/summaryconfig pin on|off - pin summaries\n\nSections: topics, stats, activity, top, quote, replied",
+		"summaryconfig.admin_only":     "❌ Only bot administrators can change summary sections.",
+		"summaryconfig.save_failed":    "❌ Failed to save summary settings",
+		"summaryconfig.reset":          "✅ Summary sections were reset to defaults.",
+		"summaryconfig.title_too_long": "❌ The heading must be at most %d characters.",
+		"summaryconfig.updated":        "✅ Summary settings were updated.",
+		"summaryconfig.title":          "📝 *Daily summary sections*\n\n",
+		"summaryconfig.delivery":       "\n*Posting:*\n",
+		"summaryconfig.thread":         "Forum topic: `%d`\n",
+		"summaryconfig.thread_general": "Forum topic: general chat\n",
+		"summaryconfig.pin_on":         "Pinning: ✅\n",
+		"summaryconfig.pin_off":        "Pinning: ❌\n",
+		"summaryconfig.hint":           "\nChange (admins): /summaryconfig <section> on|off|title [heading], /summaryconfig thread <id>|off, /summaryconfig pin on|off",
+		"summaryconfig.invalid_thread": "❌ Specify the forum topic ID as a number or off.",
+		"range.title_date":             "Summary for %s",
+		"range.title_last":             "Summary for the last %s",
+		"range.title_missed":           "While you were away (since %s)",
+		"range.generating":             "⏳ Generating the summary...",
+		"range.private_chat":           "This command works in a group chat: I'll tell you what happened there since your last message.",
+		"range.last_message_failed":    "❌ Failed to find your last message. Try again later.",
+		"range.no_messages_from_user":  "I didn't find your messages in this chat. Use, for example, /summary 3h",
+		"range.sent_privately":         "📬 Sent the summary to private messages.",
+		"range.empty":                  "There were no messages from %s to %s.",
+
+		// Tiers and chat pool
+		"tier.source.user_chat":   "assigned to you in this chat",
+		"tier.source.user_global": "assigned to you in all chats",
+		"tier.source.chat":        "tier of this chat",
+		"tier.source.default":     "default",
+		"tier.yours":              "🏷 *Your tier:* %s (%s)\n",
+		"tier.limits":             "Pro: %d, Flash: %d, images: %d per day\n",
+		"tier.token_budget":       "Token budget: %d per day\n",
+		"tier.available":          "*Available tiers:*\n",
+		"tier.line":               "• %s — Pro %s, Flash %s, images %s",
+		"tier.admin_help":         "\n*Management (admins):*\n/settier <tier|reset> [@user|id] [global] - tier of a user (or as a reply to a message)\n/chattier <tier|reset> - default tier of this chat",
+		"common.admin_only":       "❌ This command is only available to bot administrators.",
+		"tier.settier_usage":      "Usage: /settier <tier|reset> [@user|id] [global]\nOr reply with this command to a message of the user.",
+		"tier.scope_chat":         "in this chat",
+		"tier.scope_global":       "in all chats",
+		"tier.reset_failed":       "❌ Failed to reset the tier",
+		"tier.user_reset":         "✅ The tier of user %s %s was reset.",
+		"tier.not_found":          "❌ Tier \"%s\" was not found. List of tiers: /tier",
+		"tier.assign_failed":      "❌ Failed to assign the tier",
+		"tier.user_assigned":      "✅ User %s was assigned the *%s* tier %s.",
+		"tier.chattier_usage":     "Usage: /chattier <tier|reset>",
+		"tier.chat_reset_failed":  "❌ Failed to reset the chat tier",
+		"tier.chat_reset":         "✅ The chat tier was reset, default limits apply.",
+		"tier.chat_assign_failed": "❌ Failed to assign the tier to the chat",
+		"tier.chat_assigned":      "✅ This chat now has the *%s* tier.",
+		"user.not_specified":      "specify the user (@username or ID) or reply to their message",
+		"user.lookup_failed":      "failed to find user %s",
+		"user.not_seen":           "user %s hasn't written in this chat yet, use their ID",
+		"user.unrecognized":       "failed to recognize user \"%s\"",
+		"quota.failed":            "❌ Failed to get chat limits",
+		"quota.title":             "👥 *Chat-wide limit for today*\n\n",
+		"quota.share":             "\nOne participant may use at most %d%% of the chat-wide limit.\n",
+		"quota.yours":             "You have used: Pro %d, Flash %d\n",
+		"quota.nobody":            "\nNobody has used the AI today yet.\n",
+		"quota.top":               "\n*Most active:*\n",
+		"quota.resets":            "\n⏰ Resets in %d h.",
+		"quota.pool_unlimited":    "%s: %d used (no chat-wide limit)\n",
+		"quota.pool":              "%s: %d/%d, %d left (up to %d per participant)\n",
+
+		// Analytics and schedules
+		"analytics.period.day":     "today",
+		"analytics.period.week":    "for 7 days",
+		"analytics.period.month":   "for 30 days",
+		"analytics.top_usage":      "Usage: /top [day|week|month] [chart]",
+		"analytics.usage_usage":    "Usage: /usage [day|week|month] [chart]",
+		"analytics.top_title":      "🏆 *Top %s*\n\n",
+		"analytics.top_askers":     "🤖 *Most questions to the bot:*\n",
+		"analytics.top_chatters":   "\n💬 *Most active in the chat:*\n",
+		"analytics.unit_requests":  "req.",
+		"analytics.unit_messages":  "msg.",
+		"analytics.chart_messages": "Chat messages %s",
+		"analytics.chart_requests": "Bot requests by week",
+		"analytics.usage_title":    "📊 *Bot usage %s*\n\n",
+		"analytics.no_requests":    "There were no AI requests.\n",
+		"analytics.total":          "📈 Total requests: %d\n",
+		"analytics.latency":        "⏱ Average response time: %.1f s\n",
+		"analytics.errors":         "⚠️ Errors: %d (%.1f%%)\n",
+		"analytics.cost":           "💰 Cost: $%.4f\n",
+		"analytics.by_model":       "\n*By model:*\n",
+		"analytics.model_line":     "• %s — %d (%.0f%%), %.1f s, %d errors\n",
+		"analytics.images":         "\n🎨 Image generations: %d\n",
+		"analytics.by_week":        "\n*By week:*\n",
+		"analytics.week_line":      "• %s — %d requests, %d messages, %d active\n",
+		"analytics.no_data":        "No data\n",
+		"schedule.job.summary":     "📝 Summary",
+		"schedule.job.weekly":      "🗓 Weekly digest",
+		"schedule.job.monthly":     "📅 Monthly digest",
+		"schedule.not_configured":  "❌ The scheduler is not configured.",
+		"schedule.admin_only":      "❌ Only bot administrators can change the schedule.",
+		"schedule.usage":           "Usage: /schedule <summary|weekly|monthly> <cron|off|default>\nExample: /schedule summary 0 9 * * * — every day at 09:00\n/schedule weekly 0 10 * * 1 — on Mondays at 10:00\n/schedule monthly off — no monthly digest",
+		"schedule.invalid_cron":    "❌ Invalid cron expression: %s",
+		"schedule.save_failed":     "❌ Failed to save the schedule",
+		"schedule.not_applied":     "❌ The schedule was saved but not applied. It takes effect after a restart.",
+		"schedule.updated":         "✅ The schedule was updated.",
+		"schedule.default":         "%s (default)",
+		"schedule.failed":          "❌ Failed to get the schedule",
+		"schedule.off":             "off",
+		"schedule.title":           "🗓 *Schedule* (timezone `%s`)\n\n",
+		"schedule.next_run":        "   Next run: %s\n",
+		"schedule.sync":            "🔄 RAG synchronization: `%s`\n",
+
+		// Restarts and overload
+		"bot.dropped":         "⚠️ The bot is restarting, your request was not processed. Please repeat it in a minute.",
+		"bot.busy":            "⏳ The bot is overloaded with requests right now. Try again a bit later.",
+		"bot.job_interrupted": "⏳ The bot is restarting. Your request is saved and will be processed after the restart.",
+
+		// Timezones and summary history
+		"timezone.admin_only":             "❌ Only bot administrators can change the chat timezone.",
+		"timezone.save_failed":            "❌ Failed to save the timezone",
+		"timezone.chat_updated":           "✅ The chat timezone was updated.",
+		"timezone.user_updated":           "✅ Your timezone was updated.",
+		"timezone.source_default":         "default",
+		"timezone.source_chat":            "set for the chat",
+		"timezone.source_as_chat":         "same as the chat",
+		"timezone.source_user":            "personal",
+		"timezone.text":                   "🕰 *Timezone*\n\nChat: `%s` (%s), now %s\nYours: `%s` (%s), now %s\n\nDays of summaries and statistics are counted and the schedule runs in the chat timezone.\nEveryone's personal limits reset at midnight in `%s`.\n\nChange: /timezone <zone> (admins), /mytimezone <zone>",
+		"timezone.usage":                  "❌ Unknown timezone.\n\nUsage: %s <zone|default>\nThe zone is given in the IANA format, for example: Europe/Moscow, Asia/Almaty, UTC",
+		"summaries.load_failed":           "❌ Failed to load summaries. Try again later.",
+		"summaries.load_failed_short":     "❌ Failed to load the summary",
+		"summaries.empty":                 "📭 There are no saved summaries yet.",
+		"summaries.not_found":             "📭 No summary for %s was found.",
+		"summaries.search_not_configured": "❌ Summary search is not configured.",
+		"summaries.search_failed":         "❌ Failed to search summaries. Try again later.",
+		"summaries.search_empty":          "🔎 No summaries where \"%s\" was discussed were found.",
+		"summaries.search_title":          "🔎 *Summaries for \"%s\"*\n",
+		"summaries.search_result":         "\n📅 *%s* (match %.0f%%)\n",
+		"summaries.open":                  "📅 Open the summary for %s",
+
+		// Summary templates
+		"summarytemplate.not_configured": "❌ Summary templates are not configured.",
+		"summarytemplate.admin_only":     "❌ Only bot administrators can preview and change the summary template.",
+		"summarytemplate.usage":          "Usage:\n/summarytemplate - show the summary template\n/summarytemplate preview [template] - the latest summary with the template (the current one if omitted)\n/summarytemplate set <template> - set a Go text/template template\n/summarytemplate reset - restore the default template",
+		"summarytemplate.custom":         "📝 *Summary template of the chat:*\n",
+		"summarytemplate.default":        "📝 *Default summary template:*\n",
+		"summarytemplate.hint":           "\nFields: .Header, .Date, .ISODate, .MessageCount, .ParticipantCount, .Sections (Name, Title, Body) and .Section.<section>; escape escapes text for Markdown.\nChange (admins): /summarytemplate preview [template], /summarytemplate set <template>, /summarytemplate reset",
+		"summarytemplate.invalid":        "❌ Template error: %s",
+		"summarytemplate.save_failed":    "❌ Failed to save the summary template",
+		"summarytemplate.updated":        "✅ The summary template was saved. It will be used for the next summary.",
+		"summarytemplate.reset":          "✅ The summary template was reset to the default one.",
+		"summarytemplate.preview":        "👁 Preview of the latest summary:",
+		"summarytemplate.no_summary":     "📭 At least one stored summary is needed for a preview.",
+		"summarytemplate.preview_failed": "❌ Failed to build the preview. Try again later.",
+
+		// Image models
+		"draw.not_configured": "❌ Image generation is not configured.",
+		"draw.models":         "\n\nModels: %s. Choose one: /draw --model <model> <description>",
+		"draw.model_default":  "%s (default)",
+		"draw.unknown_model":  "❌ Unknown model %s. Available: %s",
+		"draw.unknown_option": "❌ Unknown option %s.",
+		"draw.option_value":   "❌ Specify the value of %s.",
+
+		// Image options
+		"draw.invalid_aspect_ratio": "❌ Unsupported aspect ratio %s. Available: %s",
+		"draw.invalid_seed":         "❌ The seed must be a number from 0 to %d.",
+		"draw.invalid_count":        "❌ The number of images must be from 1 to %d.",
+		"draw.limit_count":          "❌ You asked for %d images, but only %d generations are left today.",
+
+		// Image editing
+		"draw.edit_empty":             "Describe what to change in the photo. Example: /draw make the sky a sunset\nFor variations, reply to the photo with /vary",
+		"draw.edit_not_configured":    "❌ None of the models can edit images.",
+		"draw.edit_unsupported_model": "❌ Model %s can't edit images. Suitable: %s",
+		"draw.editing":                "🎨 Editing the image...",
+		"draw.source_failed":          "❌ Failed to download the photo",
+		"vary.no_photo":               "Reply to a photo with /vary to get its variations.",
+		"vary.prompt":                 "❌ /vary takes no description. To change a photo as described, reply to it with /draw <what to change>.",
+
+		// Image prompt enhancement
+		"draw.flag_value":      "❌ Option %s takes no value.",
+		"draw.enhanced_prompt": "\n\n✍️ Prompt: %s",
+
+		// Image option limits
+		"draw.negative_too_long": "⚠️ The --no list is too long. %d characters at most.",
+	},
+	Plurals: map[string]Forms{
+		// Summary prompts
+		"unit.messages": {One: "%d message", Other: "%d messages"},
+
+		// Scheduled reports
+		"unit.participants_from": {One: "%d participant", Other: "%d participants"},
+		"unit.replies":           {One: "%d reply", Other: "%d replies"},
+
+		// Rate limits, chat history and answers
+		"time.minutes_ago": {One: "%d minute ago", Other: "%d minutes ago"},
+		"time.hours_ago":   {One: "%d hour ago", Other: "%d hours ago"},
+		"time.days_ago":    {One: "%d day ago", Other: "%d days ago"},
+		"time.weeks_ago":   {One: "%d week ago", Other: "%d weeks ago"},
+		"time.months_ago":  {One: "%d month ago", Other: "%d months ago"},
+		"time.years_ago":   {One: "%d year ago", Other: "%d years ago"},
+	},
+}
@@ -0,0 +1,173 @@
+// Package i18n translates user-facing messages and prompts into the supported locales
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Supported locales
+const (
+	LocaleRu = "ru"
+	LocaleEn = "en"
+)
+
+// DefaultLocale is used when no locale is configured or detected
+const DefaultLocale = LocaleRu
+
+// Catalog holds the messages of one locale
+// Messages are fmt templates, plural messages have a template per plural category
+type Catalog struct {
+	Messages map[string]string
+	Plurals  map[string]Forms
+}
+
+// Forms are the templates of a plural message by CLDR category
+// Empty forms fall back to Other, so English only needs One and Other
+type Forms struct {
+	One   string
+	Few   string
+	Many  string
+	Other string
+}
+
+// Category is a CLDR plural category
+type Category int
+
+// Plural categories used by the supported locales
+const (
+	One Category = iota
+	Few
+	Many
+	Other
+)
+
+// catalogs maps locales to their messages
+var catalogs = map[string]*Catalog{
+	LocaleRu: &ru,
+	LocaleEn: &en,
+}
+
+// pluralRules select the plural category of an integer by locale
+var pluralRules = map[string]func(n int) Category{
+	LocaleRu: pluralRu,
+	LocaleEn: pluralEn,
+}
+
+// Locales returns the supported locales
+func Locales() []string {
+	return []string{LocaleRu, LocaleEn}
+}
+
+// Supported reports whether the locale has a catalog
+func Supported(locale string) bool {
+	_, ok := catalogs[locale]
+	return ok
+}
+
+// Match returns the supported locale of a Telegram language code (e.g. "en-US"), empty if unsupported
+func Match(languageCode string) string {
+	base, _, _ := strings.Cut(strings.ToLower(languageCode), "-")
+	if Supported(base) {
+		return base
+	}
+	return ""
+}
+
+// Localizer translates messages into one locale, falling back to the default locale for missing keys
+type Localizer struct {
+	locale string
+}
+
+// New creates a localizer of the locale, of the default locale if it is not supported
+func New(locale string) *Localizer {
+	if !Supported(locale) {
+		locale = DefaultLocale
+	}
+	return &Localizer{locale: locale}
+}
+
+// Locale returns the locale of the localizer
+func (l *Localizer) Locale() string {
+	return l.locale
+}
+
+// T returns the message of the key formatted with args
+// Missing keys fall back to the default locale, then to the key itself
+func (l *Localizer) T(key string, args ...interface{}) string {
+	template, ok := catalogs[l.locale].Messages[key]
+	if !ok {
+		template, ok = catalogs[DefaultLocale].Messages[key]
+	}
+	if !ok {
+		return key
+	}
+
+	if len(args) == 0 {
+		return template
+	}
+	return fmt.Sprintf(template, args...)
+}
+
+// N returns the plural form of the key for n, formatted with n followed by args
+func (l *Localizer) N(key string, n int, args ...interface{}) string {
+	locale := l.locale
+	forms, ok := catalogs[locale].Plurals[key]
+	if !ok {
+		locale = DefaultLocale
+		forms, ok = catalogs[locale].Plurals[key]
+	}
+	if !ok {
+		return key
+	}
+
+	return fmt.Sprintf(forms.form(pluralRules[locale](n)), append([]interface{}{n}, args...)...)
+}
+
+// DayMonth formats the day and month of a date, e.g. "20 ноября" or "November 20"
+func (l *Localizer) DayMonth(t time.Time) string {
+	return l.T("date.day_month", t.Day(), l.T(fmt.Sprintf("date.month.%d", int(t.Month()))))
+}
+
+// form returns the template of the category, Other if the locale doesn't define it
+func (f Forms) form(category Category) string {
+	var template string
+	switch category {
+	case One:
+		template = f.One
+	case Few:
+		template = f.Few
+	case Many:
+		template = f.Many
+	}
+	if template == "" {
+		return f.Other
+	}
+	return template
+}
+
+// pluralRu is the CLDR plural rule of Russian for integers:
+// one - 1, 21, 31...; few - 2-4, 22-24...; many - 0, 5-20, 25-30...
+func pluralRu(n int) Category {
+	if n < 0 {
+		n = -n
+	}
+	mod10, mod100 := n%10, n%100
+	switch {
+	case mod10 == 1 && mod100 != 11:
+		return One
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return Few
+	default:
+		return Many
+	}
+}
+
+// pluralEn is the CLDR plural rule of English for integers: one - 1, other - everything else
+func pluralEn(n int) Category {
+	if n == 1 {
+		return One
+	}
+	return Other
+}
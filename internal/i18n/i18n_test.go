@@ -0,0 +1,101 @@
+package i18n
+
+import "testing"
+
+func TestPluralRu(t *testing.T) {
+	tests := []struct {
+		n    int
+		want Category
+	}{
+		{0, Many},
+		{1, One},
+		{2, Few},
+		{4, Few},
+		{5, Many},
+		{11, Many},
+		{12, Many},
+		{14, Many},
+		{20, Many},
+		{21, One},
+		{22, Few},
+		{25, Many},
+		{101, One},
+		{111, Many},
+		{112, Many},
+		{122, Few},
+		{1001, One},
+		{-1, One},
+		{-3, Few},
+		{-11, Many},
+	}
+
+	for _, tt := range tests {
+		if got := pluralRu(tt.n); got != tt.want {
+			t.Errorf("pluralRu(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestPluralEn(t *testing.T) {
+	tests := []struct {
+		n    int
+		want Category
+	}{
+		{0, Other},
+		{1, One},
+		{2, Other},
+		{11, Other},
+		{21, Other},
+		{-1, Other},
+	}
+
+	for _, tt := range tests {
+		if got := pluralEn(tt.n); got != tt.want {
+			t.Errorf("pluralEn(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestLocalizerN(t *testing.T) {
+	tests := []struct {
+		locale string
+		n      int
+		want   string
+	}{
+		{LocaleRu, 1, "1 сообщение"},
+		{LocaleRu, 3, "3 сообщения"},
+		{LocaleRu, 11, "11 сообщений"},
+		{LocaleRu, 21, "21 сообщение"},
+		{LocaleRu, 0, "0 сообщений"},
+		{LocaleEn, 1, "1 message"},
+		{LocaleEn, 0, "0 messages"},
+		{LocaleEn, 21, "21 messages"},
+		{"de", 2, "2 сообщения"}, // Unsupported locales use the default one
+	}
+
+	for _, tt := range tests {
+		if got := New(tt.locale).N("unit.messages", tt.n); got != tt.want {
+			t.Errorf("N(%q, %d) in %s = %q, want %q", "unit.messages", tt.n, tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestFormsFallBackToOther(t *testing.T) {
+	forms := Forms{One: "one", Other: "other"}
+
+	tests := []struct {
+		category Category
+		want     string
+	}{
+		{One, "one"},
+		{Few, "other"},
+		{Many, "other"},
+		{Other, "other"},
+	}
+
+	for _, tt := range tests {
+		if got := forms.form(tt.category); got != tt.want {
+			t.Errorf("form(%v) = %q, want %q", tt.category, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,354 @@
+package i18n
+
+// ru is the Russian catalog
+var ru = Catalog{
+	Messages: map[string]string{
+		// Dates
+		"date.day_month": "%d %s",
+		"date.month.1":   "января",
+		"date.month.2":   "февраля",
+		"date.month.3":   "марта",
+		"date.month.4":   "апреля",
+		"date.month.5":   "мая",
+		"date.month.6":   "июня",
+		"date.month.7":   "июля",
+		"date.month.8":   "августа",
+		"date.month.9":   "сентября",
+		"date.month.10":  "октября",
+		"date.month.11":  "ноября",
+		"date.month.12":  "декабря",
+
+		// Summary prompts
+		"prompt.topic_rules":         "ВАЖНО:\n1. Название темы краткое (максимум 5-7 слов), эмодзи указывается отдельно\n2. Описание - одно предложение о том, что обсуждали и к чему пришли\n3. Участники - имена самых активных в теме, как они записаны в сообщениях\n4. ID сообщений - 1-3 самых характерных сообщения темы из квадратных скобок\n",
+		"prompt.quote_rule":          "Также выбери цитату дня (quote) - самое смешное, меткое или запоминающееся сообщение: его ID из квадратных скобок и короткий комментарий, почему оно.\n",
+		"prompt.summary":             "Проанализируй следующие сообщения из группового чата за %s и выдели 5-7 основных тем обсуждения.\n\n",
+		"prompt.summary_rules":       "5. Сфокусируйся на самых обсуждаемых и важных темах\n6. Если тем меньше 5, выведи только те что есть\n\n",
+		"prompt.messages":            "Сообщения (в квадратных скобках ID сообщения и время):\n\n",
+		"prompt.language":            "Пиши названия, описания и комментарии на русском языке.\n",
+		"prompt.range_label":         "период с %s по %s",
+		"prompt.chunk":               "Это часть %d из %d переписки группового чата за %s (с %s по %s).\nВыдели 3-5 основных тем обсуждения в этой части.\n\n",
+		"prompt.reduce":              "Переписка группового чата за %s (%s) была разбита на части по времени.\nНиже темы каждой части с количеством сообщений в ней, участниками и ID характерных сообщений.\n\n",
+		"prompt.reduce_chunk":        "--- %s–%s (%s)\n",
+		"prompt.reduce_topic":        "%s: %s (участники: %s; сообщения: %v)\n",
+		"prompt.reduce_merge":        "\nОбъедини их в 5-7 основных тем всего периода. Похожие темы из разных частей объединяй, темы, которые обсуждали дольше и активнее, ставь выше.\nУчастников и ID сообщений бери из объединяемых тем.\n\n",
+		"prompt.reduce_quotes":       "\nКандидаты в цитату дня из разных частей:\n%s\nВыбери из них одну цитату дня (quote) с её ID и коротким комментарием.\n",
+		"prompt.digest_period.week":  "неделю",
+		"prompt.digest_period.month": "месяц",
+		"prompt.digest":              "Ты анализируешь переписку группового чата за %s (с %s по %s).\nНиже краткие итоги отдельных дней и случайная выборка сообщений за этот период.\n\n",
+		"prompt.digest_days":         "ИТОГИ ДНЕЙ:\n",
+		"prompt.digest_day":          "--- %s (%s)\n",
+		"prompt.digest_sample":       "ВЫБОРКА СООБЩЕНИЙ (в квадратных скобках ID сообщения и время):\n",
+		"prompt.digest_previous":     "ТЕМЫ ПРЕДЫДУЩЕГО ПЕРИОДА:\n",
+		"prompt.digest_task":         "Выдели 5-7 главных тем периода.\n",
+		"prompt.digest_trends":       "Сравни с темами предыдущего периода: какие темы стали обсуждать больше (rising, до 3) и какие почти перестали обсуждать (fading, до 3).\n",
+		"prompt.digest_ids":          "ID сообщений указывай только из выборки сообщений.\n",
+		"schema.quote.message_id":    "ID сообщения-цитаты",
+		"schema.quote.comment":       "Короткий комментарий, почему выбрано это сообщение",
+		"schema.topic.emoji":         "Один эмодзи, подходящий к теме",
+		"schema.topic.title":         "Краткое название темы, 5-7 слов",
+		"schema.topic.description":   "Одно предложение о сути обсуждения",
+		"schema.topic.participants":  "Имена ключевых участников обсуждения",
+		"schema.topic.message_ids":   "ID характерных сообщений темы",
+		"schema.digest.topics":       "Главные темы периода",
+		"schema.digest.rising":       "Темы, которые стали обсуждать больше",
+		"schema.digest.fading":       "Темы, которые почти перестали обсуждать",
+
+		// Scheduled reports
+		"summary.title":            "📊 *Саммари за %s*\n",
+		"summary.section.topics":   "Основные темы обсуждения",
+		"summary.section.stats":    "Статистика",
+		"summary.section.activity": "Активность по часам",
+		"summary.section.top":      "Самые активные участники",
+		"summary.section.quote":    "Цитата дня",
+		"summary.section.replied":  "Самое обсуждаемое сообщение",
+		"summary.no_topics":        "В этот день не было активных обсуждений\n",
+		"summary.stats":            "💬 %s от %s, %s\n",
+		"summary.activity_peak":    "Пик: %02d:00–%02d:00 (%s)\n",
+		"report.no_discussions":    "*Активных обсуждений не было*\n",
+		"report.activity":          "\n*Активность:* %s от %s",
+		"report.top":               "*Самые активные:* %s\n",
+		"digest.title_month":       "🗓 *Дайджест за %s %d*\n\n",
+		"digest.title_week":        "🗓 *Дайджест за неделю %s – %s*\n\n",
+		"digest.topics":            "*Главные темы:*\n\n",
+		"digest.rising":            "\n*Набирают обороты:*\n",
+		"digest.fading":            "\n*Угасают:*\n",
+		"digest.change":            " (%+d%% к прошлому периоду)",
+		"digest.and_more":          "и ещё %d",
+		"digest.new_users":         "*Новые участники:* %s\n",
+		"digest.chart_title":       "Сообщения по дням",
+		"date.month_standalone.1":  "январь",
+		"date.month_standalone.2":  "февраль",
+		"date.month_standalone.3":  "март",
+		"date.month_standalone.4":  "апрель",
+		"date.month_standalone.5":  "май",
+		"date.month_standalone.6":  "июнь",
+		"date.month_standalone.7":  "июль",
+		"date.month_standalone.8":  "август",
+		"date.month_standalone.9":  "сентябрь",
+		"date.month_standalone.10": "октябрь",
+		"date.month_standalone.11": "ноябрь",
+		"date.month_standalone.12": "декабрь",
+		"date.weekday_short.0":     "Вс",
+		"date.weekday_short.1":     "Пн",
+		"date.weekday_short.2":     "Вт",
+		"date.weekday_short.3":     "Ср",
+		"date.weekday_short.4":     "Чт",
+		"date.weekday_short.5":     "Пт",
+		"date.weekday_short.6":     "Сб",
+
+		// Rate limits, chat history and answers
+		"limit.blocked":        "🚫 Вам недоступны запросы к AI. Обратитесь к администратору бота.",
+		"limit.tokens":         "🚫 Вы исчерпали дневной бюджет токенов (%d/%d).\n\nЛимиты сбросятся через %d ч.",
+		"limit.daily":          "🚫 Вы исчерпали дневной лимит запросов.\n\nЛимиты сбросятся через %d ч.\nPro: %d/%d\nFlash: %d/%d",
+		"limit.pool":           "🚫 %s\n\nЛимиты сбросятся через %d ч.\nПодробнее: /quota",
+		"limit.pool_exhausted": "Общий лимит чата на %s исчерпан (%d/%d).",
+		"limit.pool_share":     "Вы израсходовали свою долю общего лимита чата на %s (%d/%d, не более %d%%).",
+		"rag.header":           "РЕЛЕВАНТНАЯ ИНФОРМАЦИЯ ИЗ ИСТОРИИ ЧАТА:\n\n",
+		"rag.entry":            "%d. %s (%s, релевантность: %s): \"%s\"\n",
+		"rag.truncated":        "\n[... еще %d релевантных сообщений не показаны из-за ограничения длины]\n",
+		"time.just_now":        "только что",
+		"llm.prompt":           "Ответь на следующий вопрос. ВАЖНО: твой ответ должен быть не более 3500 символов. Это строгое ограничение для совместимости с Telegram.\n\nВопрос: %s",
+		"llm.prompt_rag":       "Ты полезный AI ассистент. У тебя есть доступ к истории чата.\n\n%s\n\nВОПРОС ПОЛЬЗОВАТЕЛЯ:\n%s\n\nОтветь на вопрос, используя информацию из истории чата, если она релевантна. Если информация из истории неполная или устарела, дополни её своими знаниями.\n\nВАЖНО: твой ответ должен быть не более 3500 символов. Это строгое ограничение для совместимости с Telegram.",
+		"llm.truncated":        "\n\n...[ответ обрезан из-за превышения лимита]",
+
+		// Commands and mentions
+		"common.chat_not_allowed":   "❌ Эта команда доступна только в разрешенных чатах.",
+		"common.unknown_command":    "❓ Неизвестная команда. Используйте /help для списка команд.",
+		"common.limit_check_failed": "❌ Ошибка при проверке лимитов",
+		"stats.failed":              "❌ Ошибка при получении статистики",
+		"stats.tokens":              "🔢 *Токенов сегодня:* %d\n",
+		"stats.tokens_budget":       "🔢 *Токенов сегодня:* %d/%d\n",
+		"stats.text":                "📊 *Статистика для %s*\n🏷 Тариф: %s\n\n🤖 *Gemini Pro (Thinking):*\n   Использовано: %d/%d\n   Осталось: %d\n\n⚡ *Gemini Flash:*\n   Использовано: %d/%d\n   Осталось: %d\n\n%s📈 *Всего запросов:* %d\n⏰ *Сброс лимитов через:* %d ч.",
+		"help.text":                 "👋 *Привет! Я бот с AI ассистентом*\n\n*Как использовать:*\nПросто упомяните меня (@%s) и задайте вопрос!\n\n*Доступные команды:*\n/stats - Посмотреть свою статистику\n/draw [--model <модель>] [--ar 16:9] [--no текст] [--seed 42] [--n 4] [--raw] <запрос> - Сгенерировать изображение по описанию, в ответ на фото — изменить его\n/vary [--n 4] - В ответ на фото: вариации фото\n/summary [week|month] - Саммари за вчера или дайджест за прошлую неделю/месяц\n/summary <3h|2025-10-01> - Саммари за последние часы или за дату\n/summary since my last message - Что вы пропустили с вашего последнего сообщения\n/summaries [запрос] - Листать прошлые саммари или искать, когда что обсуждали\n/sync - Запустить синхронизацию RAG (индексация сообщений)\n/tier - Показать ваш тариф и доступные тарифы\n/quota - Общий лимит чата и самые активные участники\n/top [day|week|month] [chart] - Самые активные участники\n/usage [day|week|month] [chart] - Статистика использования бота\n/schedule - Расписание саммари, дайджестов и синхронизации\n/summaryconfig - Разделы, тема и закрепление саммари\n/summarytemplate - Шаблон оформления саммари\n/timezone - Часовой пояс чата\n/mytimezone [пояс] - Ваш личный часовой пояс\n/language - Язык чата\n/mylanguage [ru|en] - Ваш личный язык ответов\n/help - Показать это сообщение\n\n*Ваши лимиты (тариф %s):*\n• Gemini Pro (думающая модель): %d запросов/день\n• Gemini Flash (быстрая модель): %d запросов/день\n• Генерация изображений: %d генераций/день\n\nСначала используются запросы к Pro модели, затем к Flash.\nЛимиты сбрасываются в полночь по часовому поясу `%s`.\n\n*Примеры:*\n• /draw красивый закат над океаном\n• /draw кот в космосе в стиле киберпанк\n• /draw --ar 16:9 --no текст,люди --n 4 горы на рассвете\n\n*Автоматические задачи:*\n• Синхронизация RAG (индексация embeddings)\n• Ежедневное саммари\n• Еженедельный и ежемесячный дайджест\nВремя запуска: /schedule",
+		"summary.usage":             "Использование: /summary [week|month|<3h|90m|2d>|<2025-10-01>|since my last message]",
+		"summary.generating_week":   "⏳ Генерирую дайджест за прошлую неделю...",
+		"summary.generating_month":  "⏳ Генерирую дайджест за прошлый месяц...",
+		"summary.generating_day":    "⏳ Генерирую саммари за вчерашний день...",
+		"summary.failed":            "❌ Ошибка при генерации саммари. Попробуйте позже.",
+		"summary.not_configured":    "❌ Функция саммари не настроена.",
+		"sync.starting":             "🔄 Запускаю синхронизацию RAG...\n\nЭто может занять несколько минут.",
+		"sync.failed":               "❌ Ошибка при синхронизации. Попробуйте позже.",
+		"sync.done":                 "✅ Синхронизация завершена успешно!",
+		"sync.not_configured":       "❌ Функция синхронизации не настроена.",
+		"mention.empty":             "❓ Пожалуйста, задайте вопрос после упоминания.",
+		"mention.truncated":         "⚠️ Ваш вопрос был обрезан до %d символов. Пожалуйста, формулируйте вопросы короче.",
+		"mention.failed":            "❌ Извините, произошла ошибка при обработке вашего запроса. Попробуйте позже.",
+		"mention.footer":            "%s\n\n---\n%s _Модель: %s | Время: %dмс_",
+		"draw.empty":                "Укажите описание изображения. Пример: /draw красивый закат над океаном",
+		"draw.too_long":             "⚠️ Описание слишком длинное. Максимум 500 символов.",
+		"draw.limit":                "❌ Вы исчерпали дневной лимит генераций (%d/день). Попробуйте завтра.",
+		"draw.generating":           "🎨 Генерирую изображение...",
+		"draw.unavailable":          "⚠️ Сервис генерации временно недоступен. Попробуйте позже.",
+		"draw.caption":              "🖼 %s\n🔁 %s\n✨ Осталось генераций сегодня: %d",
+		"draw.send_failed":          "❌ Не удалось отправить изображение",
+
+		// Languages
+		"language.name.ru":         "русский",
+		"language.name.en":         "английский",
+		"language.admin_only":      "❌ Изменять язык чата могут только администраторы бота.",
+		"language.save_failed":     "❌ Не удалось сохранить язык",
+		"language.chat_updated":    "✅ Язык чата обновлен.",
+		"language.user_updated":    "✅ Ваш язык обновлен.",
+		"language.source_default":  "по умолчанию",
+		"language.source_chat":     "настроен для чата",
+		"language.source_user":     "личный",
+		"language.source_telegram": "из настроек Telegram",
+		"language.source_as_chat":  "как у чата",
+		"language.text":            "🌐 *Язык*\n\nЧат: %s (%s)\nВаш: %s (%s)\n\nНа языке чата публикуются саммари и дайджесты.\nНа вашем языке бот отвечает вам: ответы на вопросы, команды и сообщения о лимитах. Без личной настройки используется язык вашего Telegram.\n\nИзменить: /language <%s|default> (админы), /mylanguage <%s|reset>",
+		"language.usage":           "❌ Неизвестный язык.\n\nИспользование: %s <%s|%s>",
+
+		// Summary settings and on-demand summaries
+		"summaryconfig.usage":          "Использование:\n/summaryconfig - показать разделы саммари\n/summaryconfig <раздел> on|off - включить или выключить раздел\n/summaryconfig <раздел> title [заголовок] - задать заголовок раздела (без текста - по умолчанию)\n/summaryconfig reset - вернуть все разделы по умолчанию\n/summaryconfig thread <id>|off - публиковать саммари в теме форума или в общем чате\n/summaryconfig pin on|off - закреплять саммари\n\nРазделы: topics, stats, activity, top, quote, replied",
+		"summaryconfig.admin_only":     "❌ Изменять разделы саммари могут только администраторы бота.",
+		"summaryconfig.save_failed":    "❌ Не удалось сохранить настройки саммари",
+		"summaryconfig.reset":          "✅ Разделы саммари сброшены по умолчанию.",
+		"summaryconfig.title_too_long": "❌ Заголовок не должен быть длиннее %d символов.",
+		"summaryconfig.updated":        "✅ Настройки саммари обновлены.",
+		"summaryconfig.title":          "📝 *Разделы ежедневного саммари*\n\n",
+		"summaryconfig.delivery":       "\n*Публикация:*\n",
+		"summaryconfig.thread":         "Тема форума: `%d`\n",
+		"summaryconfig.thread_general": "Тема форума: общий чат\n",
+		"summaryconfig.pin_on":         "Закрепление: ✅\n",
+		"summaryconfig.pin_off":        "Закрепление: ❌\n",
+		"summaryconfig.hint":           "\nИзменить (админы): /summaryconfig <раздел> on|off|title [заголовок], /summaryconfig thread <id>|off, /summaryconfig pin on|off",
+		"summaryconfig.invalid_thread": "❌ Укажите ID темы форума числом или off.",
+		"range.title_date":             "Саммари за %s",
+		"range.title_last":             "Саммари за последние %s",
+		"range.title_missed":           "Пока вас не было (с %s)",
+		"range.generating":             "⏳ Генерирую саммари...",
+		"range.private_chat":           "Эта команда работает в групповом чате: я расскажу, что там произошло с вашего последнего сообщения.",
+		"range.last_message_failed":    "❌ Не удалось найти ваше последнее сообщение. Попробуйте позже.",
+		"range.no_messages_from_user":  "Я не нашёл ваших сообщений в этом чате. Используйте, например, /summary 3h",
+		"range.sent_privately":         "📬 Отправил саммари в личные сообщения.",
+		"range.empty":                  "За период с %s по %s сообщений не было.",
+
+		// Tiers and chat pool
+		"tier.source.user_chat":   "назначен вам в этом чате",
+		"tier.source.user_global": "назначен вам во всех чатах",
+		"tier.source.chat":        "тариф этого чата",
+		"tier.source.default":     "по умолчанию",
+		"tier.yours":              "🏷 *Ваш тариф:* %s (%s)\n",
+		"tier.limits":             "Pro: %d, Flash: %d, изображения: %d в день\n",
+		"tier.token_budget":       "Бюджет токенов: %d в день\n",
+		"tier.available":          "*Доступные тарифы:*\n",
+		"tier.line":               "• %s — Pro %s, Flash %s, изображения %s",
+		"tier.admin_help":         "\n*Управление (для админов):*\n/settier <тариф|reset> [@user|id] [global] - тариф пользователя (или ответом на сообщение)\n/chattier <тариф|reset> - тариф по умолчанию для этого чата",
+		"common.admin_only":       "❌ Эта команда доступна только администраторам бота.",
+		"tier.settier_usage":      "Использование: /settier <тариф|reset> [@user|id] [global]\nИли ответьте этой командой на сообщение пользователя.",
+		"tier.scope_chat":         "в этом чате",
+		"tier.scope_global":       "во всех чатах",
+		"tier.reset_failed":       "❌ Не удалось сбросить тариф",
+		"tier.user_reset":         "✅ Тариф пользователя %s %s сброшен.",
+		"tier.not_found":          "❌ Тариф «%s» не найден. Список тарифов: /tier",
+		"tier.assign_failed":      "❌ Не удалось назначить тариф",
+		"tier.user_assigned":      "✅ Пользователю %s назначен тариф *%s* %s.",
+		"tier.chattier_usage":     "Использование: /chattier <тариф|reset>",
+		"tier.chat_reset_failed":  "❌ Не удалось сбросить тариф чата",
+		"tier.chat_reset":         "✅ Тариф чата сброшен, действуют лимиты по умолчанию.",
+		"tier.chat_assign_failed": "❌ Не удалось назначить тариф чату",
+		"tier.chat_assigned":      "✅ Для этого чата установлен тариф *%s*.",
+		"user.not_specified":      "укажите пользователя (@username или ID) или ответьте на его сообщение",
+		"user.lookup_failed":      "не удалось найти пользователя %s",
+		"user.not_seen":           "пользователь %s ещё не писал в этом чате, используйте его ID",
+		"user.unrecognized":       "не удалось распознать пользователя «%s»",
+		"quota.failed":            "❌ Ошибка при получении лимитов чата",
+		"quota.title":             "👥 *Общий лимит чата на сегодня*\n\n",
+		"quota.share":             "\nОдин участник может использовать не более %d%% общего лимита.\n",
+		"quota.yours":             "Вы использовали: Pro %d, Flash %d\n",
+		"quota.nobody":            "\nСегодня ещё никто не обращался к AI.\n",
+		"quota.top":               "\n*Самые активные:*\n",
+		"quota.resets":            "\n⏰ Сброс через %d ч.",
+		"quota.pool_unlimited":    "%s: использовано %d (без общего лимита)\n",
+		"quota.pool":              "%s: %d/%d, осталось %d (до %d на участника)\n",
+
+		// Analytics and schedules
+		"analytics.period.day":     "сегодня",
+		"analytics.period.week":    "за 7 дней",
+		"analytics.period.month":   "за 30 дней",
+		"analytics.top_usage":      "Использование: /top [day|week|month] [chart]",
+		"analytics.usage_usage":    "Использование: /usage [day|week|month] [chart]",
+		"analytics.top_title":      "🏆 *Топ %s*\n\n",
+		"analytics.top_askers":     "🤖 *Больше всего вопросов боту:*\n",
+		"analytics.top_chatters":   "\n💬 *Самые активные в чате:*\n",
+		"analytics.unit_requests":  "запр.",
+		"analytics.unit_messages":  "сообщ.",
+		"analytics.chart_messages": "Сообщения в чате %s",
+		"analytics.chart_requests": "Запросы к боту по неделям",
+		"analytics.usage_title":    "📊 *Использование бота %s*\n\n",
+		"analytics.no_requests":    "Запросов к AI не было.\n",
+		"analytics.total":          "📈 Всего запросов: %d\n",
+		"analytics.latency":        "⏱ Среднее время ответа: %.1f с\n",
+		"analytics.errors":         "⚠️ Ошибки: %d (%.1f%%)\n",
+		"analytics.cost":           "💰 Стоимость: $%.4f\n",
+		"analytics.by_model":       "\n*По моделям:*\n",
+		"analytics.model_line":     "• %s — %d (%.0f%%), %.1f с, ошибок %d\n",
+		"analytics.images":         "\n🎨 Генераций изображений: %d\n",
+		"analytics.by_week":        "\n*По неделям:*\n",
+		"analytics.week_line":      "• %s — запросов %d, сообщений %d, активных %d\n",
+		"analytics.no_data":        "Нет данных\n",
+		"schedule.job.summary":     "📝 Саммари",
+		"schedule.job.weekly":      "🗓 Недельный дайджест",
+		"schedule.job.monthly":     "📅 Месячный дайджест",
+		"schedule.not_configured":  "❌ Планировщик не настроен.",
+		"schedule.admin_only":      "❌ Изменять расписание могут только администраторы бота.",
+		"schedule.usage":           "Использование: /schedule <summary|weekly|monthly> <cron|off|default>\nПример: /schedule summary 0 9 * * * — каждый день в 09:00\n/schedule weekly 0 10 * * 1 — по понедельникам в 10:00\n/schedule monthly off — без месячного дайджеста",
+		"schedule.invalid_cron":    "❌ Неверное cron-выражение: %s",
+		"schedule.save_failed":     "❌ Не удалось сохранить расписание",
+		"schedule.not_applied":     "❌ Расписание сохранено, но не применено. Оно вступит в силу после перезапуска.",
+		"schedule.updated":         "✅ Расписание обновлено.",
+		"schedule.default":         "%s (по умолчанию)",
+		"schedule.failed":          "❌ Ошибка при получении расписания",
+		"schedule.off":             "выключено",
+		"schedule.title":           "🗓 *Расписание* (часовой пояс `%s`)\n\n",
+		"schedule.next_run":        "   Следующий запуск: %s\n",
+		"schedule.sync":            "🔄 Синхронизация RAG: `%s`\n",
+
+		// Restarts and overload
+		"bot.dropped":         "⚠️ Бот перезапускается, ваш запрос не был обработан. Пожалуйста, повторите его через минуту.",
+		"bot.busy":            "⏳ Бот сейчас перегружен запросами. Попробуйте чуть позже.",
+		"bot.job_interrupted": "⏳ Бот перезапускается. Ваш запрос сохранён и будет обработан после перезапуска.",
+
+		// Timezones and summary history
+		"timezone.admin_only":             "❌ Изменять часовой пояс чата могут только администраторы бота.",
+		"timezone.save_failed":            "❌ Не удалось сохранить часовой пояс",
+		"timezone.chat_updated":           "✅ Часовой пояс чата обновлен.",
+		"timezone.user_updated":           "✅ Ваш часовой пояс обновлен.",
+		"timezone.source_default":         "по умолчанию",
+		"timezone.source_chat":            "настроен для чата",
+		"timezone.source_as_chat":         "как у чата",
+		"timezone.source_user":            "личный",
+		"timezone.text":                   "🕰 *Часовой пояс*\n\nЧат: `%s` (%s), сейчас %s\nВаш: `%s` (%s), сейчас %s\n\nПо часовому поясу чата считаются дни саммари и статистики и запускается расписание.\nЛичные лимиты у всех сбрасываются в полночь по поясу `%s`.\n\nИзменить: /timezone <пояс> (админы), /mytimezone <пояс>",
+		"timezone.usage":                  "❌ Неизвестный часовой пояс.\n\nИспользование: %s <пояс|default>\nПояс указывается в формате IANA, например: Europe/Moscow, Asia/Almaty, UTC",
+		"summaries.load_failed":           "❌ Не удалось загрузить саммари. Попробуйте позже.",
+		"summaries.load_failed_short":     "❌ Не удалось загрузить саммари",
+		"summaries.empty":                 "📭 Сохранённых саммари пока нет.",
+		"summaries.not_found":             "📭 Саммари за %s не найдено.",
+		"summaries.search_not_configured": "❌ Поиск по саммари не настроен.",
+		"summaries.search_failed":         "❌ Ошибка при поиске по саммари. Попробуйте позже.",
+		"summaries.search_empty":          "🔎 Не нашёл саммари, где обсуждалось «%s».",
+		"summaries.search_title":          "🔎 *Саммари по запросу «%s»*\n",
+		"summaries.search_result":         "\n📅 *%s* (совпадение %.0f%%)\n",
+		"summaries.open":                  "📅 Открыть саммари за %s",
+
+		// Summary templates
+		"summarytemplate.not_configured": "❌ Шаблоны саммари не настроены.",
+		"summarytemplate.admin_only":     "❌ Просматривать и изменять шаблон саммари могут только администраторы бота.",
+		"summarytemplate.usage":          "Использование:\n/summarytemplate - показать шаблон саммари\n/summarytemplate preview [шаблон] - последнее саммари с шаблоном (без шаблона - с текущим)\n/summarytemplate set <шаблон> - задать шаблон в формате Go text/template\n/summarytemplate reset - вернуть шаблон по умолчанию",
+		"summarytemplate.custom":         "📝 *Шаблон саммари чата:*\n",
+		"summarytemplate.default":        "📝 *Шаблон саммари по умолчанию:*\n",
+		"summarytemplate.hint":           "\nПоля: .Header, .Date, .ISODate, .MessageCount, .ParticipantCount, .Sections (Name, Title, Body) и .Section.<раздел>; escape экранирует текст для Markdown.\nИзменить (админы): /summarytemplate preview [шаблон], /summarytemplate set <шаблон>, /summarytemplate reset",
+		"summarytemplate.invalid":        "❌ Ошибка в шаблоне: %s",
+		"summarytemplate.save_failed":    "❌ Не удалось сохранить шаблон саммари",
+		"summarytemplate.updated":        "✅ Шаблон саммари сохранён. Он будет использован в следующем саммари.",
+		"summarytemplate.reset":          "✅ Шаблон саммари сброшен по умолчанию.",
+		"summarytemplate.preview":        "👁 Предпросмотр последнего саммари:",
+		"summarytemplate.no_summary":     "📭 Для предпросмотра нужно хотя бы одно сохранённое саммари.",
+		"summarytemplate.preview_failed": "❌ Не удалось построить предпросмотр. Попробуйте позже.",
+
+		// Image models
+		"draw.not_configured": "❌ Генерация изображений не настроена.",
+		"draw.models":         "\n\nМодели: %s. Выбрать: /draw --model <модель> <описание>",
+		"draw.model_default":  "%s (по умолчанию)",
+		"draw.unknown_model":  "❌ Неизвестная модель %s. Доступные: %s",
+		"draw.unknown_option": "❌ Неизвестный параметр %s.",
+		"draw.option_value":   "❌ Укажите значение параметра %s.",
+
+		// Image options
+		"draw.invalid_aspect_ratio": "❌ Неподдерживаемое соотношение сторон %s. Доступные: %s",
+		"draw.invalid_seed":         "❌ Seed должен быть числом от 0 до %d.",
+		"draw.invalid_count":        "❌ Количество изображений должно быть от 1 до %d.",
+		"draw.limit_count":          "❌ Запрошено изображений: %d, а генераций на сегодня осталось: %d.",
+
+		// Image editing
+		"draw.edit_empty":             "Опишите, что изменить на фото. Пример: /draw сделай небо закатным\nДля вариаций ответьте на фото командой /vary",
+		"draw.edit_not_configured":    "❌ Ни одна из моделей не умеет редактировать изображения.",
+		"draw.edit_unsupported_model": "❌ Модель %s не умеет редактировать изображения. Подходящие: %s",
+		"draw.editing":                "🎨 Редактирую изображение...",
+		"draw.source_failed":          "❌ Не удалось загрузить фото",
+		"vary.no_photo":               "Ответьте командой /vary на фото, чтобы получить его вариации.",
+		"vary.prompt":                 "❌ /vary не принимает описание. Чтобы изменить фото по описанию, ответьте на него командой /draw <что изменить>.",
+
+		// Image prompt enhancement
+		"draw.flag_value":      "❌ Параметр %s указывается без значения.",
+		"draw.enhanced_prompt": "\n\n✍️ Промпт: %s",
+
+		// Image option limits
+		"draw.negative_too_long": "⚠️ Список --no слишком длинный. Максимум %d символов.",
+	},
+	Plurals: map[string]Forms{
+		// Summary prompts
+		"unit.messages": {One: "%d сообщение", Few: "%d сообщения", Many: "%d сообщений"},
+
+		// Scheduled reports
+		"unit.participants_from": {One: "%d участника", Few: "%d участников", Many: "%d участников"},
+		"unit.replies":           {One: "%d ответ", Few: "%d ответа", Many: "%d ответов"},
+
+		// Rate limits, chat history and answers
+		"time.minutes_ago": {One: "%d минута назад", Few: "%d минуты назад", Many: "%d минут назад"},
+		"time.hours_ago":   {One: "%d час назад", Few: "%d часа назад", Many: "%d часов назад"},
+		"time.days_ago":    {One: "%d день назад", Few: "%d дня назад", Many: "%d дней назад"},
+		"time.weeks_ago":   {One: "%d неделя назад", Few: "%d недели назад", Many: "%d недель назад"},
+		"time.months_ago":  {One: "%d месяц назад", Few: "%d месяца назад", Many: "%d месяцев назад"},
+		"time.years_ago":   {One: "%d год назад", Few: "%d года назад", Many: "%d лет назад"},
+	},
+}
@@ -0,0 +1,149 @@
+package images
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// geminiAPIURL is the generateContent endpoint of Gemini models, formatted with the model ID
+// The genai SDK doesn't support image output, so the REST API is called directly
+const geminiAPIURL = "https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent"
+
+// geminiVariationPrompt asks for a variation of a source image, Gemini needs an instruction with every image
+const geminiVariationPrompt = "Create a variation of this image. Keep its subject, composition and style, but change the details."
+
+// geminiRequest represents the request body of generateContent
+type geminiRequest struct {
+	Contents         []geminiContent        `json:"contents"`
+	GenerationConfig geminiGenerationConfig `json:"generationConfig"`
+}
+
+// geminiContent is a turn of a generateContent request or response
+type geminiContent struct {
+	Parts []geminiPart `json:"parts"`
+}
+
+// geminiPart is text or inline data of a content
+type geminiPart struct {
+	Text       string            `json:"text,omitempty"`
+	InlineData *geminiInlineData `json:"inlineData,omitempty"`
+}
+
+// geminiInlineData is base64 encoded binary data such as an image
+type geminiInlineData struct {
+	MimeType string `json:"mimeType"`
+	Data     string `json:"data"`
+}
+
+// geminiGenerationConfig asks image models to respond with images
+type geminiGenerationConfig struct {
+	ResponseModalities []string           `json:"responseModalities"`
+	Seed               int64              `json:"seed"`
+	ImageConfig        *geminiImageConfig `json:"imageConfig,omitempty"`
+}
+
+// geminiImageConfig sets the aspect ratio, supported by newer image models such as gemini-2.5-flash-image
+type geminiImageConfig struct {
+	AspectRatio string `json:"aspectRatio"`
+}
+
+// geminiResponse represents the response body of generateContent
+type geminiResponse struct {
+	Candidates []struct {
+		Content      geminiContent `json:"content"`
+		FinishReason string        `json:"finishReason"`
+	} `json:"candidates"`
+	PromptFeedback struct {
+		BlockReason string `json:"blockReason"`
+	} `json:"promptFeedback"`
+}
+
+// geminiProvider generates images with Gemini models that can respond with images
+type geminiProvider struct {
+	client *http.Client
+	apiKey string
+}
+
+// newGeminiProvider creates a Gemini provider
+func newGeminiProvider(client *http.Client, apiKey string) *geminiProvider {
+	return &geminiProvider{client: client, apiKey: apiKey}
+}
+
+// Generate generates an image with a Gemini model, e.g. gemini-2.0-flash-preview-image-generation
+func (p *geminiProvider) Generate(ctx context.Context, modelID string, params *Params) ([]byte, error) {
+	return p.generate(ctx, modelID, params, []geminiPart{{Text: geminiPrompt(params.Prompt, params)}})
+}
+
+// Edit sends the source image along with the instruction, the image keeps its proportions unless --ar is set
+func (p *geminiProvider) Edit(ctx context.Context, modelID string, params *Params) ([]byte, error) {
+	instruction := params.Prompt
+	if instruction == "" {
+		instruction = geminiVariationPrompt
+	}
+
+	return p.generate(ctx, modelID, params, []geminiPart{
+		{Text: geminiPrompt(instruction, params)},
+		{InlineData: &geminiInlineData{
+			MimeType: http.DetectContentType(params.Source),
+			Data:     base64.StdEncoding.EncodeToString(params.Source),
+		}},
+	})
+}
+
+// geminiPrompt appends the negative prompt to a prompt, Gemini has no negative prompt
+func geminiPrompt(prompt string, params *Params) string {
+	if params.NegativePrompt != "" {
+		prompt += "\n\nAvoid: " + params.NegativePrompt
+	}
+	return prompt
+}
+
+// generate calls generateContent and returns the first image of the response
+// The aspect ratio is only sent if it isn't the default square one, so models without imageConfig support
+// still work for default requests; an empty one keeps the proportions of a source image
+func (p *geminiProvider) generate(ctx context.Context, modelID string, params *Params, parts []geminiPart) ([]byte, error) {
+	config := geminiGenerationConfig{ResponseModalities: []string{"TEXT", "IMAGE"}, Seed: params.Seed}
+	if params.AspectRatio != "" && (params.AspectRatio != DefaultAspectRatio || params.Source != nil) {
+		config.ImageConfig = &geminiImageConfig{AspectRatio: params.AspectRatio}
+	}
+
+	data, err := postJSON(ctx, p.client, fmt.Sprintf(geminiAPIURL, modelID),
+		map[string]string{"x-goog-api-key": p.apiKey},
+		geminiRequest{
+			Contents:         []geminiContent{{Parts: parts}},
+			GenerationConfig: config,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp geminiResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if resp.PromptFeedback.BlockReason != "" {
+		return nil, fmt.Errorf("prompt blocked: %s", resp.PromptFeedback.BlockReason)
+	}
+
+	for _, candidate := range resp.Candidates {
+		for _, part := range candidate.Content.Parts {
+			if part.InlineData == nil {
+				continue
+			}
+			image, err := base64.StdEncoding.DecodeString(part.InlineData.Data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode image: %w", err)
+			}
+			return image, nil
+		}
+	}
+
+	if len(resp.Candidates) > 0 {
+		return nil, fmt.Errorf("model returned no image, finish reason %s", resp.Candidates[0].FinishReason)
+	}
+	return nil, fmt.Errorf("model returned no candidates")
+}
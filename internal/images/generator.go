@@ -0,0 +1,314 @@
+// Package images generates images with pluggable providers, falling back across models on errors
+package images
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/telegram-llm-bot/internal/metrics"
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+// Request describes the images to generate
+type Request struct {
+	Prompt         string
+	NegativePrompt string // What the images should not contain
+	AspectRatio    string // One of AspectRatios, empty for DefaultAspectRatio
+	Seed           *int64 // Seed of the first image, the next ones use the following seeds; random if nil
+	Count          int    // Number of images, at least one
+	Model          string // Name of the model to try first, empty for the configured order
+
+	// Source is an image to edit as the prompt instructs, or to vary if the prompt is empty
+	// Only models of providers implementing Editor are tried; nil generates images from the prompt alone
+	Source []byte
+}
+
+// Params are the parameters of one image as sent to a provider
+type Params struct {
+	Prompt         string
+	NegativePrompt string
+	AspectRatio    string
+	Width          int
+	Height         int
+	Seed           int64
+	Source         []byte // Image to edit, see Request.Source; an empty AspectRatio keeps its proportions
+}
+
+// Result holds the images of a request, all generated by one model
+type Result struct {
+	Model  string // Name of the model that generated the images
+	Images []Image
+}
+
+// Image is a generated image with the seed it was generated with
+type Image struct {
+	Data []byte
+	Seed int64
+}
+
+// Provider generates images with one backend
+type Provider interface {
+	// Generate generates an image with a model of the provider, identified by its provider-specific ID
+	Generate(ctx context.Context, modelID string, params *Params) ([]byte, error)
+}
+
+// Editor is a provider that can also edit images
+type Editor interface {
+	Provider
+
+	// Edit generates an image from params.Source, following params.Prompt or varying the image if it is empty
+	Edit(ctx context.Context, modelID string, params *Params) ([]byte, error)
+}
+
+var (
+	// ErrUnknownModel means the requested model is not configured
+	ErrUnknownModel = errors.New("unknown image model")
+
+	// ErrEditingUnsupported means the requested model, or every model, can't edit images
+	ErrEditingUnsupported = errors.New("image editing is not supported")
+)
+
+// Generator generates images with the configured models
+// A failed model falls back to the next one in the configured order, the requested model is tried first
+type Generator struct {
+	models    []models.ImageModel
+	providers map[string]Provider
+	timeout   time.Duration
+	logger    zerolog.Logger
+}
+
+// NewGenerator creates an image generator of the configured models
+// Models of providers without credentials are skipped, so the generator may have no models
+func NewGenerator(config *models.BotConfig, logger zerolog.Logger) *Generator {
+	logger = logger.With().Str("component", "images").Logger()
+
+	// One client for all providers, attempts are bounded by their context
+	httpClient := &http.Client{}
+
+	providers := make(map[string]Provider)
+	if config.HuggingFaceToken != "" {
+		providers[models.ImageProviderHuggingFace] = newHuggingFaceProvider(httpClient, config.HuggingFaceToken)
+	}
+	if config.GeminiAPIKey != "" {
+		providers[models.ImageProviderGemini] = newGeminiProvider(httpClient, config.GeminiAPIKey)
+	}
+	if config.SDWebUIURL != "" {
+		providers[models.ImageProviderSDWebUI] = newSDWebUIProvider(httpClient, config.SDWebUIURL)
+	}
+
+	g := &Generator{
+		providers: providers,
+		timeout:   time.Duration(config.ImageTimeout) * time.Second,
+		logger:    logger,
+	}
+	for _, model := range config.ImageModels {
+		if providers[model.Provider] == nil {
+			logger.Warn().
+				Str("model", model.Name).
+				Str("provider", model.Provider).
+				Msg("Image provider is not configured, skipping model")
+			continue
+		}
+		g.models = append(g.models, model)
+	}
+
+	logger.Info().Int("models", len(g.models)).Msg("Image generator initialized")
+
+	return g
+}
+
+// Models returns the available models in fallback order, the first one is the default
+func (g *Generator) Models() []models.ImageModel {
+	return g.models
+}
+
+// HasModel reports whether a model of the name is available
+func (g *Generator) HasModel(name string) bool {
+	_, ok := g.model(name)
+	return ok
+}
+
+// EditModels returns the available models that can edit images, in fallback order
+func (g *Generator) EditModels() []models.ImageModel {
+	var editModels []models.ImageModel
+	for _, model := range g.models {
+		if g.canEdit(model) {
+			editModels = append(editModels, model)
+		}
+	}
+	return editModels
+}
+
+// CanEdit reports whether an available model of the name can edit images
+func (g *Generator) CanEdit(name string) bool {
+	model, ok := g.model(name)
+	return ok && g.canEdit(model)
+}
+
+// canEdit reports whether the provider of a model implements Editor
+func (g *Generator) canEdit(model models.ImageModel) bool {
+	_, ok := g.providers[model.Provider].(Editor)
+	return ok
+}
+
+// Generate generates the images of a request, falling back to the next model when one fails
+// All images come from the same model; the returned error joins the errors of all attempted models
+func (g *Generator) Generate(ctx context.Context, req *Request) (*Result, error) {
+	order, err := g.attemptOrder(req.Model)
+	if err != nil {
+		return nil, err
+	}
+	if req.Source != nil {
+		if order, err = g.editOrder(order, req.Model); err != nil {
+			return nil, err
+		}
+	}
+
+	params, err := imageParams(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for i, model := range order {
+		if ctx.Err() != nil {
+			errs = append(errs, ctx.Err())
+			break
+		}
+
+		images, err := g.generateAll(ctx, model, params)
+		if err == nil {
+			return &Result{Model: model.Name, Images: images}, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", model.Name, err))
+
+		if i < len(order)-1 {
+			metrics.ImageFallbacks.WithLabelValues(model.Name).Inc()
+			g.logger.Warn().
+				Err(err).
+				Str("model", model.Name).
+				Str("next_model", order[i+1].Name).
+				Msg("Image generation failed, falling back to the next model")
+		}
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// generateAll generates the images of all params with one model, failing if any of them fails
+func (g *Generator) generateAll(ctx context.Context, model models.ImageModel, params []Params) ([]Image, error) {
+	images := make([]Image, 0, len(params))
+	for i := range params {
+		data, err := g.generateWith(ctx, model, &params[i])
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, Image{Data: data, Seed: params[i].Seed})
+	}
+	return images, nil
+}
+
+// generateWith makes one attempt to generate an image with a model
+func (g *Generator) generateWith(ctx context.Context, model models.ImageModel, params *Params) ([]byte, error) {
+	startTime := time.Now()
+
+	ctx, cancel := context.WithTimeout(ctx, g.timeout)
+	defer cancel()
+
+	g.logger.Info().
+		Str("prompt", params.Prompt).
+		Str("model", model.Name).
+		Str("provider", model.Provider).
+		Str("aspect_ratio", params.AspectRatio).
+		Int64("seed", params.Seed).
+		Bool("edit", params.Source != nil).
+		Msg("Starting image generation")
+
+	var data []byte
+	var err error
+	if params.Source != nil {
+		data, err = g.providers[model.Provider].(Editor).Edit(ctx, model.ID, params)
+	} else {
+		data, err = g.providers[model.Provider].Generate(ctx, model.ID, params)
+	}
+	if err == nil && len(data) == 0 {
+		err = errors.New("provider returned no image")
+	}
+
+	metrics.ImageRequestDuration.
+		WithLabelValues(model.Name, model.Provider, metrics.Status(err)).
+		Observe(time.Since(startTime).Seconds())
+
+	if err != nil {
+		g.logger.Error().
+			Err(err).
+			Str("model", model.Name).
+			Str("provider", model.Provider).
+			Dur("duration", time.Since(startTime)).
+			Msg("Image generation failed")
+		return nil, err
+	}
+
+	g.logger.Info().
+		Int("size", len(data)).
+		Str("model", model.Name).
+		Dur("duration", time.Since(startTime)).
+		Msg("Image generated successfully")
+
+	return data, nil
+}
+
+// attemptOrder returns the models to try: the requested one first, then the rest in the configured order
+func (g *Generator) attemptOrder(name string) ([]models.ImageModel, error) {
+	if len(g.models) == 0 {
+		return nil, errors.New("no image models configured")
+	}
+	if name == "" {
+		return g.models, nil
+	}
+
+	requested, ok := g.model(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownModel, name)
+	}
+
+	order := []models.ImageModel{requested}
+	for _, model := range g.models {
+		if model.Name != requested.Name {
+			order = append(order, model)
+		}
+	}
+	return order, nil
+}
+
+// editOrder keeps the models of an attempt order that can edit images
+func (g *Generator) editOrder(order []models.ImageModel, requested string) ([]models.ImageModel, error) {
+	if requested != "" && !g.CanEdit(requested) {
+		return nil, fmt.Errorf("%w by %s", ErrEditingUnsupported, requested)
+	}
+
+	var editOrder []models.ImageModel
+	for _, model := range order {
+		if g.canEdit(model) {
+			editOrder = append(editOrder, model)
+		}
+	}
+	if len(editOrder) == 0 {
+		return nil, ErrEditingUnsupported
+	}
+	return editOrder, nil
+}
+
+// model finds an available model by name
+func (g *Generator) model(name string) (models.ImageModel, bool) {
+	for _, model := range g.models {
+		if model.Name == name {
+			return model, true
+		}
+	}
+	return models.ImageModel{}, false
+}
@@ -0,0 +1,52 @@
+package images
+
+import (
+	"context"
+	"net/http"
+)
+
+// Hugging Face Inference API endpoint (new router as of Nov 2025)
+// Old endpoint api-inference.huggingface.co is deprecated
+const huggingFaceAPIURL = "https://router.huggingface.co/hf-inference/models"
+
+// huggingFaceRequest represents the request body for Hugging Face API
+type huggingFaceRequest struct {
+	Inputs     string                `json:"inputs"`
+	Parameters huggingFaceParameters `json:"parameters"`
+}
+
+// huggingFaceParameters are the text-to-image parameters, models ignore the ones they don't support
+type huggingFaceParameters struct {
+	NegativePrompt string `json:"negative_prompt,omitempty"`
+	Width          int    `json:"width"`
+	Height         int    `json:"height"`
+	Seed           int64  `json:"seed"`
+}
+
+// huggingFaceProvider generates images with text-to-image models of the Hugging Face Inference API
+// The response body is the image itself
+type huggingFaceProvider struct {
+	client *http.Client
+	token  string
+}
+
+// newHuggingFaceProvider creates a Hugging Face provider
+func newHuggingFaceProvider(client *http.Client, token string) *huggingFaceProvider {
+	return &huggingFaceProvider{client: client, token: token}
+}
+
+// Generate generates an image with a Hugging Face model, e.g. black-forest-labs/FLUX.1-schnell
+func (p *huggingFaceProvider) Generate(ctx context.Context, modelID string, params *Params) ([]byte, error) {
+	return postJSON(ctx, p.client, huggingFaceAPIURL+"/"+modelID,
+		map[string]string{"Authorization": "Bearer " + p.token},
+		huggingFaceRequest{
+			Inputs: params.Prompt,
+			Parameters: huggingFaceParameters{
+				NegativePrompt: params.NegativePrompt,
+				Width:          params.Width,
+				Height:         params.Height,
+				Seed:           params.Seed,
+			},
+		},
+	)
+}
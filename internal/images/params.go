@@ -0,0 +1,129 @@
+package images
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/jpeg" // Decoders of source images, Telegram photos are JPEG
+	_ "image/png"
+	"math"
+	"math/rand"
+)
+
+const (
+	// DefaultAspectRatio is used when a request sets none
+	DefaultAspectRatio = "1:1"
+
+	// MaxSeed is the largest seed, Gemini seeds are 32-bit signed integers
+	MaxSeed = math.MaxInt32
+
+	// MaxCount is the largest number of images of one request, a Telegram media group holds up to 10
+	MaxCount = 4
+
+	// imagePixels is the area of generated images, sizes of all aspect ratios are about one megapixel
+	imagePixels = 1024 * 1024
+
+	// sizeStep is the multiple of generated image sides, as Stable Diffusion and FLUX expect
+	sizeStep = 64
+)
+
+// imageSize is the width and height of an aspect ratio
+type imageSize struct {
+	width  int
+	height int
+}
+
+// aspectRatioSizes maps supported aspect ratios to sizes of about imagePixels, multiples of sizeStep
+var aspectRatioSizes = map[string]imageSize{
+	"1:1":  {1024, 1024},
+	"4:3":  {1152, 896},
+	"3:4":  {896, 1152},
+	"3:2":  {1216, 832},
+	"2:3":  {832, 1216},
+	"16:9": {1344, 768},
+	"9:16": {768, 1344},
+	"21:9": {1536, 640},
+}
+
+// AspectRatios lists the supported aspect ratios
+var AspectRatios = []string{"1:1", "4:3", "3:4", "3:2", "2:3", "16:9", "9:16", "21:9"}
+
+// SupportedAspectRatio reports whether an aspect ratio is one of AspectRatios
+func SupportedAspectRatio(aspectRatio string) bool {
+	_, ok := aspectRatioSizes[aspectRatio]
+	return ok
+}
+
+// RandomSeed returns a random seed in [0, MaxSeed]
+func RandomSeed() int64 {
+	return rand.Int63n(MaxSeed + 1)
+}
+
+// imageParams returns the parameters of every image of a request
+// Image i uses the seed of the request plus i, so a request is reproduced by repeating its first seed
+func imageParams(req *Request) ([]Params, error) {
+	aspectRatio := req.AspectRatio
+	var size imageSize
+	if aspectRatio == "" && req.Source != nil {
+		// Edits keep the proportions of the source image
+		size = sourceSize(req.Source)
+	} else {
+		if aspectRatio == "" {
+			aspectRatio = DefaultAspectRatio
+		}
+		var ok bool
+		if size, ok = aspectRatioSizes[aspectRatio]; !ok {
+			return nil, fmt.Errorf("unsupported aspect ratio %s", aspectRatio)
+		}
+	}
+
+	count := req.Count
+	if count < 1 {
+		count = 1
+	}
+	if count > MaxCount {
+		return nil, fmt.Errorf("at most %d images can be generated at once, got %d", MaxCount, count)
+	}
+
+	seed := RandomSeed()
+	if req.Seed != nil {
+		if *req.Seed < 0 || *req.Seed > MaxSeed {
+			return nil, fmt.Errorf("seed must be between 0 and %d, got %d", MaxSeed, *req.Seed)
+		}
+		seed = *req.Seed
+	}
+
+	params := make([]Params, count)
+	for i := range params {
+		params[i] = Params{
+			Prompt:         req.Prompt,
+			NegativePrompt: req.NegativePrompt,
+			AspectRatio:    aspectRatio,
+			Width:          size.width,
+			Height:         size.height,
+			Seed:           (seed + int64(i)) % (MaxSeed + 1),
+			Source:         req.Source,
+		}
+	}
+	return params, nil
+}
+
+// sourceSize scales the size of a source image to about imagePixels, keeping its proportions
+// Images that can't be decoded get the size of DefaultAspectRatio
+func sourceSize(source []byte) imageSize {
+	config, _, err := image.DecodeConfig(bytes.NewReader(source))
+	if err != nil || config.Width == 0 || config.Height == 0 {
+		return aspectRatioSizes[DefaultAspectRatio]
+	}
+
+	scale := math.Sqrt(float64(imagePixels) / float64(config.Width*config.Height))
+	return imageSize{
+		width:  roundToStep(float64(config.Width) * scale),
+		height: roundToStep(float64(config.Height) * scale),
+	}
+}
+
+// roundToStep rounds an image side to the nearest multiple of sizeStep, at least one step
+func roundToStep(side float64) int {
+	return max(int(math.Round(side/sizeStep))*sizeStep, sizeStep)
+}
@@ -0,0 +1,103 @@
+package images
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Endpoints of the AUTOMATIC1111 web UI API (started with --api)
+const (
+	sdWebUITxt2ImgPath = "/sdapi/v1/txt2img"
+	sdWebUIImg2ImgPath = "/sdapi/v1/img2img"
+)
+
+// Denoising strengths of img2img, how far the result may depart from the source image
+const (
+	sdWebUIEditStrength      = 0.75
+	sdWebUIVariationStrength = 0.5
+)
+
+// sdWebUIRequest represents the request body of txt2img and img2img
+type sdWebUIRequest struct {
+	Prompt            string                 `json:"prompt"`
+	NegativePrompt    string                 `json:"negative_prompt,omitempty"`
+	Width             int                    `json:"width"`
+	Height            int                    `json:"height"`
+	Seed              int64                  `json:"seed"`
+	InitImages        []string               `json:"init_images,omitempty"` // Base64 encoded source images of img2img
+	DenoisingStrength float64                `json:"denoising_strength,omitempty"`
+	OverrideSettings  map[string]interface{} `json:"override_settings,omitempty"`
+}
+
+// sdWebUIResponse represents the response body of txt2img and img2img
+type sdWebUIResponse struct {
+	Images []string `json:"images"` // Base64 encoded PNGs
+}
+
+// sdWebUIProvider generates images with a Stable Diffusion web UI API such as AUTOMATIC1111 or Forge
+type sdWebUIProvider struct {
+	client  *http.Client
+	baseURL string
+}
+
+// newSDWebUIProvider creates a Stable Diffusion web UI provider
+func newSDWebUIProvider(client *http.Client, baseURL string) *sdWebUIProvider {
+	return &sdWebUIProvider{client: client, baseURL: baseURL}
+}
+
+// Generate generates an image with a checkpoint of the web UI, the loaded one if the model ID is empty
+func (p *sdWebUIProvider) Generate(ctx context.Context, modelID string, params *Params) ([]byte, error) {
+	return p.generate(ctx, sdWebUITxt2ImgPath, modelID, p.request(params))
+}
+
+// Edit redraws the source image with img2img, a variation keeps closer to it than an edit
+func (p *sdWebUIProvider) Edit(ctx context.Context, modelID string, params *Params) ([]byte, error) {
+	body := p.request(params)
+	body.InitImages = []string{base64.StdEncoding.EncodeToString(params.Source)}
+	body.DenoisingStrength = sdWebUIEditStrength
+	if params.Prompt == "" {
+		body.DenoisingStrength = sdWebUIVariationStrength
+	}
+
+	return p.generate(ctx, sdWebUIImg2ImgPath, modelID, body)
+}
+
+// request builds the request body of the common parameters
+func (p *sdWebUIProvider) request(params *Params) sdWebUIRequest {
+	return sdWebUIRequest{
+		Prompt:         params.Prompt,
+		NegativePrompt: params.NegativePrompt,
+		Width:          params.Width,
+		Height:         params.Height,
+		Seed:           params.Seed,
+	}
+}
+
+// generate calls an endpoint of the web UI and returns the first image of the response
+func (p *sdWebUIProvider) generate(ctx context.Context, path, modelID string, body sdWebUIRequest) ([]byte, error) {
+	if modelID != "" {
+		body.OverrideSettings = map[string]interface{}{"sd_model_checkpoint": modelID}
+	}
+
+	data, err := postJSON(ctx, p.client, p.baseURL+path, nil, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp sdWebUIResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if len(resp.Images) == 0 {
+		return nil, fmt.Errorf("web UI returned no images")
+	}
+
+	image, err := base64.StdEncoding.DecodeString(resp.Images[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	return image, nil
+}
@@ -0,0 +1,392 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/telegram-llm-bot/internal/metrics"
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+const (
+	// retryBaseDelay is the delay before the first retry, doubled for every next attempt
+	retryBaseDelay = 15 * time.Second
+
+	// retryMaxDelay caps the retry backoff
+	retryMaxDelay = 5 * time.Minute
+
+	// cleanupInterval is how often finished jobs are deleted
+	cleanupInterval = time.Hour
+
+	// retentionDays is how long finished jobs are kept for debugging
+	retentionDays = 7
+
+	// releaseTimeout limits returning interrupted jobs to the queue on shutdown
+	releaseTimeout = 5 * time.Second
+)
+
+// Outcomes of job attempts used in metrics
+const (
+	outcomeDone     = "done"
+	outcomeRetry    = "retry"
+	outcomeFailed   = "failed"
+	outcomeReleased = "released"
+)
+
+// Handler processes a claimed job
+// Returning nil marks the job as done, an error schedules a retry
+// unless the attempt was the last one or the error is permanent
+type Handler func(ctx context.Context, job *models.Job) error
+
+// permanentError marks an error that must not be retried
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps an error so the job fails without retries
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// Store is the durable job queue, implemented by storage.Client
+// Job state changes return false if the job is no longer leased to the owner
+type Store interface {
+	EnqueueJob(ctx context.Context, kind string, chatID int64, messageID int, userID int64, payload interface{}, maxAttempts int) (int64, error)
+	ClaimJobs(ctx context.Context, owner string, limit int, lease time.Duration) ([]models.Job, error)
+	ExtendJobLease(ctx context.Context, jobID int64, owner string, lease time.Duration) (bool, error)
+	UpdateJobPayload(ctx context.Context, jobID int64, owner string, payload interface{}) (bool, error)
+	CompleteJob(ctx context.Context, jobID int64, owner string) (bool, error)
+	RetryJob(ctx context.Context, jobID int64, owner, errMsg string, delay time.Duration) (bool, error)
+	FailJob(ctx context.Context, jobID int64, owner, errMsg string) (bool, error)
+	ReleaseJob(ctx context.Context, jobID int64, owner string) (bool, error)
+	CleanupJobs(ctx context.Context, keepDays int) (int, error)
+}
+
+// Runner claims jobs from the durable queue and runs them with registered handlers
+// Jobs are leased to the runner; the lease is extended while the job runs,
+// so jobs of a crashed instance are picked up again after the lease expires
+type Runner struct {
+	storage      Store
+	handlers     map[string]Handler
+	owner        string
+	concurrency  int
+	lease        time.Duration
+	pollInterval time.Duration
+	maxAttempts  int
+	slots        chan struct{}
+	wakeup       chan struct{}
+	wg           sync.WaitGroup
+	stopped      chan struct{} // Closed when Start stops claiming jobs
+	workCtx      context.Context
+	cancelWork   context.CancelFunc
+	onInterrupt  func(job *models.Job)
+	dispatch     func(job *models.Job, run func())
+	logger       zerolog.Logger
+}
+
+// NewRunner creates a job runner configured from the bot configuration
+func NewRunner(storage Store, config *models.BotConfig, logger zerolog.Logger) *Runner {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "bot"
+	}
+
+	workCtx, cancelWork := context.WithCancel(context.Background())
+
+	return &Runner{
+		storage:      storage,
+		handlers:     make(map[string]Handler),
+		owner:        fmt.Sprintf("%s-%d-%d", hostname, os.Getpid(), time.Now().UnixNano()),
+		concurrency:  config.JobsConcurrency,
+		lease:        time.Duration(config.JobsLeaseSeconds) * time.Second,
+		pollInterval: time.Duration(config.JobsPollIntervalSeconds) * time.Second,
+		maxAttempts:  config.JobsMaxAttempts,
+		slots:        make(chan struct{}, config.JobsConcurrency),
+		wakeup:       make(chan struct{}, 1),
+		stopped:      make(chan struct{}),
+		workCtx:      workCtx,
+		cancelWork:   cancelWork,
+		dispatch:     func(_ *models.Job, run func()) { go run() },
+		logger:       logger.With().Str("component", "jobs").Logger(),
+	}
+}
+
+// Register sets the handler of a job kind. Must be called before Start
+func (r *Runner) Register(kind string, handler Handler) {
+	r.handlers[kind] = handler
+}
+
+// SetInterruptCallback sets a function called for jobs interrupted by shutdown
+// Such jobs are returned to the queue and resumed after restart
+func (r *Runner) SetInterruptCallback(callback func(job *models.Job)) {
+	r.onInterrupt = callback
+}
+
+// SetDispatcher sets a function that schedules claimed jobs, e.g. to keep the order of jobs of a chat
+// It is called in claim order and must call run exactly once. By default every job runs right away.
+// Must be called before Start
+func (r *Runner) SetDispatcher(dispatch func(job *models.Job, run func())) {
+	r.dispatch = dispatch
+}
+
+// Enqueue adds a job for a message to the queue and wakes up the runner
+// A message gets at most one job of each kind
+func (r *Runner) Enqueue(ctx context.Context, kind string, chatID int64, messageID int, userID int64, payload interface{}) (int64, error) {
+	if _, ok := r.handlers[kind]; !ok {
+		return 0, fmt.Errorf("no handler registered for job kind %s", kind)
+	}
+
+	jobID, err := r.storage.EnqueueJob(ctx, kind, chatID, messageID, userID, payload, r.maxAttempts)
+	if err != nil {
+		return 0, err
+	}
+
+	select {
+	case r.wakeup <- struct{}{}:
+	default:
+	}
+
+	return jobID, nil
+}
+
+// SavePayload stores a new payload of a running job, so a retry reuses what the attempt already computed
+// Jobs run directly without the queue (ID 0) only get the payload updated in memory
+func (r *Runner) SavePayload(ctx context.Context, job *models.Job, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode job payload: %w", err)
+	}
+
+	if job.ID != 0 {
+		owned, err := r.storage.UpdateJobPayload(ctx, job.ID, r.owner, payload)
+		if err != nil {
+			return err
+		}
+		if !owned {
+			return fmt.Errorf("job lease was lost")
+		}
+	}
+
+	job.Payload = data
+	return nil
+}
+
+// Start claims and runs jobs until the context is cancelled
+// Running jobs are not interrupted by cancellation, call Shutdown to drain them
+func (r *Runner) Start(ctx context.Context) error {
+	defer close(r.stopped)
+
+	r.logger.Info().
+		Str("owner", r.owner).
+		Int("concurrency", r.concurrency).
+		Dur("lease", r.lease).
+		Int("max_attempts", r.maxAttempts).
+		Msg("Job runner started")
+
+	pollTicker := time.NewTicker(r.pollInterval)
+	defer pollTicker.Stop()
+
+	cleanupTicker := time.NewTicker(cleanupInterval)
+	defer cleanupTicker.Stop()
+
+	for {
+		r.claimAndRun(ctx)
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info().Msg("Stopped claiming jobs")
+			return nil
+		case <-pollTicker.C:
+		case <-r.wakeup:
+		case <-cleanupTicker.C:
+			if _, err := r.storage.CleanupJobs(ctx, retentionDays); err != nil {
+				r.logger.Warn().Err(err).Msg("Failed to clean up finished jobs")
+			}
+		}
+	}
+}
+
+// Shutdown waits for running jobs to finish until ctx expires
+// Jobs still running after that are cancelled and returned to the queue
+func (r *Runner) Shutdown(ctx context.Context) {
+	select {
+	case <-r.stopped:
+	case <-ctx.Done():
+	}
+
+	r.logger.Info().Msg("Waiting for running jobs to finish...")
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		r.logger.Warn().Msg("Shutdown deadline exceeded, interrupting running jobs")
+		r.cancelWork()
+		<-done
+	}
+
+	r.cancelWork()
+	r.logger.Info().Msg("Job runner stopped")
+}
+
+// claimAndRun claims as many jobs as there are free worker slots and runs them
+func (r *Runner) claimAndRun(ctx context.Context) {
+	free := r.concurrency - len(r.slots)
+	if free <= 0 || ctx.Err() != nil {
+		return
+	}
+
+	jobs, err := r.storage.ClaimJobs(ctx, r.owner, free, r.lease)
+	if err != nil {
+		r.logger.Warn().Err(err).Msg("Failed to claim jobs")
+		return
+	}
+
+	for i := range jobs {
+		job := &jobs[i]
+		r.slots <- struct{}{}
+		r.wg.Add(1)
+
+		// The lease is extended from the claim on, as the job may wait for its turn in the dispatcher
+		jobCtx, cancel := context.WithCancel(r.workCtx)
+		logger := r.logger.With().
+			Int64("job_id", job.ID).
+			Str("kind", job.Kind).
+			Int64("chat_id", job.ChatID).
+			Int("attempt", job.Attempts).
+			Logger()
+		go r.keepLease(jobCtx, cancel, job, logger)
+
+		r.dispatch(job, func() {
+			defer func() {
+				cancel()
+				<-r.slots
+				r.wg.Done()
+			}()
+			r.run(jobCtx, job, logger)
+		})
+	}
+}
+
+// run executes a job and records the outcome
+func (r *Runner) run(ctx context.Context, job *models.Job, logger zerolog.Logger) {
+	handler, ok := r.handlers[job.Kind]
+	if !ok {
+		logger.Error().Msg("No handler registered for job kind")
+		r.finish(job, outcomeFailed, fmt.Errorf("unknown job kind %s", job.Kind), logger)
+		return
+	}
+
+	logger.Debug().Msg("Running job")
+	startTime := time.Now()
+	err := r.safeRun(ctx, handler, job)
+	metrics.JobDuration.WithLabelValues(job.Kind).Observe(time.Since(startTime).Seconds())
+
+	var permanent *permanentError
+	switch {
+	case err == nil:
+		r.finish(job, outcomeDone, nil, logger)
+	case r.workCtx.Err() != nil:
+		// Interrupted by shutdown, the next start picks the job up again
+		r.finish(job, outcomeReleased, err, logger)
+		if r.onInterrupt != nil {
+			r.onInterrupt(job)
+		}
+	case errors.As(err, &permanent) || job.IsLastAttempt():
+		r.finish(job, outcomeFailed, err, logger)
+	default:
+		r.finish(job, outcomeRetry, err, logger)
+	}
+}
+
+// safeRun calls the handler, converting panics into permanent errors
+func (r *Runner) safeRun(ctx context.Context, handler Handler, job *models.Job) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = Permanent(fmt.Errorf("panic in job handler: %v", p))
+		}
+	}()
+
+	return handler(ctx, job)
+}
+
+// keepLease extends the job lease until the job finishes
+// The job is cancelled if the lease was taken over by another worker
+func (r *Runner) keepLease(ctx context.Context, cancel context.CancelFunc, job *models.Job, logger zerolog.Logger) {
+	ticker := time.NewTicker(r.lease / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			owned, err := r.storage.ExtendJobLease(ctx, job.ID, r.owner, r.lease)
+			if err != nil {
+				logger.Warn().Err(err).Msg("Failed to extend job lease")
+				continue
+			}
+			if !owned {
+				logger.Warn().Msg("Job lease lost, cancelling job")
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// finish stores the outcome of a job attempt
+// Uses a fresh context so outcomes are stored during shutdown too
+func (r *Runner) finish(job *models.Job, outcome string, jobErr error, logger zerolog.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancel()
+
+	var owned bool
+	var err error
+	switch outcome {
+	case outcomeDone:
+		owned, err = r.storage.CompleteJob(ctx, job.ID, r.owner)
+		logger.Info().Msg("Job done")
+	case outcomeReleased:
+		owned, err = r.storage.ReleaseJob(ctx, job.ID, r.owner)
+		logger.Info().Msg("Job interrupted, returned to the queue")
+	case outcomeFailed:
+		owned, err = r.storage.FailJob(ctx, job.ID, r.owner, jobErr.Error())
+		logger.Error().Err(jobErr).Msg("Job failed")
+	case outcomeRetry:
+		delay := retryDelay(job.Attempts)
+		owned, err = r.storage.RetryJob(ctx, job.ID, r.owner, jobErr.Error(), delay)
+		logger.Warn().Err(jobErr).Dur("retry_in", delay).Msg("Job attempt failed, retrying")
+	}
+
+	metrics.JobsProcessed.WithLabelValues(job.Kind, outcome).Inc()
+
+	if err != nil {
+		logger.Error().Err(err).Str("outcome", outcome).Msg("Failed to store job outcome")
+	} else if !owned {
+		logger.Warn().Str("outcome", outcome).Msg("Job lease was lost before storing the outcome")
+	}
+}
+
+// retryDelay returns the exponential backoff before the next attempt
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, retryMaxDelay)
+}
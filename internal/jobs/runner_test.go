@@ -0,0 +1,402 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 15 * time.Second},
+		{1, 15 * time.Second},
+		{2, 30 * time.Second},
+		{3, time.Minute},
+		{4, 2 * time.Minute},
+		{5, 4 * time.Minute},
+		{6, 5 * time.Minute},
+		{7, 5 * time.Minute},
+		{100, 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := retryDelay(tt.attempts); got != tt.want {
+			t.Errorf("retryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestPermanent(t *testing.T) {
+	cause := errors.New("invalid payload")
+
+	tests := []struct {
+		name          string
+		err           error
+		wantPermanent bool
+	}{
+		{name: "plain error", err: cause},
+		{name: "permanent", err: Permanent(cause), wantPermanent: true},
+		{name: "wrapped permanent", err: errors.Join(errors.New("attempt failed"), Permanent(cause)), wantPermanent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var permanent *permanentError
+			if got := errors.As(tt.err, &permanent); got != tt.wantPermanent {
+				t.Errorf("errors.As(permanentError) = %v, want %v", got, tt.wantPermanent)
+			}
+			if !errors.Is(tt.err, cause) {
+				t.Error("the cause is not unwrapped")
+			}
+		})
+	}
+}
+
+func TestRunnerOutcomes(t *testing.T) {
+	tests := []struct {
+		name         string
+		kind         string
+		attempts     int // Attempts before the claim
+		handler      Handler
+		wantStatus   string
+		wantAttempts int
+		wantError    string
+		wantDelay    time.Duration
+	}{
+		{
+			name:         "success",
+			kind:         "test",
+			handler:      func(context.Context, *models.Job) error { return nil },
+			wantStatus:   models.JobStatusDone,
+			wantAttempts: 1,
+		},
+		{
+			name:         "retryable error",
+			kind:         "test",
+			handler:      func(context.Context, *models.Job) error { return errors.New("timeout") },
+			wantStatus:   models.JobStatusQueued,
+			wantAttempts: 1,
+			wantError:    "timeout",
+			wantDelay:    retryBaseDelay,
+		},
+		{
+			name:         "retryable error backs off",
+			kind:         "test",
+			attempts:     1,
+			handler:      func(context.Context, *models.Job) error { return errors.New("timeout") },
+			wantStatus:   models.JobStatusQueued,
+			wantAttempts: 2,
+			wantError:    "timeout",
+			wantDelay:    2 * retryBaseDelay,
+		},
+		{
+			name:         "retryable error on the last attempt",
+			kind:         "test",
+			attempts:     2,
+			handler:      func(context.Context, *models.Job) error { return errors.New("timeout") },
+			wantStatus:   models.JobStatusFailed,
+			wantAttempts: 3,
+			wantError:    "timeout",
+		},
+		{
+			name:         "permanent error",
+			kind:         "test",
+			handler:      func(context.Context, *models.Job) error { return Permanent(errors.New("invalid payload")) },
+			wantStatus:   models.JobStatusFailed,
+			wantAttempts: 1,
+			wantError:    "invalid payload",
+		},
+		{
+			name:         "panic",
+			kind:         "test",
+			handler:      func(context.Context, *models.Job) error { panic("nil map") },
+			wantStatus:   models.JobStatusFailed,
+			wantAttempts: 1,
+			wantError:    "panic in job handler: nil map",
+		},
+		{
+			name:         "unknown kind",
+			kind:         "unknown",
+			wantStatus:   models.JobStatusFailed,
+			wantAttempts: 1,
+			wantError:    "unknown job kind unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newFakeStore()
+			id := store.add(models.Job{Kind: tt.kind, ChatID: 1, Attempts: tt.attempts, MaxAttempts: 3})
+
+			r := newTestRunner(store)
+			if tt.handler != nil {
+				r.Register("test", tt.handler)
+			}
+			r.claimAndRun(context.Background())
+			r.wg.Wait()
+
+			job := store.get(id)
+			if job.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", job.Status, tt.wantStatus)
+			}
+			if job.Attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", job.Attempts, tt.wantAttempts)
+			}
+			if !strings.Contains(job.LastError, tt.wantError) || (tt.wantError == "") != (job.LastError == "") {
+				t.Errorf("last error = %q, want %q", job.LastError, tt.wantError)
+			}
+			if delay := store.delay(id); delay != tt.wantDelay {
+				t.Errorf("retry delay = %v, want %v", delay, tt.wantDelay)
+			}
+		})
+	}
+}
+
+func TestRunnerShutdownReleasesRunningJob(t *testing.T) {
+	store := newFakeStore()
+	id := store.add(models.Job{Kind: "test", ChatID: 1, MaxAttempts: 3})
+
+	started := make(chan struct{})
+	r := newTestRunner(store)
+	r.Register("test", func(ctx context.Context, job *models.Job) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	var interrupted []int64
+	r.SetInterruptCallback(func(job *models.Job) {
+		interrupted = append(interrupted, job.ID)
+	})
+
+	r.claimAndRun(context.Background())
+	<-started
+
+	// The deadline has passed, so the running job is interrupted right away
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r.Shutdown(ctx)
+
+	job := store.get(id)
+	if job.Status != models.JobStatusQueued {
+		t.Errorf("status = %s, want %s", job.Status, models.JobStatusQueued)
+	}
+	if job.Attempts != 0 {
+		t.Errorf("attempts = %d, want the interrupted attempt not counted", job.Attempts)
+	}
+	if len(interrupted) != 1 || interrupted[0] != id {
+		t.Errorf("interrupt callback called for %v, want [%d]", interrupted, id)
+	}
+}
+
+func TestRunnerStartRunsEnqueuedJobs(t *testing.T) {
+	store := newFakeStore()
+	r := newTestRunner(store)
+
+	var mu sync.Mutex
+	var ran, dispatched []int64
+	r.Register("test", func(_ context.Context, job *models.Job) error {
+		mu.Lock()
+		ran = append(ran, job.ID)
+		mu.Unlock()
+		return nil
+	})
+	r.SetDispatcher(func(job *models.Job, run func()) {
+		mu.Lock()
+		dispatched = append(dispatched, job.ID)
+		mu.Unlock()
+		run()
+	})
+
+	if _, err := r.Enqueue(context.Background(), "other", 1, 1, 1, nil); err == nil {
+		t.Error("Enqueue accepted a job kind without a handler")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { _ = r.Start(ctx) }()
+
+	first, err := r.Enqueue(ctx, "test", 1, 10, 1, nil)
+	if err != nil {
+		t.Fatalf("Enqueue() unexpected error: %v", err)
+	}
+	second, err := r.Enqueue(ctx, "test", 1, 11, 1, nil)
+	if err != nil {
+		t.Fatalf("Enqueue() unexpected error: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for store.get(first).Status != models.JobStatusDone || store.get(second).Status != models.JobStatusDone {
+		if time.Now().After(deadline) {
+			t.Fatal("enqueued jobs were not run")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+	r.Shutdown(shutdownCtx)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(ran) != 2 || len(dispatched) != 2 || dispatched[0] != first || dispatched[1] != second {
+		t.Errorf("ran %v, dispatched %v, want jobs %d and %d in order", ran, dispatched, first, second)
+	}
+}
+
+// newTestRunner creates a runner of the store that claims two jobs at a time
+func newTestRunner(store Store) *Runner {
+	config := &models.BotConfig{
+		JobsConcurrency:         2,
+		JobsLeaseSeconds:        30,
+		JobsMaxAttempts:         3,
+		JobsPollIntervalSeconds: 1,
+	}
+	return NewRunner(store, config, zerolog.Nop())
+}
+
+// fakeStore is an in-memory Store following the state changes of the bot_jobs functions
+type fakeStore struct {
+	mu     sync.Mutex
+	jobs   []*models.Job // In ID order, claimed first in first out
+	owners map[int64]string
+	delays map[int64]time.Duration
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		owners: make(map[int64]string),
+		delays: make(map[int64]time.Duration),
+	}
+}
+
+// add queues a job and returns its ID
+func (s *fakeStore) add(job models.Job) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job.ID = int64(len(s.jobs) + 1)
+	job.Status = models.JobStatusQueued
+	s.jobs = append(s.jobs, &job)
+	return job.ID
+}
+
+// get returns a copy of the job
+func (s *fakeStore) get(id int64) models.Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.jobs[id-1]
+}
+
+// delay returns the last retry delay of the job
+func (s *fakeStore) delay(id int64) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.delays[id]
+}
+
+// leased returns the job if it is running with the owner's lease. Must be called with mu held
+func (s *fakeStore) leased(id int64, owner string) *models.Job {
+	if id < 1 || int(id) > len(s.jobs) {
+		return nil
+	}
+	job := s.jobs[id-1]
+	if job.Status != models.JobStatusRunning || s.owners[id] != owner {
+		return nil
+	}
+	return job
+}
+
+func (s *fakeStore) EnqueueJob(_ context.Context, kind string, chatID int64, _ int, userID int64, payload interface{}, maxAttempts int) (int64, error) {
+	return s.add(models.Job{Kind: kind, ChatID: chatID, UserID: userID, MaxAttempts: maxAttempts}), nil
+}
+
+func (s *fakeStore) ClaimJobs(_ context.Context, owner string, limit int, _ time.Duration) ([]models.Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var claimed []models.Job
+	for _, job := range s.jobs {
+		if len(claimed) == limit {
+			break
+		}
+		if job.Status != models.JobStatusQueued {
+			continue
+		}
+		job.Status = models.JobStatusRunning
+		job.Attempts++
+		s.owners[job.ID] = owner
+		claimed = append(claimed, *job)
+	}
+	return claimed, nil
+}
+
+func (s *fakeStore) ExtendJobLease(_ context.Context, jobID int64, owner string, _ time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.leased(jobID, owner) != nil, nil
+}
+
+func (s *fakeStore) UpdateJobPayload(_ context.Context, jobID int64, owner string, _ interface{}) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.leased(jobID, owner) != nil, nil
+}
+
+func (s *fakeStore) CompleteJob(_ context.Context, jobID int64, owner string) (bool, error) {
+	return s.finish(jobID, owner, models.JobStatusDone, ""), nil
+}
+
+func (s *fakeStore) RetryJob(_ context.Context, jobID int64, owner, errMsg string, delay time.Duration) (bool, error) {
+	s.mu.Lock()
+	s.delays[jobID] = delay
+	s.mu.Unlock()
+	return s.finish(jobID, owner, models.JobStatusQueued, errMsg), nil
+}
+
+func (s *fakeStore) FailJob(_ context.Context, jobID int64, owner, errMsg string) (bool, error) {
+	return s.finish(jobID, owner, models.JobStatusFailed, errMsg), nil
+}
+
+func (s *fakeStore) ReleaseJob(_ context.Context, jobID int64, owner string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job := s.leased(jobID, owner)
+	if job == nil {
+		return false, nil
+	}
+	job.Status = models.JobStatusQueued
+	job.Attempts = max(job.Attempts-1, 0)
+	delete(s.owners, jobID)
+	return true, nil
+}
+
+func (s *fakeStore) CleanupJobs(context.Context, int) (int, error) {
+	return 0, nil
+}
+
+// finish ends the lease of a running job with the status
+func (s *fakeStore) finish(jobID int64, owner, status, errMsg string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job := s.leased(jobID, owner)
+	if job == nil {
+		return false
+	}
+	job.Status = status
+	if errMsg != "" {
+		job.LastError = errMsg
+	}
+	delete(s.owners, jobID)
+	return true
+}
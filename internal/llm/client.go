@@ -9,6 +9,9 @@ import (
 
 	"github.com/google/generative-ai-go/genai"
 	"github.com/rs/zerolog"
+	"github.com/telegram-llm-bot/internal/health"
+	"github.com/telegram-llm-bot/internal/i18n"
+	"github.com/telegram-llm-bot/internal/metrics"
 	"github.com/telegram-llm-bot/internal/models"
 	"google.golang.org/api/option"
 )
@@ -21,8 +24,17 @@ type Client struct {
 	logger      zerolog.Logger
 	genaiClient *genai.Client
 	mu          sync.Mutex
+
+	// Request outcomes for health checks
+	statsMu             sync.Mutex
+	lastSuccess         time.Time
+	lastError           string
+	consecutiveFailures int
 }
 
+// unhealthyAfterFailures is the number of consecutive failed requests after which the client is degraded
+const unhealthyAfterFailures = 3
+
 // NewClient creates a new Gemini LLM client
 func NewClient(apiKey string, timeout int, config *models.BotConfig, logger zerolog.Logger) *Client {
 	return &Client{
@@ -83,10 +95,57 @@ func (c *Client) GenerateResponse(ctx context.Context, req *models.LLMRequest) *
 
 	// Calculate execution time
 	response.ExecutionTimeMs = int(time.Since(startTime).Milliseconds())
+	c.recordOutcome(response.Error)
+	metrics.LLMRequestDuration.
+		WithLabelValues(req.ModelType.String(), metrics.Status(response.Error)).
+		Observe(time.Since(startTime).Seconds())
 
 	return response
 }
 
+// recordOutcome tracks request results for health checks
+func (c *Client) recordOutcome(err error) {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+
+	if err != nil {
+		c.lastError = err.Error()
+		c.consecutiveFailures++
+		return
+	}
+
+	c.lastSuccess = time.Now()
+	c.consecutiveFailures = 0
+}
+
+// HealthCheck reports the state of the Gemini client
+// The client is degraded after several consecutive failed requests
+func (c *Client) HealthCheck(ctx context.Context) health.Result {
+	c.mu.Lock()
+	initialized := c.genaiClient != nil
+	c.mu.Unlock()
+
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+
+	details := map[string]interface{}{
+		"client_initialized":   initialized,
+		"consecutive_failures": c.consecutiveFailures,
+	}
+	if !c.lastSuccess.IsZero() {
+		details["last_success_at"] = c.lastSuccess.UTC().Format(time.RFC3339)
+	}
+
+	if c.apiKey == "" {
+		return health.Result{Status: health.StatusFail, Error: "API key is not configured", Details: details}
+	}
+	if c.consecutiveFailures >= unhealthyAfterFailures {
+		return health.Result{Status: health.StatusDegraded, Error: c.lastError, Details: details}
+	}
+
+	return health.Result{Status: health.StatusOK, Details: details}
+}
+
 // generateWithRetry attempts to generate response with retry logic
 func (c *Client) generateWithRetry(ctx context.Context, req *models.LLMRequest) *models.LLMResponse {
 	maxRetries := 3
@@ -101,6 +160,7 @@ func (c *Client) generateWithRetry(ctx context.Context, req *models.LLMRequest)
 				Dur("backoff", backoff).
 				Int64("user_id", req.UserID).
 				Msg("Retrying LLM request")
+			metrics.LLMRetries.WithLabelValues(req.ModelType.String()).Inc()
 
 			select {
 			case <-ctx.Done():
@@ -153,12 +213,13 @@ func (c *Client) generate(ctx context.Context, req *models.LLMRequest) (*models.
 	model.SetTopK(c.config.LLMTopK)
 	model.SetMaxOutputTokens(c.config.LLMMaxTokens)
 
-	// Create prompt with or without RAG context
+	// Create prompt with or without RAG context, in the language of the user
+	tr := i18n.New(req.Locale)
 	var prompt string
 	if req.RAGContext != "" {
-		prompt = fmt.Sprintf(SystemPromptWithRAGTemplate, req.RAGContext, req.Text)
+		prompt = tr.T("llm.prompt_rag", req.RAGContext, req.Text)
 	} else {
-		prompt = fmt.Sprintf(SystemPromptTemplate, req.Text)
+		prompt = tr.T("llm.prompt", req.Text)
 	}
 
 	c.logger.Debug().
@@ -178,6 +239,17 @@ func (c *Client) generate(ctx context.Context, req *models.LLMRequest) (*models.
 		return nil, fmt.Errorf("no response candidates from LLM")
 	}
 
+	// Capture token usage reported by the API
+	var usage models.TokenUsage
+	if resp.UsageMetadata != nil {
+		usage = models.NewTokenUsage(
+			req.ModelType.String(),
+			int(resp.UsageMetadata.PromptTokenCount),
+			int(resp.UsageMetadata.CandidatesTokenCount),
+			int(resp.UsageMetadata.TotalTokenCount),
+		)
+	}
+
 	candidate := resp.Candidates[0]
 	if candidate.Content == nil || len(candidate.Content.Parts) == 0 {
 		return nil, fmt.Errorf("no content parts in response")
@@ -196,7 +268,8 @@ func (c *Client) generate(ctx context.Context, req *models.LLMRequest) (*models.
 	// Check if response exceeds max length
 	if len([]rune(text)) > MaxResponseLength {
 		runes := []rune(text)
-		fallbackRunes := []rune(FallbackMessage)
+		fallbackMessage := tr.T("llm.truncated")
+		fallbackRunes := []rune(fallbackMessage)
 		maxContentLength := MaxResponseLength - len(fallbackRunes)
 
 		// Protection against too long fallback message
@@ -211,7 +284,7 @@ func (c *Client) generate(ctx context.Context, req *models.LLMRequest) (*models.
 				Msg("Response truncated without fallback (fallback too long)")
 		} else {
 			// Normal truncation with fallback
-			text = string(runes[:maxContentLength]) + FallbackMessage
+			text = string(runes[:maxContentLength]) + fallbackMessage
 			c.logger.Warn().
 				Int64("user_id", req.UserID).
 				Str("model", req.ModelType.String()).
@@ -226,12 +299,16 @@ func (c *Client) generate(ctx context.Context, req *models.LLMRequest) (*models.
 		Str("username", req.Username).
 		Str("model", req.ModelType.String()).
 		Int("response_length", len([]rune(text))).
+		Int("prompt_tokens", usage.PromptTokens).
+		Int("candidates_tokens", usage.CandidatesTokens).
+		Float64("cost_usd", usage.CostUSD).
 		Msg("LLM response generated successfully")
 
 	return &models.LLMResponse{
 		Text:      text,
 		ModelUsed: req.ModelType.String(),
 		Length:    len([]rune(text)),
+		Usage:     usage,
 		Error:     nil,
 	}, nil
 }
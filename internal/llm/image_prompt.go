@@ -0,0 +1,108 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/telegram-llm-bot/internal/metrics"
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+// MaxImagePromptLength is the maximum length of an enhanced image prompt in characters
+// It leaves room for the rest of a photo caption, Telegram captions hold 1024 characters
+const MaxImagePromptLength = 700
+
+// imagePromptInstruction asks Flash to turn a short description in any language into an image prompt
+const imagePromptInstruction = `You write prompts for text-to-image models.
+Rewrite the user's image description as one detailed English prompt:
+- translate it to English if it is in another language
+- keep every subject, attribute and style the user asked for, and don't add text or lettering they didn't ask for
+- add concrete details of composition, lighting, colors, mood and medium
+- at most 80 words, a single paragraph of comma-separated phrases
+Reply with the prompt only, without quotes or explanations.`
+
+// EnhanceImagePrompt translates and expands an image description into a detailed English prompt with Flash
+// It is a helper call of image generation, so callers don't count it against text request limits
+func (c *Client) EnhanceImagePrompt(ctx context.Context, prompt string) (string, models.TokenUsage, error) {
+	startTime := time.Now()
+	model := models.ModelFlash.String()
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	enhanced, usage, err := c.enhanceImagePrompt(ctx, model, prompt)
+
+	metrics.LLMRequestDuration.
+		WithLabelValues(model, metrics.Status(err)).
+		Observe(time.Since(startTime).Seconds())
+
+	if err != nil {
+		return "", usage, err
+	}
+
+	c.logger.Debug().
+		Int("prompt_length", len([]rune(prompt))).
+		Int("enhanced_length", len([]rune(enhanced))).
+		Int("total_tokens", usage.TotalTokens).
+		Dur("duration", time.Since(startTime)).
+		Msg("Image prompt enhanced")
+
+	return enhanced, usage, nil
+}
+
+// enhanceImagePrompt makes the API call of EnhanceImagePrompt
+func (c *Client) enhanceImagePrompt(ctx context.Context, modelName, prompt string) (string, models.TokenUsage, error) {
+	client, err := c.getClient(ctx)
+	if err != nil {
+		return "", models.TokenUsage{}, fmt.Errorf("failed to get genai client: %w", err)
+	}
+
+	model := client.GenerativeModel(modelName)
+	model.SystemInstruction = &genai.Content{Parts: []genai.Part{genai.Text(imagePromptInstruction)}}
+	model.SetTemperature(0.7)
+	model.SetMaxOutputTokens(300)
+
+	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
+	if err != nil {
+		return "", models.TokenUsage{}, fmt.Errorf("failed to generate content: %w", err)
+	}
+
+	var usage models.TokenUsage
+	if resp.UsageMetadata != nil {
+		usage = models.NewTokenUsage(
+			modelName,
+			int(resp.UsageMetadata.PromptTokenCount),
+			int(resp.UsageMetadata.CandidatesTokenCount),
+			int(resp.UsageMetadata.TotalTokenCount),
+		)
+	}
+
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil {
+		return "", usage, fmt.Errorf("no response candidates from LLM")
+	}
+
+	var text strings.Builder
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if t, ok := part.(genai.Text); ok {
+			text.WriteString(string(t))
+		}
+	}
+
+	enhanced := strings.Trim(strings.TrimSpace(text.String()), `"'`)
+	if enhanced == "" {
+		return "", usage, fmt.Errorf("empty enhanced prompt")
+	}
+
+	// Cut overlong prompts at the last phrase that fits
+	if runes := []rune(enhanced); len(runes) > MaxImagePromptLength {
+		enhanced = string(runes[:MaxImagePromptLength])
+		if i := strings.LastIndex(enhanced, ","); i > 0 {
+			enhanced = enhanced[:i]
+		}
+	}
+
+	return enhanced, usage, nil
+}
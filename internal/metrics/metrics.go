@@ -0,0 +1,174 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes all metric names
+const namespace = "telegram_bot"
+
+// Label values of operation outcomes
+const (
+	StatusSuccess = "success"
+	StatusError   = "error"
+)
+
+var (
+	// UpdatesProcessed counts Telegram updates by type: command, mention, message, other
+	UpdatesProcessed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "updates_processed_total",
+		Help:      "Telegram updates processed by type.",
+	}, []string{"type"})
+
+	// UpdatesRejected counts commands and mentions rejected because the update queues are full
+	UpdatesRejected = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "updates_rejected_total",
+		Help:      "Commands and mentions rejected because the worker pool queues are full.",
+	})
+
+	// DispatcherQueueDepth reports the number of updates and claimed jobs waiting for a worker
+	DispatcherQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "dispatcher_queue_depth",
+		Help:      "Updates and claimed jobs queued for the worker pool.",
+	})
+
+	// LLMRequestDuration measures Gemini text generation latency including retries
+	LLMRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "llm_request_duration_seconds",
+		Help:      "Latency of LLM requests by model and status, including retries.",
+		Buckets:   []float64{0.5, 1, 2, 5, 10, 20, 30, 60, 120},
+	}, []string{"model", "status"})
+
+	// LLMRetries counts retried LLM attempts
+	LLMRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "llm_retries_total",
+		Help:      "LLM request retries by model.",
+	}, []string{"model"})
+
+	// ImageRequestDuration measures image generation attempts by model, provider and status
+	ImageRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "image_request_duration_seconds",
+		Help:      "Latency of image generation attempts by model, provider and status.",
+		Buckets:   []float64{1, 2, 5, 10, 20, 30, 60, 120},
+	}, []string{"model", "provider", "status"})
+
+	// ImageFallbacks counts image generations that fell back from a failed model to the next one
+	ImageFallbacks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "image_fallbacks_total",
+		Help:      "Image generations that fell back to the next model, by the failed model.",
+	}, []string{"model"})
+
+	// RAGSearchDuration measures RAG search latency (query embedding + vector search)
+	RAGSearchDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "rag_search_duration_seconds",
+		Help:      "Latency of RAG searches.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	// RAGSearches counts RAG searches by result: hit, miss, error
+	RAGSearches = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rag_searches_total",
+		Help:      "RAG searches by result (hit, miss, error).",
+	}, []string{"result"})
+
+	// RAGResults observes the number of messages found per RAG search
+	RAGResults = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "rag_results",
+		Help:      "Number of relevant messages found per RAG search.",
+		Buckets:   []float64{0, 1, 2, 3, 5, 10, 20},
+	})
+
+	// EmbeddingBatches counts embedding batch calls by status
+	EmbeddingBatches = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "embedding_batches_total",
+		Help:      "Embedding batch requests by status.",
+	}, []string{"status"})
+
+	// EmbeddingBatchSize observes the number of texts per embedding batch
+	EmbeddingBatchSize = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "embedding_batch_size",
+		Help:      "Number of texts per embedding batch.",
+		Buckets:   []float64{1, 5, 10, 25, 50, 100},
+	})
+
+	// StorageOperationDuration measures Supabase operations including retries
+	StorageOperationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "storage_operation_duration_seconds",
+		Help:      "Latency of Supabase operations by operation and status, including retries.",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10},
+	}, []string{"operation", "status"})
+
+	// StorageRetries counts retried Supabase operations
+	StorageRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "storage_retries_total",
+		Help:      "Supabase operation retries by operation.",
+	}, []string{"operation"})
+
+	// RateLimitRejections counts requests rejected by the rate limiter by reason
+	RateLimitRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limit_rejections_total",
+		Help:      "Requests rejected by the rate limiter by reason (tier, tokens, daily, chat_pool).",
+	}, []string{"reason"})
+
+	// JobsProcessed counts job queue attempts by kind and outcome
+	JobsProcessed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "jobs_processed_total",
+		Help:      "Job queue attempts by kind and outcome (done, retry, failed, released).",
+	}, []string{"kind", "outcome"})
+
+	// JobDuration measures the duration of job attempts
+	JobDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "job_duration_seconds",
+		Help:      "Duration of job queue attempts by kind.",
+		Buckets:   []float64{0.5, 1, 2, 5, 10, 20, 30, 60, 120},
+	}, []string{"kind"})
+
+	// SchedulerJobRuns counts scheduled job runs by job and status
+	SchedulerJobRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scheduler_job_runs_total",
+		Help:      "Scheduled job runs by job and status.",
+	}, []string{"job", "status"})
+
+	// SchedulerJobDuration measures scheduled job duration
+	SchedulerJobDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "scheduler_job_duration_seconds",
+		Help:      "Duration of scheduled jobs.",
+		Buckets:   []float64{1, 5, 15, 30, 60, 120, 300, 600},
+	}, []string{"job"})
+)
+
+// Status returns the status label value for an error
+func Status(err error) string {
+	if err != nil {
+		return StatusError
+	}
+	return StatusSuccess
+}
+
+// Handler returns the HTTP handler serving metrics in Prometheus format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"strconv"
+	"time"
+)
+
+// AnalyticsPeriod represents the time range of an analytics report
+type AnalyticsPeriod struct {
+	Name  string    // "day", "week" or "month"
+	Label string    // Human-readable label for messages
+	Since time.Time // Start of the period (inclusive)
+	Weeks int       // Number of weeks shown in trends
+}
+
+// UserActivity represents the number of actions of a user in a period
+type UserActivity struct {
+	UserID    int64  `json:"user_id"`
+	Username  string `json:"username"`
+	FirstName string `json:"first_name"`
+	Count     int64  `json:"count"`
+}
+
+// DisplayName returns the best available name of the user
+func (a *UserActivity) DisplayName() string {
+	if a.FirstName != "" {
+		return a.FirstName
+	}
+	if a.Username != "" {
+		return "@" + a.Username
+	}
+	return "id" + strconv.FormatInt(a.UserID, 10)
+}
+
+// ModelUsage represents aggregated requests to one model in a period
+type ModelUsage struct {
+	Model        string  `json:"model_used"`
+	Requests     int64   `json:"requests"`
+	Errors       int64   `json:"errors"`
+	AvgLatencyMs float64 `json:"avg_latency_ms"`
+	TotalTokens  int64   `json:"total_tokens"`
+	CostUSD      float64 `json:"cost_usd"`
+}
+
+// WeeklyUsage represents chat activity during one week
+type WeeklyUsage struct {
+	WeekStart   string `json:"week_start"` // Monday of the week, YYYY-MM-DD
+	Requests    int64  `json:"requests"`
+	Messages    int64  `json:"messages"`
+	ActiveUsers int64  `json:"active_users"`
+}
+
+// UsageReport represents aggregated bot usage in a chat
+type UsageReport struct {
+	Period           AnalyticsPeriod
+	Models           []ModelUsage
+	Weekly           []WeeklyUsage
+	ImageGenerations int64
+}
+
+// TotalRequests returns the number of requests to all models
+func (r *UsageReport) TotalRequests() int64 {
+	var total int64
+	for _, m := range r.Models {
+		total += m.Requests
+	}
+	return total
+}
+
+// TotalErrors returns the number of failed requests to all models
+func (r *UsageReport) TotalErrors() int64 {
+	var total int64
+	for _, m := range r.Models {
+		total += m.Errors
+	}
+	return total
+}
+
+// ErrorRate returns the share of failed requests in percent
+func (r *UsageReport) ErrorRate() float64 {
+	total := r.TotalRequests()
+	if total == 0 {
+		return 0
+	}
+	return float64(r.TotalErrors()) / float64(total) * 100
+}
+
+// AvgLatencyMs returns the average latency across all models weighted by requests
+func (r *UsageReport) AvgLatencyMs() float64 {
+	total := r.TotalRequests()
+	if total == 0 {
+		return 0
+	}
+	var sum float64
+	for _, m := range r.Models {
+		sum += m.AvgLatencyMs * float64(m.Requests)
+	}
+	return sum / float64(total)
+}
+
+// TotalCostUSD returns the estimated cost of all requests
+func (r *UsageReport) TotalCostUSD() float64 {
+	var total float64
+	for _, m := range r.Models {
+		total += m.CostUSD
+	}
+	return total
+}
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// Digest periods
+const (
+	DigestPeriodWeek  = "week"
+	DigestPeriodMonth = "month"
+)
+
+// ChatDigest represents a generated weekly or monthly digest from the chat_digests table
+type ChatDigest struct {
+	ID           int64     `json:"id"`
+	ChatID       int64     `json:"chat_id"`
+	Period       string    `json:"period"`       // week or month
+	PeriodStart  string    `json:"period_start"` // Format: YYYY-MM-DD in the chat's timezone
+	PeriodEnd    string    `json:"period_end"`   // Inclusive
+	SummaryText  string    `json:"summary_text"`
+	Topics       []string  `json:"topics"` // Topic lines ("emoji title"), the baseline of the next digest's trends
+	MessageCount int       `json:"message_count"`
+	ActiveUsers  int       `json:"active_users"`
+	NewUsers     int       `json:"new_users"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// DailyActivity represents messages and active users of a chat on one day
+type DailyActivity struct {
+	Date        string `json:"date"`
+	Messages    int64  `json:"messages"`
+	ActiveUsers int64  `json:"active_users"`
+}
+
+// Participant represents activity of a user in a period and when they first wrote in the chat
+type Participant struct {
+	UserActivity
+	FirstSeenAt time.Time `json:"first_seen_at"`
+}
+
+// DigestInput represents the material a digest is built from
+// Daily summaries give the structure of the period, sampled messages fill days without summaries
+type DigestInput struct {
+	ChatID         int64
+	Period         string
+	Start          string // Format: YYYY-MM-DD
+	End            string // Inclusive
+	DailySummaries []DailySummary
+	Messages       []ChatMessage
+	PreviousTopics []string // Topics of the previous digest of the same period, for trends
+}
+
+// DigestResult represents the result of digest generation
+type DigestResult struct {
+	Topics []SummaryTopic
+	Rising []SummaryTopic // Topics that grew compared to the previous period
+	Fading []SummaryTopic // Topics that faded compared to the previous period
+	Usage  TokenUsage
+}
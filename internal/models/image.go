@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// ImageGeneration represents a generated image with the parameters that reproduce it
+type ImageGeneration struct {
+	ID             int64     `json:"id,omitempty"`
+	ChatID         int64     `json:"chat_id"`
+	UserID         int64     `json:"user_id"`
+	MessageID      int       `json:"message_id"`        // Telegram message of the sent photo
+	FileID         string    `json:"file_id,omitempty"` // Telegram file ID of the sent photo
+	Prompt         string    `json:"prompt"`
+	EnhancedPrompt string    `json:"enhanced_prompt,omitempty"` // Prompt sent to the model after enhancement, empty if not enhanced
+	NegativePrompt string    `json:"negative_prompt,omitempty"`
+	AspectRatio    string    `json:"aspect_ratio"`
+	Model          string    `json:"model"` // Name of the configured image model
+	Seed           int64     `json:"seed"`
+	SourceFileID   string    `json:"source_file_id,omitempty"` // Telegram file ID of the edited photo, empty for /draw without a photo
+	CreatedAt      time.Time `json:"created_at"`
+}
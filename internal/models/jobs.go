@@ -0,0 +1,44 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Job statuses in the bot_jobs table
+const (
+	JobStatusQueued  = "queued"
+	JobStatusRunning = "running"
+	JobStatusDone    = "done"
+	JobStatusFailed  = "failed"
+)
+
+// Job kinds handled by the job queue
+const (
+	JobKindMention = "mention"
+	JobKindDraw    = "draw"
+)
+
+// Job represents a durable LLM or image request from the bot_jobs table
+type Job struct {
+	ID          int64           `json:"id"`
+	Kind        string          `json:"kind"`
+	ChatID      int64           `json:"chat_id"`
+	UserID      int64           `json:"user_id"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"` // Including the current attempt
+	MaxAttempts int             `json:"max_attempts"`
+	LastError   string          `json:"last_error,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// IsFirstAttempt reports whether the job is processed for the first time
+func (j *Job) IsFirstAttempt() bool {
+	return j.Attempts <= 1
+}
+
+// IsLastAttempt reports whether a failure of the current attempt is final
+func (j *Job) IsLastAttempt() bool {
+	return j.Attempts >= j.MaxAttempts
+}
@@ -0,0 +1,100 @@
+package models
+
+import "time"
+
+// Built-in quota tier names (seeded by deployments/supabase/schema.sql)
+const (
+	TierDefault = "default"
+	TierTrusted = "trusted"
+	TierAdmin   = "admin"
+	TierBanned  = "banned"
+)
+
+// Quota tier assignment sources, from most to least specific
+const (
+	QuotaSourceUserChat   = "user_chat"   // Tier assigned to the user in this chat
+	QuotaSourceUserGlobal = "user_global" // Tier assigned to the user in all chats
+	QuotaSourceChat       = "chat"        // Default tier assigned to the whole chat
+	QuotaSourceDefault    = "default"     // No assignment, built-in default tier
+)
+
+// QuotaTier represents a named set of daily limits from the quota_tiers table
+// A nil limit means "use the global value from configuration"
+type QuotaTier struct {
+	Name             string `json:"name"`
+	Description      string `json:"description,omitempty"`
+	ProDailyLimit    *int   `json:"pro_daily_limit"`
+	FlashDailyLimit  *int   `json:"flash_daily_limit"`
+	ImageDailyLimit  *int   `json:"image_daily_limit"`
+	DailyTokenBudget *int   `json:"daily_token_budget"`
+}
+
+// QuotaAssignment binds a tier to a user and/or chat
+// ChatID = 0 applies to all chats, UserID = 0 applies to all users of the chat
+type QuotaAssignment struct {
+	ChatID     int64     `json:"chat_id"`
+	UserID     int64     `json:"user_id"`
+	Tier       string    `json:"tier"`
+	AssignedBy int64     `json:"assigned_by,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// EffectiveLimits represents the resolved daily limits of a user in a chat
+type EffectiveLimits struct {
+	Tier             string
+	Source           string
+	ProDailyLimit    int
+	FlashDailyLimit  int
+	ImageDailyLimit  int
+	TokenDailyBudget int // 0 means unlimited
+}
+
+// IsBlocked reports whether the limits forbid all text requests
+func (l *EffectiveLimits) IsBlocked() bool {
+	return l.ProDailyLimit <= 0 && l.FlashDailyLimit <= 0
+}
+
+// ChatUsageEntry represents text requests of one user counted against the chat pool
+type ChatUsageEntry struct {
+	UserID     int64  `json:"user_id"`
+	Username   string `json:"username"`
+	FirstName  string `json:"first_name"`
+	ProCount   int    `json:"pro_count"`
+	FlashCount int    `json:"flash_count"`
+}
+
+// ChatPoolStats represents the state of a chat-wide daily request pool
+// A pool of 0 means the chat has no shared limit for that model
+type ChatPoolStats struct {
+	ChatID        int64
+	ProPool       int
+	FlashPool     int
+	ProUsed       int
+	FlashUsed     int
+	MaxUserShare  int // Max share of a pool one user may consume, in percent
+	Consumers     []ChatUsageEntry
+	ResetsInHours int
+}
+
+// UserCap returns how many requests of a pool a single user may consume
+// Returns 0 for an unlimited pool
+func (s *ChatPoolStats) UserCap(pool int) int {
+	if pool <= 0 {
+		return 0
+	}
+	if s.MaxUserShare <= 0 || s.MaxUserShare >= 100 {
+		return pool
+	}
+	// Round up so small pools still allow at least one request per user
+	return max((pool*s.MaxUserShare+99)/100, 1)
+}
+
+// UserUsage returns Pro and Flash requests of a user counted against the pool
+func (s *ChatPoolStats) UserUsage(userID int64) (int, int) {
+	for _, entry := range s.Consumers {
+		if entry.UserID == userID {
+			return entry.ProCount, entry.FlashCount
+		}
+	}
+	return 0, 0
+}
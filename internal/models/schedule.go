@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Scheduled jobs. Summaries and digests can be scheduled per chat, RAG sync only globally
+const (
+	ScheduleJobSummary = "summary"
+	ScheduleJobWeekly  = "weekly"
+	ScheduleJobMonthly = "monthly"
+	ScheduleJobSync    = "sync"
+)
+
+// ChatSchedule represents a per-chat schedule of a job from the chat_schedules table
+type ChatSchedule struct {
+	ChatID    int64     `json:"chat_id"`
+	Job       string    `json:"job"`
+	CronExpr  string    `json:"cron_expr"`
+	Enabled   bool      `json:"enabled"`
+	UpdatedBy int64     `json:"updated_by,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// ChatSettings represents per-chat settings from the chat_settings table
+// Empty values fall back to the global configuration
+type ChatSettings struct {
+	ChatID          int64         `json:"chat_id"`
+	Timezone        string        `json:"timezone,omitempty"`
+	Locale          string        `json:"locale,omitempty"`            // Language of chat-wide messages (summaries, digests)
+	SummaryReport   SummaryReport `json:"summary_report,omitempty"`    // Sections of the daily summary (/summaryconfig)
+	SummaryTemplate string        `json:"summary_template,omitempty"`  // text/template of the daily summary (/summarytemplate)
+	SummaryThreadID int64         `json:"summary_thread_id,omitempty"` // Forum topic for summaries and digests
+	SummaryPin      bool          `json:"summary_pin,omitempty"`       // Pin daily summaries, unpinning the previous one
+	UpdatedBy       int64         `json:"updated_by,omitempty"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+}
+
+// UserSettings represents per-user settings from the user_settings table
+type UserSettings struct {
+	UserID    int64     `json:"user_id"`
+	Timezone  string    `json:"timezone,omitempty"`
+	Locale    string    `json:"locale,omitempty"` // Language of replies to the user
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -1,17 +1,134 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // DailySummary represents a generated daily chat summary
 type DailySummary struct {
 	ID                 int64     `json:"id"`
 	ChatID             int64     `json:"chat_id"`
-	Date               string    `json:"date"` // Format: YYYY-MM-DD in Moscow timezone
+	Date               string    `json:"date"` // Format: YYYY-MM-DD in the chat's timezone
 	SummaryText        string    `json:"summary_text"`
 	MostActiveUserID   int64     `json:"most_active_user_id,omitempty"`
 	MostActiveUsername string    `json:"most_active_username,omitempty"`
 	MessageCount       int       `json:"message_count"`
 	CreatedAt          time.Time `json:"created_at"`
+
+	// Structured result the summary text was rendered from, nil for summaries generated before it existed
+	Data *StructuredSummary `json:"summary_data,omitempty"`
+
+	// Delivery to the chat, tracked separately so a failed send can be retried
+	DeliveryStatus   string     `json:"delivery_status,omitempty"`
+	DeliveryAttempts int        `json:"delivery_attempts"`
+	DeliveryError    string     `json:"delivery_error,omitempty"`
+	SentMessageID    int64      `json:"sent_message_id,omitempty"`
+	DeliveredAt      *time.Time `json:"delivered_at,omitempty"`
+
+	Similarity float64 `json:"similarity,omitempty"` // Similarity score from summary search
+}
+
+// EmbeddingText returns the text the summary is indexed by for semantic search
+// Structured summaries are indexed by their topics, older ones by the formatted text
+func (s *DailySummary) EmbeddingText() string {
+	if s.Data == nil || len(s.Data.Topics) == 0 {
+		return s.SummaryText
+	}
+
+	lines := make([]string, 0, len(s.Data.Topics))
+	for _, topic := range s.Data.Topics {
+		line := topic.Title
+		if topic.Description != "" {
+			line += ": " + topic.Description
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Delivery statuses of daily summaries
+const (
+	DeliveryStatusPending = "pending"
+	DeliveryStatusSent    = "sent"
+	DeliveryStatusFailed  = "failed"
+)
+
+// SummaryDelivery describes how a summary or digest is posted to a chat
+type SummaryDelivery struct {
+	ChatID         int64
+	ThreadID       int64 // Forum topic to post into, 0 for the general chat
+	Text           string
+	Pin            bool  // Pin the sent message
+	UnpinMessageID int64 // Previously pinned summary to unpin, 0 if none
+}
+
+// SummaryTopic represents one discussion topic of a structured summary
+type SummaryTopic struct {
+	Emoji        string   `json:"emoji"`
+	Title        string   `json:"title"`
+	Description  string   `json:"description,omitempty"`
+	Participants []string `json:"participants,omitempty"` // Names of key participants as they appear in the chat
+	MessageIDs   []int64  `json:"message_ids,omitempty"`  // Telegram IDs of representative messages
+}
+
+// String returns the topic as a single "emoji title" line
+func (t SummaryTopic) String() string {
+	return strings.TrimSpace(t.Emoji + " " + t.Title)
+}
+
+// SummaryQuote represents the quote of the day chosen by the model
+type SummaryQuote struct {
+	MessageID int64  `json:"message_id"`        // Telegram ID of the quoted message
+	Comment   string `json:"comment,omitempty"` // Why the message was chosen
+}
+
+// StructuredSummary represents the structured summary stored in daily_summaries.summary_data
+type StructuredSummary struct {
+	Topics []SummaryTopic `json:"topics"`
+	Quote  *SummaryQuote  `json:"quote,omitempty"`
+}
+
+// Sections of the daily summary report
+const (
+	SummarySectionTopics   = "topics"
+	SummarySectionStats    = "stats"
+	SummarySectionActivity = "activity"
+	SummarySectionTop      = "top"
+	SummarySectionQuote    = "quote"
+	SummarySectionReplied  = "replied"
+)
+
+// SummarySections lists the sections of the daily summary report in display order
+var SummarySections = []string{
+	SummarySectionTopics,
+	SummarySectionStats,
+	SummarySectionActivity,
+	SummarySectionTop,
+	SummarySectionQuote,
+	SummarySectionReplied,
+}
+
+// SummarySection configures one section of the daily summary report of a chat
+type SummarySection struct {
+	Disabled bool   `json:"disabled,omitempty"`
+	Title    string `json:"title,omitempty"` // Custom heading, empty for the default one
+}
+
+// SummaryReport maps section names to their chat configuration, missing sections use defaults
+type SummaryReport map[string]SummarySection
+
+// Enabled reports whether a section appears in the report
+func (r SummaryReport) Enabled(section string) bool {
+	return !r[section].Disabled
+}
+
+// Title returns the heading of a section, or the default one if the chat has none
+func (r SummaryReport) Title(section, fallback string) string {
+	if title := r[section].Title; title != "" {
+		return title
+	}
+	return fallback
 }
 
 // UserMessageCount represents message count statistics for a user
@@ -31,8 +148,10 @@ type SummaryRequest struct {
 
 // SummaryResult represents the result of summary generation
 type SummaryResult struct {
-	Topics         []string
+	Topics         []SummaryTopic
+	Quote          *SummaryQuote // Quote of the day, nil if the model chose none
 	MostActiveUser *UserMessageCount
 	MessageCount   int
+	Usage          TokenUsage
 	Error          error
 }
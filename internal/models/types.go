@@ -15,13 +15,6 @@ const (
 	// Used for fast responses with good quality
 	// See current rate limits: https://ai.google.dev/pricing
 	ModelFlash ModelType = "gemini-2.0-flash"
-
-	// ModelImageGeneration represents FLUX.1-schnell for image generation via Hugging Face
-	// Used for generating images from text prompts
-	// Free access through Hugging Face Inference API
-	// See: https://huggingface.co/black-forest-labs/FLUX.1-schnell
-	// Note: This constant is not used directly, see internal/llm/image_generation.go
-	ModelImageGeneration ModelType = "flux-schnell"
 )
 
 // String returns string representation of ModelType
@@ -29,44 +22,72 @@ func (m ModelType) String() string {
 	return string(m)
 }
 
+// Image generation providers, see internal/images
+const (
+	// ImageProviderHuggingFace generates images with models of the Hugging Face Inference API, e.g. FLUX.1-schnell
+	ImageProviderHuggingFace = "huggingface"
+
+	// ImageProviderGemini generates images with Gemini models that return images, e.g. gemini-2.0-flash-preview-image-generation
+	ImageProviderGemini = "gemini"
+
+	// ImageProviderSDWebUI generates images with a Stable Diffusion web UI API such as AUTOMATIC1111
+	ImageProviderSDWebUI = "sdwebui"
+)
+
+// ImageProviders lists the supported image generation providers
+var ImageProviders = []string{ImageProviderHuggingFace, ImageProviderGemini, ImageProviderSDWebUI}
+
+// ImageModel is an image generation model selectable with /draw --model
+type ImageModel struct {
+	Name     string // Short name users select the model by, e.g. "flux-schnell"
+	Provider string // One of ImageProviders
+	ID       string // Provider-specific model ID, may be empty for sdwebui (the loaded checkpoint)
+}
+
 // ChatMessage represents a message from the chat_messages table
 type ChatMessage struct {
-	ID          int64     `json:"id"`
-	MessageID   int64     `json:"message_id"`
-	UserID      int64     `json:"user_id"`
-	Username    string    `json:"username,omitempty"`
-	FirstName   string    `json:"first_name,omitempty"`
-	ChatID      int64     `json:"chat_id"`
-	MessageText string    `json:"message_text"`
-	Indexed     bool      `json:"indexed"`
-	CreatedAt   time.Time `json:"created_at"`
-	IndexedAt   time.Time `json:"indexed_at,omitempty"`
-	Similarity  float64   `json:"similarity,omitempty"` // Similarity score from RAG search
+	ID               int64     `json:"id"`
+	MessageID        int64     `json:"message_id"`
+	UserID           int64     `json:"user_id"`
+	Username         string    `json:"username,omitempty"`
+	FirstName        string    `json:"first_name,omitempty"`
+	ChatID           int64     `json:"chat_id"`
+	MessageText      string    `json:"message_text"`
+	ReplyToMessageID int64     `json:"reply_to_message_id,omitempty"` // Telegram ID of the message this one replies to
+	Indexed          bool      `json:"indexed"`
+	CreatedAt        time.Time `json:"created_at"`
+	IndexedAt        time.Time `json:"indexed_at,omitempty"`
+	Similarity       float64   `json:"similarity,omitempty"` // Similarity score from RAG search
 }
 
 // RequestLog represents a log entry for a user request
 type RequestLog struct {
-	ID              int64     `json:"id"`
-	UserID          int64     `json:"user_id"`
-	Username        string    `json:"username,omitempty"`
-	FirstName       string    `json:"first_name,omitempty"`
-	ChatID          int64     `json:"chat_id"`
-	RequestText     string    `json:"request_text"`
-	ResponseText    string    `json:"response_text"`
-	ModelUsed       string    `json:"model_used"`
-	ResponseLength  int       `json:"response_length"`
-	ExecutionTimeMs int       `json:"execution_time_ms"`
-	ErrorMessage    string    `json:"error_message,omitempty"`
-	CreatedAt       time.Time `json:"created_at"`
+	ID               int64     `json:"id"`
+	UserID           int64     `json:"user_id"`
+	Username         string    `json:"username,omitempty"`
+	FirstName        string    `json:"first_name,omitempty"`
+	ChatID           int64     `json:"chat_id"`
+	RequestText      string    `json:"request_text"`
+	ResponseText     string    `json:"response_text"`
+	ModelUsed        string    `json:"model_used"`
+	ResponseLength   int       `json:"response_length"`
+	ExecutionTimeMs  int       `json:"execution_time_ms"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CandidatesTokens int       `json:"candidates_tokens"`
+	TotalTokens      int       `json:"total_tokens"`
+	CostUSD          float64   `json:"cost_usd"`
+	ErrorMessage     string    `json:"error_message,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
 }
 
 // DailyLimit represents daily usage limits for a user
 type DailyLimit struct {
 	ID                 int64     `json:"id"`
 	UserID             int64     `json:"user_id"`
-	Date               string    `json:"date"` // Format: YYYY-MM-DD in Moscow timezone
+	Date               string    `json:"date"` // Format: YYYY-MM-DD in the global timezone (settings.LimitLocation)
 	ProRequestsCount   int       `json:"pro_requests_count"`
 	FlashRequestsCount int       `json:"flash_requests_count"`
+	TokensUsed         int       `json:"tokens_used"`
 	UpdatedAt          time.Time `json:"updated_at"`
 }
 
@@ -79,8 +100,11 @@ type UserStats struct {
 	ProRequestsLimit   int    `json:"pro_requests_limit"`
 	FlashRequestsUsed  int    `json:"flash_requests_used"`
 	FlashRequestsLimit int    `json:"flash_requests_limit"`
+	TokensUsed         int    `json:"tokens_used"`
+	TokenBudget        int    `json:"token_budget"` // 0 means unlimited
 	TotalRequests      int64  `json:"total_requests"`
 	ResetsInHours      int    `json:"resets_in_hours"`
+	Tier               string `json:"tier"`
 }
 
 // LLMRequest represents a request to LLM
@@ -93,6 +117,7 @@ type LLMRequest struct {
 	ModelType   ModelType
 	TimeoutSecs int
 	RAGContext  string // Optional RAG context to include in prompt
+	Locale      string // Language of the prompt, the default locale if empty
 }
 
 // LLMResponse represents a response from LLM
@@ -101,6 +126,7 @@ type LLMResponse struct {
 	ModelUsed       string
 	Length          int
 	ExecutionTimeMs int
+	Usage           TokenUsage
 	Error           error
 }
 
@@ -110,15 +136,47 @@ type RateLimitResult struct {
 	ModelToUse     ModelType
 	ProRemaining   int
 	FlashRemaining int
+	Tier           string
 	Message        string
 }
 
+// Telegram update receiving modes
+const (
+	TelegramModePolling = "polling"
+	TelegramModeWebhook = "webhook"
+)
+
 // BotConfig represents bot configuration
 type BotConfig struct {
 	// Telegram settings
 	TelegramToken    string
 	TelegramUsername string
 	AllowedChatIDs   []int64 // List of allowed chat IDs (supports multiple chats)
+	AdminUserIDs     []int64 // Telegram user IDs allowed to run admin commands
+	TelegramMode     string  // "polling" or "webhook"
+
+	// Webhook settings (TelegramMode = "webhook")
+	WebhookURL         string // Public HTTPS URL registered via setWebhook
+	WebhookListenAddr  string // Local listen address of the webhook server
+	WebhookSecretToken string // Verified against X-Telegram-Bot-Api-Secret-Token
+	WebhookTLSCert     string // Optional TLS certificate file for the embedded HTTPS server
+	WebhookTLSKey      string // Optional TLS key file for the embedded HTTPS server
+	WebhookSelfSigned  bool   // Upload WebhookTLSCert to Telegram as a self-signed certificate
+
+	// Update worker pool
+	WorkerPoolSize      int // Number of workers handling updates
+	WorkerQueueSize     int // Max updates queued across all chats
+	WorkerChatQueueSize int // Max updates queued per chat
+
+	// Graceful shutdown
+	ShutdownTimeout int // Seconds in-flight requests get to finish on shutdown
+
+	// Durable job queue for mentions and /draw
+	JobsEnabled             bool
+	JobsConcurrency         int // Jobs processed in parallel
+	JobsLeaseSeconds        int // Lease of a claimed job, extended while it runs
+	JobsMaxAttempts         int // Attempts before a job fails
+	JobsPollIntervalSeconds int // Interval between queue polls
 
 	// Gemini API settings
 	GeminiAPIKey  string
@@ -127,20 +185,53 @@ type BotConfig struct {
 	// Hugging Face API settings
 	HuggingFaceToken string
 
+	// Image generation models in fallback order, the first one is the default
+	ImageModels  []ImageModel
+	ImageTimeout int    // Seconds one image generation attempt may take
+	SDWebUIURL   string // Base URL of a Stable Diffusion web UI API, e.g. http://localhost:7860
+
+	// Expand /draw prompts into detailed English prompts with Flash, unless --raw is given
+	ImagePromptEnhancement bool
+
 	// Supabase settings
 	SupabaseURL     string
 	SupabaseKey     string
 	SupabaseTimeout int
 
 	// App settings
-	Timezone    string
-	LogLevel    string
-	Environment string
+	Timezone      string
+	DefaultLocale string // Language of chats and users without a configured or detected one
+	LogLevel      string
+	Environment   string
+
+	// Scheduler (cron expressions in Timezone, chats may override the summary and digest schedules)
+	SummaryCronSchedule       string
+	SyncCronSchedule          string
+	DigestWeeklyCronSchedule  string
+	DigestMonthlyCronSchedule string
+	DigestSampleSize          int // Messages sampled from the period in addition to daily summaries
+
+	// Map-reduce summarization of busy days
+	SummaryChunkSize      int // Max messages per chunk; days with more messages are summarized in chunks
+	SummaryMapConcurrency int // Chunks summarized in parallel
+	SummaryTopChatters    int // Most active participants shown in daily summaries
+
+	// Retry of failed summary deliveries
+	SummaryDeliveryRetrySchedule string // Cron schedule of delivery retries
+	SummaryDeliveryMaxAttempts   int    // Delivery attempts per summary before giving up
 
 	// Rate limits
 	ProDailyLimit   int
 	FlashDailyLimit int
 
+	// Token budget per user per day (0 = unlimited)
+	TokenDailyBudgetPerUser int
+
+	// Chat-wide daily pool of text requests shared by all users (0 = no pool)
+	ChatProDailyPool     int
+	ChatFlashDailyPool   int
+	ChatPoolMaxUserShare int // Max share of a pool one user may consume, in percent
+
 	// Image Generation Limits
 	ImageGenerationDailyLimitPerUser int
 	ImageGenerationDailyLimitPerChat int
@@ -153,6 +244,14 @@ type BotConfig struct {
 
 	// RAG Configuration
 	RAG RAGConfig
+
+	// Analytics
+	ChartsEnabled bool // Render PNG charts for /top and /usage
+
+	// HTTP server for operational endpoints
+	HTTPAddr       string // Listen address, e.g. ":8080"
+	MetricsEnabled bool   // Expose Prometheus metrics on /metrics
+	HealthEnabled  bool   // Expose /healthz and /readyz
 }
 
 // RAGConfig represents RAG (Retrieval Augmented Generation) configuration
@@ -173,6 +272,16 @@ type RAGResult struct {
 	Count     int            // Number of results found
 }
 
+// IsAdmin checks if the given user ID is in the bot admin list
+func (c *BotConfig) IsAdmin(userID int64) bool {
+	for _, adminID := range c.AdminUserIDs {
+		if adminID == userID {
+			return true
+		}
+	}
+	return false
+}
+
 // IsAllowedChat checks if the given chat ID is in the allowed list
 func (c *BotConfig) IsAllowedChat(chatID int64) bool {
 	for _, allowedID := range c.AllowedChatIDs {
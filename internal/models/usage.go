@@ -0,0 +1,88 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+// Usage components recorded in the token_usage table
+const (
+	UsageComponentSummary     = "summary"
+	UsageComponentEmbeddings  = "embeddings"
+	UsageComponentImagePrompt = "image_prompt" // Enhancement of /draw prompts
+)
+
+// ModelPricing represents the price of a model in USD per 1M tokens
+// See current prices: https://ai.google.dev/pricing
+type ModelPricing struct {
+	InputPerMillion  float64
+	OutputPerMillion float64
+}
+
+// modelPrices contains paid-tier prices for models used by the bot
+var modelPrices = map[string]ModelPricing{
+	string(ModelPro):     {InputPerMillion: 1.25, OutputPerMillion: 10.00},
+	string(ModelFlash):   {InputPerMillion: 0.10, OutputPerMillion: 0.40},
+	"text-embedding-004": {InputPerMillion: 0, OutputPerMillion: 0},
+}
+
+// TokenUsage represents token consumption of one or more Gemini calls
+type TokenUsage struct {
+	PromptTokens     int
+	CandidatesTokens int
+	ThoughtsTokens   int // Thinking tokens of thinking models, billed as output but not part of the candidates
+	TotalTokens      int
+	CostUSD          float64
+	Estimated        bool // True when the API did not report usage and tokens were estimated
+}
+
+// NewTokenUsage builds token usage for a model and calculates its cost
+// Tokens of the total beyond the prompt and candidates are thinking tokens, priced as output
+func NewTokenUsage(model string, promptTokens, candidatesTokens, totalTokens int) TokenUsage {
+	if totalTokens == 0 {
+		totalTokens = promptTokens + candidatesTokens
+	}
+	thoughtsTokens := max(totalTokens-promptTokens-candidatesTokens, 0)
+
+	return TokenUsage{
+		PromptTokens:     promptTokens,
+		CandidatesTokens: candidatesTokens,
+		ThoughtsTokens:   thoughtsTokens,
+		TotalTokens:      totalTokens,
+		CostUSD:          EstimateCost(model, promptTokens, candidatesTokens+thoughtsTokens),
+	}
+}
+
+// Add accumulates another usage into this one
+func (u *TokenUsage) Add(other TokenUsage) {
+	u.PromptTokens += other.PromptTokens
+	u.CandidatesTokens += other.CandidatesTokens
+	u.ThoughtsTokens += other.ThoughtsTokens
+	u.TotalTokens += other.TotalTokens
+	u.CostUSD += other.CostUSD
+	u.Estimated = u.Estimated || other.Estimated
+}
+
+// EstimateCost calculates the cost in USD of a call with the given token counts
+// Output tokens are the candidates and thinking tokens; unknown models are treated as free
+func EstimateCost(model string, promptTokens, outputTokens int) float64 {
+	pricing, ok := modelPrices[model]
+	if !ok {
+		return 0
+	}
+	return float64(promptTokens)/1e6*pricing.InputPerMillion +
+		float64(outputTokens)/1e6*pricing.OutputPerMillion
+}
+
+// UsageRecord represents a row in the token_usage table
+type UsageRecord struct {
+	Component string
+	Model     string
+	ChatID    int64
+	UserID    int64
+	Usage     TokenUsage
+	CreatedAt time.Time
+}
+
+// UsageCallback receives token usage of background Gemini calls (summaries, embeddings)
+type UsageCallback func(ctx context.Context, record *UsageRecord)
@@ -10,4 +10,11 @@ const (
 
 	// DefaultMaxContextLength is the default maximum context length in characters
 	DefaultMaxContextLength = 2000
+
+	// SummarySearchTopK is the number of daily summaries returned by summary search
+	SummarySearchTopK = 5
+
+	// SummarySimilarityThreshold is the minimum similarity of summary search results.
+	// Summaries cover many topics, so a query matches them less closely than a single message
+	SummarySimilarityThreshold = 0.6
 )
@@ -9,6 +9,8 @@ import (
 
 	"github.com/rs/zerolog"
 	"github.com/telegram-llm-bot/internal/embeddings"
+	"github.com/telegram-llm-bot/internal/i18n"
+	"github.com/telegram-llm-bot/internal/metrics"
 	"github.com/telegram-llm-bot/internal/models"
 	"github.com/telegram-llm-bot/internal/storage"
 )
@@ -36,8 +38,8 @@ func NewSearcher(
 	}
 }
 
-// Search performs RAG search for relevant messages
-func (s *Searcher) Search(ctx context.Context, query string, chatID int64) (*models.RAGResult, error) {
+// Search performs RAG search for relevant messages, the context is formatted in the language of tr
+func (s *Searcher) Search(ctx context.Context, query string, chatID int64, tr *i18n.Localizer) (*models.RAGResult, error) {
 	if !s.config.Enabled {
 		s.logger.Debug().Msg("RAG is disabled")
 		return &models.RAGResult{
@@ -57,6 +59,7 @@ func (s *Searcher) Search(ctx context.Context, query string, chatID int64) (*mod
 
 	queryEmbedding, err := s.embeddingsClient.GenerateEmbedding(ctx, query)
 	if err != nil {
+		metrics.RAGSearches.WithLabelValues(metrics.StatusError).Inc()
 		return nil, fmt.Errorf("failed to generate query embedding: %w", err)
 	}
 
@@ -75,11 +78,21 @@ func (s *Searcher) Search(ctx context.Context, query string, chatID int64) (*mod
 	)
 
 	if err != nil {
+		metrics.RAGSearches.WithLabelValues(metrics.StatusError).Inc()
 		return nil, fmt.Errorf("failed to search similar messages: %w", err)
 	}
 
+	// Record search latency and hits
+	metrics.RAGSearchDuration.Observe(time.Since(startTime).Seconds())
+	metrics.RAGResults.Observe(float64(len(similarMessages)))
+	if len(similarMessages) > 0 {
+		metrics.RAGSearches.WithLabelValues("hit").Inc()
+	} else {
+		metrics.RAGSearches.WithLabelValues("miss").Inc()
+	}
+
 	// 3. Format context
-	context := s.FormatContext(similarMessages)
+	context := s.FormatContext(similarMessages, tr)
 
 	// 4. Create result
 	result := &models.RAGResult{
@@ -97,14 +110,38 @@ func (s *Searcher) Search(ctx context.Context, query string, chatID int64) (*mod
 	return result, nil
 }
 
+// SearchSummaries finds the daily summaries of a chat most similar to the query
+// Works even if RAG for mentions is disabled, summaries are indexed by the same sync job
+func (s *Searcher) SearchSummaries(ctx context.Context, query string, chatID int64) ([]models.DailySummary, error) {
+	startTime := time.Now()
+
+	queryEmbedding, err := s.embeddingsClient.GenerateEmbedding(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate query embedding: %w", err)
+	}
+
+	summaries, err := s.storage.SearchSimilarSummaries(ctx, queryEmbedding, SummarySimilarityThreshold, SummarySearchTopK, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search similar summaries: %w", err)
+	}
+
+	s.logger.Info().
+		Int64("chat_id", chatID).
+		Int("results_count", len(summaries)).
+		Dur("duration", time.Since(startTime)).
+		Msg("Summary search completed")
+
+	return summaries, nil
+}
+
 // FormatContext formats search results into a context string for LLM
-func (s *Searcher) FormatContext(messages []*models.ChatMessage) string {
+func (s *Searcher) FormatContext(messages []*models.ChatMessage, tr *i18n.Localizer) string {
 	if len(messages) == 0 {
 		return ""
 	}
 
 	var builder strings.Builder
-	builder.WriteString("РЕЛЕВАНТНАЯ ИНФОРМАЦИЯ ИЗ ИСТОРИИ ЧАТА:\n\n")
+	builder.WriteString(tr.T("rag.header"))
 
 	totalLength := 0
 	maxLength := s.config.MaxContextLength
@@ -112,15 +149,14 @@ func (s *Searcher) FormatContext(messages []*models.ChatMessage) string {
 	for i, msg := range messages {
 		// Format: "1. Вася (2 дня назад, similarity: 0.89): сообщение"
 		author := formatAuthor(msg)
-		timeAgo := formatTimeAgo(msg.CreatedAt)
+		timeAgo := formatTimeAgo(msg.CreatedAt, tr)
 		similarity := fmt.Sprintf("%.2f", msg.Similarity)
 
-		entry := fmt.Sprintf("%d. %s (%s, релевантность: %s): \"%s\"\n",
-			i+1, author, timeAgo, similarity, msg.MessageText)
+		entry := tr.T("rag.entry", i+1, author, timeAgo, similarity, msg.MessageText)
 
 		entryRunes := utf8.RuneCountInString(entry)
 		if totalLength+entryRunes > maxLength {
-			builder.WriteString(fmt.Sprintf("\n[... еще %d релевантных сообщений не показаны из-за ограничения длины]\n", len(messages)-i))
+			builder.WriteString(tr.T("rag.truncated", len(messages)-i))
 			break
 		}
 
@@ -143,57 +179,27 @@ func formatAuthor(msg *models.ChatMessage) string {
 	return fmt.Sprintf("User_%d", msg.UserID)
 }
 
-// formatTimeAgo formats time ago in Russian
-func formatTimeAgo(t time.Time) string {
+// formatTimeAgo formats time ago in the language of tr
+func formatTimeAgo(t time.Time, tr *i18n.Localizer) string {
 	now := time.Now()
 	diff := now.Sub(t)
 
 	switch {
 	case diff < time.Minute:
-		return "только что"
+		return tr.T("time.just_now")
 	case diff < time.Hour:
-		minutes := int(diff.Minutes())
-		return fmt.Sprintf("%d %s назад", minutes, pluralizeRu(minutes, "минута", "минуты", "минут"))
+		return tr.N("time.minutes_ago", int(diff.Minutes()))
 	case diff < 24*time.Hour:
-		hours := int(diff.Hours())
-		return fmt.Sprintf("%d %s назад", hours, pluralizeRu(hours, "час", "часа", "часов"))
+		return tr.N("time.hours_ago", int(diff.Hours()))
 	case diff < 7*24*time.Hour:
-		days := int(diff.Hours() / 24)
-		return fmt.Sprintf("%d %s назад", days, pluralizeRu(days, "день", "дня", "дней"))
+		return tr.N("time.days_ago", int(diff.Hours()/24))
 	case diff < 30*24*time.Hour:
-		weeks := int(diff.Hours() / 24 / 7)
-		return fmt.Sprintf("%d %s назад", weeks, pluralizeRu(weeks, "неделя", "недели", "недель"))
+		return tr.N("time.weeks_ago", int(diff.Hours()/24/7))
 	case diff < 365*24*time.Hour:
-		months := int(diff.Hours() / 24 / 30)
-		return fmt.Sprintf("%d %s назад", months, pluralizeRu(months, "месяц", "месяца", "месяцев"))
+		return tr.N("time.months_ago", int(diff.Hours()/24/30))
 	default:
-		years := int(diff.Hours() / 24 / 365)
-		return fmt.Sprintf("%d %s назад", years, pluralizeRu(years, "год", "года", "лет"))
-	}
-}
-
-// pluralizeRu returns correct Russian plural form
-func pluralizeRu(n int, form1, form2, form5 string) string {
-	n = abs(n) % 100
-	if n >= 11 && n <= 19 {
-		return form5
-	}
-	n = n % 10
-	if n == 1 {
-		return form1
-	}
-	if n >= 2 && n <= 4 {
-		return form2
-	}
-	return form5
-}
-
-// abs returns absolute value
-func abs(n int) int {
-	if n < 0 {
-		return -n
+		return tr.N("time.years_ago", int(diff.Hours()/24/365))
 	}
-	return n
 }
 
 // truncate truncates string to maxLen characters
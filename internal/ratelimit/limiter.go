@@ -6,41 +6,109 @@ import (
 	"time"
 
 	"github.com/rs/zerolog"
+	"github.com/telegram-llm-bot/internal/i18n"
+	"github.com/telegram-llm-bot/internal/metrics"
 	"github.com/telegram-llm-bot/internal/models"
+	"github.com/telegram-llm-bot/internal/settings"
 	"github.com/telegram-llm-bot/internal/storage"
 )
 
 // Limiter manages rate limits for users
+// Global limits from configuration apply to the default tier; other quota tiers
+// are stored in the database and resolved per user and chat.
+// Optionally, all users of a chat share a daily pool of Pro/Flash requests.
+// Personal limits reset at midnight in the global timezone, chat pools at midnight in the chat's one
 type Limiter struct {
 	storage         *storage.Client
-	timezone        *time.Location
+	settings        *settings.Service
 	proDailyLimit   int
 	flashDailyLimit int
+	imageDailyLimit int
+	tokenBudget     int
+	chatProPool     int
+	chatFlashPool   int
+	maxUserShare    int
 	logger          zerolog.Logger
 }
 
 // NewLimiter creates a new rate limiter
-func NewLimiter(storage *storage.Client, timezone string, proLimit, flashLimit int, logger zerolog.Logger) (*Limiter, error) {
-	loc, err := time.LoadLocation(timezone)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load timezone %s: %w", timezone, err)
-	}
-
+func NewLimiter(storage *storage.Client, settings *settings.Service, cfg *models.BotConfig, logger zerolog.Logger) *Limiter {
 	return &Limiter{
 		storage:         storage,
-		timezone:        loc,
-		proDailyLimit:   proLimit,
-		flashDailyLimit: flashLimit,
+		settings:        settings,
+		proDailyLimit:   cfg.ProDailyLimit,
+		flashDailyLimit: cfg.FlashDailyLimit,
+		imageDailyLimit: cfg.ImageGenerationDailyLimitPerUser,
+		tokenBudget:     cfg.TokenDailyBudgetPerUser,
+		chatProPool:     cfg.ChatProDailyPool,
+		chatFlashPool:   cfg.ChatFlashDailyPool,
+		maxUserShare:    cfg.ChatPoolMaxUserShare,
 		logger:          logger.With().Str("component", "ratelimit").Logger(),
-	}, nil
+	}
+}
+
+// GetEffectiveLimits resolves the quota tier of a user in a chat into concrete daily limits
+// If tiers cannot be loaded, the global limits from configuration are used
+func (l *Limiter) GetEffectiveLimits(ctx context.Context, userID, chatID int64) *models.EffectiveLimits {
+	limits := &models.EffectiveLimits{
+		Tier:             models.TierDefault,
+		Source:           models.QuotaSourceDefault,
+		ProDailyLimit:    l.proDailyLimit,
+		FlashDailyLimit:  l.flashDailyLimit,
+		ImageDailyLimit:  l.imageDailyLimit,
+		TokenDailyBudget: l.tokenBudget,
+	}
+
+	tier, source, err := l.storage.GetEffectiveQuotaTier(ctx, userID, chatID)
+	if err != nil {
+		l.logger.Warn().
+			Err(err).
+			Int64("user_id", userID).
+			Int64("chat_id", chatID).
+			Msg("Failed to resolve quota tier, using default limits")
+		return limits
+	}
+
+	limits.Tier = tier.Name
+	limits.Source = source
+	if tier.ProDailyLimit != nil {
+		limits.ProDailyLimit = *tier.ProDailyLimit
+	}
+	if tier.FlashDailyLimit != nil {
+		limits.FlashDailyLimit = *tier.FlashDailyLimit
+	}
+	if tier.ImageDailyLimit != nil {
+		limits.ImageDailyLimit = *tier.ImageDailyLimit
+	}
+	if tier.DailyTokenBudget != nil {
+		limits.TokenDailyBudget = *tier.DailyTokenBudget
+	}
+
+	return limits
 }
 
 // CheckLimit checks if user can make a request and determines which model to use
-func (l *Limiter) CheckLimit(ctx context.Context, userID int64) (*models.RateLimitResult, error) {
-	// Get current date in Moscow timezone
-	now := time.Now().In(l.timezone)
+// Rejection messages are in the language of tr
+func (l *Limiter) CheckLimit(ctx context.Context, userID, chatID int64, tr *i18n.Localizer) (*models.RateLimitResult, error) {
+	// Get current date in the timezone of limit days
+	now := time.Now().In(l.settings.LimitLocation())
 	dateStr := now.Format("2006-01-02")
 
+	effective := l.GetEffectiveLimits(ctx, userID, chatID)
+	if effective.IsBlocked() {
+		l.logger.Info().
+			Int64("user_id", userID).
+			Int64("chat_id", chatID).
+			Str("tier", effective.Tier).
+			Msg("Request rejected by quota tier")
+		metrics.RateLimitRejections.WithLabelValues("tier").Inc()
+		return &models.RateLimitResult{
+			Allowed: false,
+			Tier:    effective.Tier,
+			Message: tr.T("limit.blocked"),
+		}, nil
+	}
+
 	// Get user's daily limits
 	limits, err := l.storage.GetDailyLimit(ctx, userID, dateStr)
 	if err != nil {
@@ -52,39 +120,97 @@ func (l *Limiter) CheckLimit(ctx context.Context, userID int64) (*models.RateLim
 		return nil, fmt.Errorf("failed to check rate limit: %w", err)
 	}
 
-	proRemaining := l.proDailyLimit - limits.ProRequestsCount
-	flashRemaining := l.flashDailyLimit - limits.FlashRequestsCount
+	proRemaining := effective.ProDailyLimit - limits.ProRequestsCount
+	flashRemaining := effective.FlashDailyLimit - limits.FlashRequestsCount
 
 	l.logger.Debug().
 		Int64("user_id", userID).
+		Str("tier", effective.Tier).
 		Int("pro_used", limits.ProRequestsCount).
 		Int("pro_remaining", proRemaining).
 		Int("flash_used", limits.FlashRequestsCount).
 		Int("flash_remaining", flashRemaining).
+		Int("tokens_used", limits.TokensUsed).
+		Int("token_budget", effective.TokenDailyBudget).
 		Msg("Checking rate limit")
 
+	// Check daily token budget before request counts
+	if effective.TokenDailyBudget > 0 && limits.TokensUsed >= effective.TokenDailyBudget {
+		metrics.RateLimitRejections.WithLabelValues("tokens").Inc()
+		hoursUntilReset := l.hoursUntilMidnight(now)
+		return &models.RateLimitResult{
+			Allowed: false,
+			Tier:    effective.Tier,
+			Message: tr.T("limit.tokens",
+				limits.TokensUsed, effective.TokenDailyBudget,
+				hoursUntilReset,
+			),
+		}, nil
+	}
+
 	// Check if user has exceeded both limits
 	if proRemaining <= 0 && flashRemaining <= 0 {
+		metrics.RateLimitRejections.WithLabelValues("daily").Inc()
 		hoursUntilReset := l.hoursUntilMidnight(now)
 		return &models.RateLimitResult{
 			Allowed:        false,
 			ModelToUse:     "",
 			ProRemaining:   0,
 			FlashRemaining: 0,
-			Message: fmt.Sprintf(
-				"🚫 Вы исчерпали дневной лимит запросов.\n\n"+
-					"Лимиты сбросятся через %d ч.\n"+
-					"Pro: %d/%d\nFlash: %d/%d",
+			Tier:           effective.Tier,
+			Message: tr.T("limit.daily",
 				hoursUntilReset,
-				limits.ProRequestsCount, l.proDailyLimit,
-				limits.FlashRequestsCount, l.flashDailyLimit,
+				limits.ProRequestsCount, effective.ProDailyLimit,
+				limits.FlashRequestsCount, effective.FlashDailyLimit,
 			),
 		}, nil
 	}
 
+	// Check chat-wide pool and fairness share
+	proAllowed := proRemaining > 0
+	flashAllowed := flashRemaining > 0
+	if l.chatPoolEnabled() {
+		pool, err := l.GetChatPoolStats(ctx, chatID)
+		if err != nil {
+			// Don't block users if the pool cannot be loaded
+			l.logger.Warn().
+				Err(err).
+				Int64("chat_id", chatID).
+				Msg("Failed to check chat pool, skipping")
+		} else {
+			var proReason, flashReason string
+			if proAllowed {
+				proAllowed, proReason = checkPool(pool, userID, models.ModelPro, tr)
+			}
+			if flashAllowed {
+				flashAllowed, flashReason = checkPool(pool, userID, models.ModelFlash, tr)
+			}
+
+			if !proAllowed && !flashAllowed {
+				reason := flashReason
+				if reason == "" {
+					reason = proReason
+				}
+
+				l.logger.Info().
+					Int64("user_id", userID).
+					Int64("chat_id", chatID).
+					Str("reason", reason).
+					Msg("Request rejected by chat pool")
+				metrics.RateLimitRejections.WithLabelValues("chat_pool").Inc()
+
+				return &models.RateLimitResult{
+					Allowed: false,
+					Tier:    effective.Tier,
+					Message: tr.T("limit.pool", reason, pool.ResetsInHours),
+				}, nil
+			}
+		}
+	}
+
 	// Determine which model to use
 	var modelToUse models.ModelType
-	if proRemaining > 0 {
+	if proAllowed {
 		modelToUse = models.ModelPro
 	} else {
 		modelToUse = models.ModelFlash
@@ -95,14 +221,16 @@ func (l *Limiter) CheckLimit(ctx context.Context, userID int64) (*models.RateLim
 		ModelToUse:     modelToUse,
 		ProRemaining:   proRemaining,
 		FlashRemaining: flashRemaining,
+		Tier:           effective.Tier,
 		Message:        "",
 	}, nil
 }
 
-// IncrementUsage increments the usage count for a user
-func (l *Limiter) IncrementUsage(ctx context.Context, userID int64, modelType models.ModelType) error {
-	// Get current date in Moscow timezone
-	now := time.Now().In(l.timezone)
+// IncrementUsage increments the usage count for a user, counts the request against the chat pool
+// and adds consumed tokens to the daily budget
+func (l *Limiter) IncrementUsage(ctx context.Context, userID, chatID int64, modelType models.ModelType, tokens int) error {
+	// Get current date in the timezone of limit days
+	now := time.Now().In(l.settings.LimitLocation())
 	dateStr := now.Format("2006-01-02")
 
 	err := l.storage.IncrementLimit(ctx, userID, dateStr, modelType)
@@ -115,21 +243,42 @@ func (l *Limiter) IncrementUsage(ctx context.Context, userID int64, modelType mo
 		return fmt.Errorf("failed to increment usage: %w", err)
 	}
 
+	// The chat pool day follows the chat's timezone
+	// A failed pool update must not skip token accounting, so it is only logged
+	if l.chatPoolEnabled() {
+		chatDate := time.Now().In(l.settings.ChatLocation(ctx, chatID)).Format("2006-01-02")
+		if err := l.storage.IncrementChatUsage(ctx, chatID, userID, chatDate, modelType); err != nil {
+			l.logger.Error().
+				Err(err).
+				Int64("chat_id", chatID).
+				Int64("user_id", userID).
+				Str("model", string(modelType)).
+				Msg("Failed to increment chat usage")
+		}
+	}
+
+	if err := l.storage.AddDailyTokens(ctx, userID, dateStr, tokens); err != nil {
+		return fmt.Errorf("failed to add daily tokens: %w", err)
+	}
+
 	l.logger.Debug().
 		Int64("user_id", userID).
 		Str("model", string(modelType)).
 		Str("date", dateStr).
+		Int("tokens", tokens).
 		Msg("Usage incremented")
 
 	return nil
 }
 
-// GetUserStats returns statistics for a user
-func (l *Limiter) GetUserStats(ctx context.Context, userID int64, username, firstName string) (*models.UserStats, error) {
-	// Get current date in Moscow timezone
-	now := time.Now().In(l.timezone)
+// GetUserStats returns statistics for a user with limits effective in the given chat
+func (l *Limiter) GetUserStats(ctx context.Context, userID, chatID int64, username, firstName string) (*models.UserStats, error) {
+	// Get current date in the timezone of limit days
+	now := time.Now().In(l.settings.LimitLocation())
 	dateStr := now.Format("2006-01-02")
 
+	effective := l.GetEffectiveLimits(ctx, userID, chatID)
+
 	// Get daily limits
 	limits, err := l.storage.GetDailyLimit(ctx, userID, dateStr)
 	if err != nil {
@@ -157,18 +306,76 @@ func (l *Limiter) GetUserStats(ctx context.Context, userID int64, username, firs
 		Username:           username,
 		FirstName:          firstName,
 		ProRequestsUsed:    limits.ProRequestsCount,
-		ProRequestsLimit:   l.proDailyLimit,
+		ProRequestsLimit:   effective.ProDailyLimit,
 		FlashRequestsUsed:  limits.FlashRequestsCount,
-		FlashRequestsLimit: l.flashDailyLimit,
+		FlashRequestsLimit: effective.FlashDailyLimit,
+		TokensUsed:         limits.TokensUsed,
+		TokenBudget:        effective.TokenDailyBudget,
 		TotalRequests:      totalRequests,
 		ResetsInHours:      hoursUntilReset,
+		Tier:               effective.Tier,
 	}, nil
 }
 
-// hoursUntilMidnight calculates hours until midnight in the timezone
+// GetChatPoolStats returns today's chat-wide pool usage with consumers sorted by activity
+func (l *Limiter) GetChatPoolStats(ctx context.Context, chatID int64) (*models.ChatPoolStats, error) {
+	now := time.Now().In(l.settings.ChatLocation(ctx, chatID))
+	dateStr := now.Format("2006-01-02")
+
+	entries, err := l.storage.GetChatUsage(ctx, chatID, dateStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat pool stats: %w", err)
+	}
+
+	stats := &models.ChatPoolStats{
+		ChatID:        chatID,
+		ProPool:       l.chatProPool,
+		FlashPool:     l.chatFlashPool,
+		MaxUserShare:  l.maxUserShare,
+		Consumers:     entries,
+		ResetsInHours: l.hoursUntilMidnight(now),
+	}
+	for _, entry := range entries {
+		stats.ProUsed += entry.ProCount
+		stats.FlashUsed += entry.FlashCount
+	}
+
+	return stats, nil
+}
+
+// chatPoolEnabled reports whether any chat-wide pool is configured
+func (l *Limiter) chatPoolEnabled() bool {
+	return l.chatProPool > 0 || l.chatFlashPool > 0
+}
+
+// checkPool checks whether a user may spend one more request of the model from the chat pool
+// Returns a user-facing reason when the request is not allowed
+func checkPool(pool *models.ChatPoolStats, userID int64, model models.ModelType, tr *i18n.Localizer) (bool, string) {
+	label, size, used := "Flash", pool.FlashPool, pool.FlashUsed
+	userPro, userFlash := pool.UserUsage(userID)
+	userUsed := userFlash
+	if model == models.ModelPro {
+		label, size, used = "Pro", pool.ProPool, pool.ProUsed
+		userUsed = userPro
+	}
+
+	if size <= 0 {
+		return true, ""
+	}
+	if used >= size {
+		return false, tr.T("limit.pool_exhausted", label, used, size)
+	}
+	if userCap := pool.UserCap(size); userUsed >= userCap {
+		return false, tr.T("limit.pool_share", label, userUsed, userCap, pool.MaxUserShare)
+	}
+
+	return true, ""
+}
+
+// hoursUntilMidnight calculates hours until midnight in the timezone of now
 func (l *Limiter) hoursUntilMidnight(now time.Time) int {
 	// Get midnight of next day
-	midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, l.timezone)
+	midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
 	duration := midnight.Sub(now)
 	hours := int(duration.Hours())
 
@@ -0,0 +1,135 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+const (
+	// deliveryRetryWindow is how old undelivered summaries may be to be sent again.
+	// Older ones are stale, the next daily summary replaces them
+	deliveryRetryWindow = 24 * time.Hour
+
+	// deliveryRetryDelay keeps the retry job away from summaries still being sent by the daily job
+	deliveryRetryDelay = 5 * time.Minute
+)
+
+// deliverSummary sends a stored daily summary to its chat and records the outcome
+// replacedMessageID is the message of a regenerated summary to unpin, 0 to unpin the previous day's summary
+func (s *Scheduler) deliverSummary(ctx context.Context, summary *models.DailySummary, replacedMessageID int64) error {
+	if s.deliver == nil {
+		return nil
+	}
+
+	threadID, pin := s.settings.SummaryDelivery(ctx, summary.ChatID)
+	delivery := &models.SummaryDelivery{
+		ChatID:   summary.ChatID,
+		ThreadID: threadID,
+		Text:     summary.SummaryText,
+		Pin:      pin,
+	}
+
+	if pin {
+		delivery.UnpinMessageID = replacedMessageID
+		if delivery.UnpinMessageID == 0 {
+			previous, err := s.storage.GetLastSentSummary(ctx, summary.ChatID, summary.Date)
+			if err != nil {
+				s.logger.Warn().
+					Err(err).
+					Int64("chat_id", summary.ChatID).
+					Msg("Failed to get previous summary, it stays pinned")
+			} else if previous != nil {
+				delivery.UnpinMessageID = previous.SentMessageID
+			}
+		}
+	}
+
+	summary.DeliveryAttempts++
+	messageID, sendErr := s.deliver(ctx, delivery)
+	if sendErr != nil {
+		summary.DeliveryStatus = models.DeliveryStatusFailed
+		summary.DeliveryError = sendErr.Error()
+	} else {
+		now := time.Now()
+		summary.DeliveryStatus = models.DeliveryStatusSent
+		summary.DeliveryError = ""
+		summary.SentMessageID = messageID
+		summary.DeliveredAt = &now
+	}
+
+	// The summary is already sent, a lost status only risks a duplicate on retry
+	if err := s.storage.UpdateSummaryDelivery(ctx, summary); err != nil {
+		s.logger.Warn().
+			Err(err).
+			Int64("chat_id", summary.ChatID).
+			Str("date", summary.Date).
+			Msg("Failed to record summary delivery")
+	}
+
+	if sendErr != nil {
+		return fmt.Errorf("failed to send summary: %w", sendErr)
+	}
+	return nil
+}
+
+// runDeliveryRetry sends again daily summaries whose delivery failed
+func (s *Scheduler) runDeliveryRetry(ctx context.Context) {
+	startTime := time.Now()
+	err := s.retryDeliveries(ctx)
+	s.observeJob(jobDelivery, startTime, err)
+
+	if err != nil {
+		s.logger.Error().
+			Err(err).
+			Msg("Summary delivery retry failed")
+	}
+}
+
+// retryDeliveries delivers recent undelivered summaries that have attempts left
+func (s *Scheduler) retryDeliveries(ctx context.Context) error {
+	now := time.Now()
+	summaries, err := s.storage.GetUndeliveredSummaries(ctx,
+		now.Add(-deliveryRetryWindow), now.Add(-deliveryRetryDelay), s.config.SummaryDeliveryMaxAttempts)
+	if err != nil {
+		return fmt.Errorf("failed to get undelivered summaries: %w", err)
+	}
+
+	if len(summaries) == 0 {
+		return nil
+	}
+
+	s.logger.Info().
+		Int("count", len(summaries)).
+		Msg("Retrying delivery of summaries")
+
+	var errs []error
+	for i := range summaries {
+		summary := &summaries[i]
+		if !s.config.IsAllowedChat(summary.ChatID) {
+			continue
+		}
+
+		if err := s.deliverSummary(ctx, summary, 0); err != nil {
+			s.logger.Warn().
+				Err(err).
+				Int64("chat_id", summary.ChatID).
+				Str("date", summary.Date).
+				Int("attempts", summary.DeliveryAttempts).
+				Msg("Summary delivery retry failed")
+			errs = append(errs, err)
+			continue
+		}
+
+		s.logger.Info().
+			Int64("chat_id", summary.ChatID).
+			Str("date", summary.Date).
+			Int("attempts", summary.DeliveryAttempts).
+			Msg("Summary delivered on retry")
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,302 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/telegram-llm-bot/internal/charts"
+	"github.com/telegram-llm-bot/internal/i18n"
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+// maxDigestTopChatters limits the most active participants listed in a digest
+const maxDigestTopChatters = 3
+
+// maxDigestNewUsers limits the new participants listed in a digest
+const maxDigestNewUsers = 10
+
+// GenerateDigest generates the digest of the last completed week or month for a chat
+// (used for manual /summary week|month command). An existing digest is regenerated
+func (s *Scheduler) GenerateDigest(ctx context.Context, chatID int64, period string) error {
+	s.logger.Info().
+		Int64("chat_id", chatID).
+		Str("period", period).
+		Msg("Manual digest generation requested")
+
+	return s.processDigest(ctx, chatID, period, true)
+}
+
+// runChatDigest generates and sends the digest of the last completed period for a chat
+func (s *Scheduler) runChatDigest(ctx context.Context, chatID int64, period string) {
+	job := jobWeeklyDigest
+	if period == models.DigestPeriodMonth {
+		job = jobMonthlyDigest
+	}
+
+	startTime := time.Now()
+	err := s.processDigest(ctx, chatID, period, false)
+	s.observeJob(job, startTime, err)
+
+	if err != nil {
+		s.logger.Error().
+			Err(err).
+			Int64("chat_id", chatID).
+			Str("period", period).
+			Msg("Failed to process chat digest")
+	}
+}
+
+// processDigest builds, saves and sends a digest
+// It is built from stored daily summaries and sampled messages of the period,
+// compared with the previous digest of the same period for trends
+func (s *Scheduler) processDigest(ctx context.Context, chatID int64, period string, force bool) error {
+	loc := s.settings.ChatLocation(ctx, chatID)
+	start, end := digestRange(period, time.Now().In(loc))
+	startDate := start.Format("2006-01-02")
+	endDate := end.AddDate(0, 0, -1).Format("2006-01-02")
+
+	logger := s.logger.With().
+		Int64("chat_id", chatID).
+		Str("period", period).
+		Str("start", startDate).
+		Str("end", endDate).
+		Bool("force", force).
+		Logger()
+
+	logger.Info().Msg("Processing digest")
+
+	if !force {
+		existing, err := s.storage.GetDigest(ctx, chatID, period, startDate)
+		if err != nil {
+			return fmt.Errorf("failed to check if digest exists: %w", err)
+		}
+		if existing != nil {
+			logger.Info().Msg("Digest already exists for this period, skipping")
+			return nil
+		}
+	}
+
+	activity, err := s.storage.GetDailyActivity(ctx, chatID, startDate, endDate, loc)
+	if err != nil {
+		return fmt.Errorf("failed to get daily activity: %w", err)
+	}
+
+	messageCount := 0
+	for _, day := range activity {
+		messageCount += int(day.Messages)
+	}
+	if messageCount == 0 {
+		logger.Info().Msg("No messages for this period, skipping digest")
+		return nil
+	}
+
+	dailySummaries, err := s.storage.GetDailySummaries(ctx, chatID, startDate, endDate)
+	if err != nil {
+		return fmt.Errorf("failed to get daily summaries: %w", err)
+	}
+
+	var sampled []models.ChatMessage
+	if s.config.DigestSampleSize > 0 {
+		sampled, err = s.storage.SampleMessages(ctx, chatID, start, end, s.config.DigestSampleSize)
+		if err != nil {
+			return fmt.Errorf("failed to sample messages: %w", err)
+		}
+	}
+
+	participants, err := s.storage.GetParticipants(ctx, chatID, start, end)
+	if err != nil {
+		return fmt.Errorf("failed to get participants: %w", err)
+	}
+
+	// Trends are relative to the previous digest, if it was generated
+	prevStart, _ := digestRange(period, start.AddDate(0, 0, -1))
+	previous, err := s.storage.GetDigest(ctx, chatID, period, prevStart.Format("2006-01-02"))
+	if err != nil {
+		logger.Warn().Err(err).Msg("Failed to get previous digest, skipping trends")
+		previous = nil
+	}
+
+	input := &models.DigestInput{
+		ChatID:         chatID,
+		Period:         period,
+		Start:          startDate,
+		End:            endDate,
+		DailySummaries: dailySummaries,
+		Messages:       sampled,
+	}
+	if previous != nil {
+		input.PreviousTopics = previous.Topics
+	}
+
+	tr := i18n.New(s.settings.ChatLocale(ctx, chatID))
+	result, err := s.generator.GenerateDigest(ctx, input, loc, tr)
+	if err != nil {
+		return fmt.Errorf("failed to generate digest: %w", err)
+	}
+
+	newUsers := newParticipants(participants, start)
+	digest := &models.ChatDigest{
+		ChatID:       chatID,
+		Period:       period,
+		PeriodStart:  startDate,
+		PeriodEnd:    endDate,
+		Topics:       topicLines(result.Topics),
+		MessageCount: messageCount,
+		ActiveUsers:  len(participants),
+		NewUsers:     len(newUsers),
+	}
+	digest.SummaryText = s.formatDigestMessage(digest, start, result, participants, newUsers, previous, tr)
+
+	if err := s.storage.SaveDigest(ctx, digest); err != nil {
+		return fmt.Errorf("failed to save digest: %w", err)
+	}
+
+	if s.deliver != nil {
+		threadID, _ := s.settings.SummaryDelivery(ctx, chatID)
+		delivery := &models.SummaryDelivery{ChatID: chatID, ThreadID: threadID, Text: digest.SummaryText}
+		if _, err := s.deliver(ctx, delivery); err != nil {
+			return fmt.Errorf("failed to send digest: %w", err)
+		}
+	}
+
+	if s.chartCallback != nil && s.config.ChartsEnabled {
+		s.chartCallback(chatID, tr.T("digest.chart_title"), activityBars(period, activity, tr))
+	}
+
+	logger.Info().
+		Int("topic_count", len(result.Topics)).
+		Int("message_count", messageCount).
+		Int("active_users", len(participants)).
+		Int("new_users", len(newUsers)).
+		Msg("Digest completed successfully")
+
+	return nil
+}
+
+// digestRange returns the last completed period before now: the previous calendar week
+// (Monday to Sunday) or month. The end is exclusive
+func digestRange(period string, now time.Time) (time.Time, time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	if period == models.DigestPeriodMonth {
+		end := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		return end.AddDate(0, -1, 0), end
+	}
+
+	// Weeks start on Monday
+	daysSinceMonday := (int(today.Weekday()) + 6) % 7
+	end := today.AddDate(0, 0, -daysSinceMonday)
+	return end.AddDate(0, 0, -7), end
+}
+
+// newParticipants returns participants who wrote in the chat for the first time in the period
+// Nobody is new if all participants are, which means there is no earlier history to compare with
+func newParticipants(participants []models.Participant, start time.Time) []models.Participant {
+	var newUsers []models.Participant
+	for _, participant := range participants {
+		if !participant.FirstSeenAt.Before(start) {
+			newUsers = append(newUsers, participant)
+		}
+	}
+
+	if len(newUsers) == len(participants) {
+		return nil
+	}
+	return newUsers
+}
+
+// activityBars converts daily activity into chart bars
+func activityBars(period string, activity []models.DailyActivity, tr *i18n.Localizer) []charts.Bar {
+	bars := make([]charts.Bar, 0, len(activity))
+	for _, day := range activity {
+		label := day.Date
+		if t, err := time.Parse("2006-01-02", day.Date); err == nil {
+			label = t.Format("02.01")
+			if period == models.DigestPeriodWeek {
+				label = tr.T(fmt.Sprintf("date.weekday_short.%d", t.Weekday())) + " " + label
+			}
+		}
+		bars = append(bars, charts.Bar{Label: label, Value: float64(day.Messages)})
+	}
+	return bars
+}
+
+// topicLines converts topics into the lines stored with the digest
+func topicLines(topics []models.SummaryTopic) []string {
+	lines := make([]string, len(topics))
+	for i, topic := range topics {
+		lines[i] = topic.String()
+	}
+	return lines
+}
+
+// formatDigestMessage formats the digest into a Telegram message
+func (s *Scheduler) formatDigestMessage(
+	digest *models.ChatDigest,
+	start time.Time,
+	result *models.DigestResult,
+	participants []models.Participant,
+	newUsers []models.Participant,
+	previous *models.ChatDigest,
+	tr *i18n.Localizer,
+) string {
+	var sb strings.Builder
+
+	if digest.Period == models.DigestPeriodMonth {
+		sb.WriteString(tr.T("digest.title_month", tr.T(fmt.Sprintf("date.month_standalone.%d", start.Month())), start.Year()))
+	} else {
+		end := start.AddDate(0, 0, 6)
+		sb.WriteString(tr.T("digest.title_week", start.Format("02.01"), end.Format("02.01.2006")))
+	}
+
+	if len(result.Topics) > 0 {
+		sb.WriteString(tr.T("digest.topics"))
+		sb.WriteString(formatTopics(digest.ChatID, result.Topics))
+	} else {
+		sb.WriteString(tr.T("report.no_discussions"))
+	}
+
+	if len(result.Rising) > 0 {
+		sb.WriteString(tr.T("digest.rising"))
+		for _, topic := range result.Rising {
+			sb.WriteString(formatTopicLine(topic) + "\n")
+		}
+	}
+	if len(result.Fading) > 0 {
+		sb.WriteString(tr.T("digest.fading"))
+		for _, topic := range result.Fading {
+			sb.WriteString(formatTopicLine(topic) + "\n")
+		}
+	}
+
+	sb.WriteString(tr.T("report.activity",
+		tr.N("unit.messages", digest.MessageCount), tr.N("unit.participants_from", digest.ActiveUsers)))
+	if previous != nil && previous.MessageCount > 0 {
+		change := (digest.MessageCount - previous.MessageCount) * 100 / previous.MessageCount
+		sb.WriteString(tr.T("digest.change", change))
+	}
+	sb.WriteString("\n")
+
+	if len(participants) > 0 {
+		top := make([]string, 0, maxDigestTopChatters)
+		for _, participant := range participants[:min(len(participants), maxDigestTopChatters)] {
+			top = append(top, fmt.Sprintf("%s (%d)", escapeMarkdownV1(participant.DisplayName()), participant.Count))
+		}
+		sb.WriteString(tr.T("report.top", strings.Join(top, ", ")))
+	}
+
+	if len(newUsers) > 0 {
+		names := make([]string, 0, maxDigestNewUsers)
+		for _, participant := range newUsers[:min(len(newUsers), maxDigestNewUsers)] {
+			names = append(names, escapeMarkdownV1(participant.DisplayName()))
+		}
+		if len(newUsers) > maxDigestNewUsers {
+			names = append(names, tr.T("digest.and_more", len(newUsers)-maxDigestNewUsers))
+		}
+		sb.WriteString(tr.T("digest.new_users", strings.Join(names, ", ")))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,104 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/telegram-llm-bot/internal/i18n"
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+// maxRangeTopChatters is the number of most active participants shown in range summaries
+const maxRangeTopChatters = 3
+
+// SummarizeRange generates an on-demand summary of chat messages written in [since, until)
+// The summary is neither stored nor sent; the title heads the returned message, which is in the locale
+// of the requesting user. Returns an empty text if there are no messages in the range
+func (s *Scheduler) SummarizeRange(ctx context.Context, chatID int64, since, until time.Time, title, locale string) (string, error) {
+	loc := s.settings.ChatLocation(ctx, chatID)
+	tr := i18n.New(locale)
+
+	logger := s.logger.With().
+		Int64("chat_id", chatID).
+		Time("since", since).
+		Time("until", until).
+		Logger()
+
+	messages, err := s.storage.GetMessagesInRange(ctx, chatID, since, until)
+	if err != nil {
+		return "", fmt.Errorf("failed to get messages: %w", err)
+	}
+
+	if len(messages) == 0 {
+		logger.Info().Msg("No messages in range, skipping summary")
+		return "", nil
+	}
+
+	label := tr.T("prompt.range_label",
+		since.In(loc).Format("02.01.2006 15:04"), until.In(loc).Format("02.01.2006 15:04"))
+	result, err := s.generator.GenerateSummary(ctx, messages, label, loc, tr)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate summary: %w", err)
+	}
+
+	logger.Info().
+		Int("topic_count", len(result.Topics)).
+		Int("message_count", len(messages)).
+		Msg("Range summary completed successfully")
+
+	return formatRangeMessage(chatID, title, messages, result.Topics, tr), nil
+}
+
+// formatRangeMessage formats an on-demand summary into a Telegram message
+func formatRangeMessage(chatID int64, title string, messages []models.ChatMessage, topics []models.SummaryTopic, tr *i18n.Localizer) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("📊 *%s*\n\n", escapeMarkdownV1(title)))
+
+	if len(topics) > 0 {
+		sb.WriteString(formatTopics(chatID, topics))
+	} else {
+		sb.WriteString(tr.T("report.no_discussions"))
+	}
+
+	participants := rangeParticipants(messages)
+	sb.WriteString(tr.T("report.activity",
+		tr.N("unit.messages", len(messages)), tr.N("unit.participants_from", len(participants))))
+	sb.WriteString("\n")
+
+	top := make([]string, 0, maxRangeTopChatters)
+	for _, participant := range participants[:min(len(participants), maxRangeTopChatters)] {
+		top = append(top, fmt.Sprintf("%s (%d)", escapeMarkdownV1(participant.DisplayName()), participant.Count))
+	}
+	sb.WriteString(tr.T("report.top", strings.Join(top, ", ")))
+
+	return sb.String()
+}
+
+// rangeParticipants counts messages per participant, most active first
+func rangeParticipants(messages []models.ChatMessage) []models.UserActivity {
+	counts := make(map[int64]*models.UserActivity)
+	var order []int64
+	for _, msg := range messages {
+		activity, ok := counts[msg.UserID]
+		if !ok {
+			activity = &models.UserActivity{UserID: msg.UserID, Username: msg.Username, FirstName: msg.FirstName}
+			counts[msg.UserID] = activity
+			order = append(order, msg.UserID)
+		}
+		activity.Count++
+	}
+
+	participants := make([]models.UserActivity, 0, len(order))
+	for _, userID := range order {
+		participants = append(participants, *counts[userID])
+	}
+	sort.SliceStable(participants, func(i, j int) bool {
+		return participants[i].Count > participants[j].Count
+	})
+
+	return participants
+}
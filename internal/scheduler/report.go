@@ -0,0 +1,284 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/telegram-llm-bot/internal/i18n"
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+const (
+	// maxReportQuoteLength truncates quoted messages in the daily summary
+	maxReportQuoteLength = 300
+
+	// minRepliesForMostReplied is the number of replies a message needs to be featured
+	minRepliesForMostReplied = 2
+)
+
+// sparkLevels are the bars of the hourly activity histogram from lowest to highest
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// rankMedals mark the first places of the top chatters
+var rankMedals = []string{"🥇", "🥈", "🥉"}
+
+// dailyReport holds everything the daily summary message is rendered from
+type dailyReport struct {
+	chatID   int64
+	date     string
+	loc      *time.Location
+	messages []models.ChatMessage
+	counts   []models.UserMessageCount // Most active first
+	result   *models.SummaryResult
+	tr       *i18n.Localizer // Chat's language
+}
+
+// formatSummaryMessage formats the daily summary into a Telegram message with the chat's template
+// A template that fails to render falls back to the default one, so the summary is still delivered
+func (s *Scheduler) formatSummaryMessage(report *dailyReport, settings models.SummaryReport, text string) string {
+	data := s.summaryTemplateData(report, settings)
+
+	if text != "" {
+		tmpl, err := newSummaryTemplate().Parse(text)
+		if err == nil {
+			var summary string
+			if summary, err = executeSummaryTemplate(tmpl, data); err == nil {
+				return summary
+			}
+		}
+		s.logger.Warn().
+			Err(err).
+			Int64("chat_id", report.chatID).
+			Msg("Failed to render chat summary template, using the default one")
+	}
+
+	summary, err := executeSummaryTemplate(defaultSummaryTemplate, data)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("chat_id", report.chatID).Msg("Failed to render default summary template")
+		return data.Header
+	}
+	return summary
+}
+
+// summaryTemplateData renders the sections of the daily summary for templates
+// Sections disabled in the chat's report settings are skipped, custom titles replace default headings
+func (s *Scheduler) summaryTemplateData(report *dailyReport, settings models.SummaryReport) *summaryTemplateData {
+	date := formatReportDate(report.date, report.tr)
+	data := &summaryTemplateData{
+		Header:           report.tr.T("summary.title", date),
+		Date:             date,
+		ISODate:          report.date,
+		MessageCount:     len(report.messages),
+		ParticipantCount: len(report.counts),
+		Section:          make(map[string]*summaryTemplateSection),
+	}
+
+	for _, section := range models.SummarySections {
+		if !settings.Enabled(section) {
+			continue
+		}
+
+		var body string
+		switch section {
+		case models.SummarySectionTopics:
+			body = formatReportTopics(report)
+		case models.SummarySectionStats:
+			body = formatReportStats(report)
+		case models.SummarySectionActivity:
+			body = formatReportActivity(report)
+		case models.SummarySectionTop:
+			body = s.formatReportTop(report)
+		case models.SummarySectionQuote:
+			body = formatReportQuote(report)
+		case models.SummarySectionReplied:
+			body = formatReportMostReplied(report)
+		}
+		if body == "" {
+			continue
+		}
+
+		rendered := summaryTemplateSection{
+			Name:  section,
+			Title: settings.Title(section, report.tr.T("summary.section."+section)),
+			Body:  body,
+		}
+		data.Sections = append(data.Sections, rendered)
+		data.Section[section] = &rendered
+	}
+
+	return data
+}
+
+// formatReportDate formats a YYYY-MM-DD date as "20 ноября" in the chat's language
+func formatReportDate(date string, tr *i18n.Localizer) string {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+	return tr.DayMonth(t)
+}
+
+// formatReportTopics formats the topics section
+func formatReportTopics(report *dailyReport) string {
+	if len(report.result.Topics) == 0 {
+		return report.tr.T("summary.no_topics")
+	}
+	return "\n" + formatTopics(report.chatID, report.result.Topics)
+}
+
+// formatReportStats formats message and participant counts
+func formatReportStats(report *dailyReport) string {
+	messages := len(report.messages)
+	participants := len(report.counts)
+
+	replies := 0
+	for _, msg := range report.messages {
+		if msg.ReplyToMessageID != 0 {
+			replies++
+		}
+	}
+
+	tr := report.tr
+	return tr.T("summary.stats",
+		tr.N("unit.messages", messages), tr.N("unit.participants_from", participants), tr.N("unit.replies", replies))
+}
+
+// formatReportActivity formats the hourly activity histogram with the peak hour
+func formatReportActivity(report *dailyReport) string {
+	var hours [24]int
+	for _, msg := range report.messages {
+		hours[msg.CreatedAt.In(report.loc).Hour()]++
+	}
+
+	peak := 0
+	for hour, count := range hours {
+		if count > hours[peak] {
+			peak = hour
+		}
+	}
+	if hours[peak] == 0 {
+		return ""
+	}
+
+	// Empty hours stay at the lowest bar, active ones are scaled over the rest
+	bars := make([]rune, len(hours))
+	for hour, count := range hours {
+		level := (count*(len(sparkLevels)-1) + hours[peak] - 1) / hours[peak]
+		bars[hour] = sparkLevels[level]
+	}
+
+	return fmt.Sprintf("`%s`\n`0     6     12    18  23`\n", string(bars)) +
+		report.tr.T("summary.activity_peak", peak, (peak+1)%24, report.tr.N("unit.messages", hours[peak]))
+}
+
+// formatReportTop formats the most active participants
+func (s *Scheduler) formatReportTop(report *dailyReport) string {
+	if len(report.counts) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	for i, count := range report.counts[:min(len(report.counts), s.config.SummaryTopChatters)] {
+		rank := fmt.Sprintf("%d.", i+1)
+		if i < len(rankMedals) {
+			rank = rankMedals[i]
+		}
+		sb.WriteString(fmt.Sprintf("%s %s (%s)\n",
+			rank, escapeMarkdownV1(reportUserName(count.Username, count.FirstName, count.UserID)),
+			report.tr.N("unit.messages", count.MessageCount),
+		))
+	}
+	return sb.String()
+}
+
+// formatReportQuote formats the quote of the day chosen by the model
+func formatReportQuote(report *dailyReport) string {
+	quote := report.result.Quote
+	if quote == nil {
+		return ""
+	}
+
+	msg := findReportMessage(report.messages, quote.MessageID)
+	if msg == nil {
+		return ""
+	}
+
+	text := formatReportMessage(report.chatID, msg)
+	if quote.Comment != "" {
+		text += "💭 " + escapeMarkdownV1(quote.Comment) + "\n"
+	}
+	return text
+}
+
+// formatReportMostReplied formats the message of the day with the most replies
+func formatReportMostReplied(report *dailyReport) string {
+	replies := make(map[int64]int)
+	for _, msg := range report.messages {
+		if msg.ReplyToMessageID != 0 {
+			replies[msg.ReplyToMessageID]++
+		}
+	}
+
+	// Only messages of the day are featured, ties go to the earlier message
+	var top *models.ChatMessage
+	for i := range report.messages {
+		msg := &report.messages[i]
+		if replies[msg.MessageID] >= minRepliesForMostReplied &&
+			(top == nil || replies[msg.MessageID] > replies[top.MessageID]) {
+			top = msg
+		}
+	}
+	if top == nil {
+		return ""
+	}
+
+	count := replies[top.MessageID]
+	return formatReportMessage(report.chatID, top) +
+		"↩️ " + report.tr.N("unit.replies", count) + "\n"
+}
+
+// formatReportMessage formats a quoted message with its author and link
+func formatReportMessage(chatID int64, msg *models.ChatMessage) string {
+	text := msg.MessageText
+	if runes := []rune(text); len(runes) > maxReportQuoteLength {
+		text = string(runes[:maxReportQuoteLength]) + "…"
+	}
+
+	author := escapeMarkdownV1(reportUserName(msg.Username, msg.FirstName, msg.UserID))
+	if link := messageLink(chatID, msg.MessageID); link != "" {
+		author = fmt.Sprintf("[%s](%s)", author, link)
+	}
+
+	return fmt.Sprintf("«%s» — %s\n", escapeMarkdownV1(text), author)
+}
+
+// findReportMessage finds a message of the day by its Telegram ID
+func findReportMessage(messages []models.ChatMessage, messageID int64) *models.ChatMessage {
+	for i := range messages {
+		if messages[i].MessageID == messageID {
+			return &messages[i]
+		}
+	}
+	return nil
+}
+
+// reportUserName returns @username, the first name or a placeholder
+func reportUserName(username, firstName string, userID int64) string {
+	switch {
+	case username != "":
+		return "@" + username
+	case firstName != "":
+		return firstName
+	default:
+		return fmt.Sprintf("User%d", userID)
+	}
+}
+
+// sortUserMessageCounts orders message counts from the most active user
+func sortUserMessageCounts(counts []models.UserMessageCount) {
+	sort.SliceStable(counts, func(i, j int) bool {
+		return counts[i].MessageCount > counts[j].MessageCount
+	})
+}
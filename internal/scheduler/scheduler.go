@@ -4,26 +4,90 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/robfig/cron/v3"
 	"github.com/rs/zerolog"
+	"github.com/telegram-llm-bot/internal/charts"
+	"github.com/telegram-llm-bot/internal/health"
+	"github.com/telegram-llm-bot/internal/i18n"
+	"github.com/telegram-llm-bot/internal/metrics"
 	"github.com/telegram-llm-bot/internal/models"
+	"github.com/telegram-llm-bot/internal/settings"
 	"github.com/telegram-llm-bot/internal/storage"
 	"github.com/telegram-llm-bot/internal/summary"
 )
 
-// SummaryCallback is a function that sends the summary to a chat
-type SummaryCallback func(chatID int64, summaryText string) error
+// Job names used in metrics
+const (
+	jobRAGSync       = "rag_sync"
+	jobDailySummary  = "daily_summary"
+	jobWeeklyDigest  = "weekly_digest"
+	jobMonthlyDigest = "monthly_digest"
+	jobDelivery      = "summary_delivery"
+)
+
+// supergroupIDPrefix offsets supergroup chat IDs from the IDs used in message links
+const supergroupIDPrefix = -1000000000000
+
+// chatJobs are the jobs scheduled per chat
+var chatJobs = []string{models.ScheduleJobSummary, models.ScheduleJobWeekly, models.ScheduleJobMonthly}
+
+// DeliveryCallback is a function that posts a summary or digest to a chat and returns the sent message ID
+type DeliveryCallback func(ctx context.Context, delivery *models.SummaryDelivery) (int64, error)
+
+// ChartCallback is a function that sends a bar chart to a chat
+type ChartCallback func(chatID int64, title string, bars []charts.Bar)
+
+// chatJob identifies a per-chat cron entry
+type chatJob struct {
+	chatID int64
+	job    string
+}
 
 // Scheduler handles scheduled tasks like daily summaries and RAG sync
 type Scheduler struct {
-	storage         *storage.Client
-	generator       *summary.Generator
-	config          *models.BotConfig
-	summaryCallback SummaryCallback
-	syncJob         *SyncJob
-	logger          zerolog.Logger
-	timezone        *time.Location
+	storage       *storage.Client
+	generator     *summary.Generator
+	config        *models.BotConfig
+	deliver       DeliveryCallback
+	chartCallback ChartCallback
+	syncJob       *SyncJob
+	settings      *settings.Service
+	logger        zerolog.Logger
+	timezone      *time.Location // Global timezone, chats may override it
+	cron          *cron.Cron
+	runCtx        context.Context // Context of scheduled runs, set by Start
+	syncSchedule  cron.Schedule
+
+	// Cron entries of per-chat jobs
+	entriesMu   sync.Mutex
+	chatEntries map[chatJob]cron.EntryID
+
+	// Last run outcomes per job for health checks
+	runsMu   sync.Mutex
+	lastRuns map[string]*jobRun
+}
+
+// cronLogger adapts zerolog to the cron.Logger interface
+type cronLogger struct {
+	logger zerolog.Logger
+}
+
+func (l cronLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.logger.Debug().Fields(keysAndValues).Msg(msg)
+}
+
+func (l cronLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.logger.Error().Err(err).Fields(keysAndValues).Msg(msg)
+}
+
+// jobRun represents the last outcomes of a scheduled job
+type jobRun struct {
+	lastSuccess time.Time
+	lastFailure time.Time
+	lastError   string
 }
 
 // NewScheduler creates a new scheduler
@@ -31,120 +95,261 @@ func NewScheduler(
 	storage *storage.Client,
 	generator *summary.Generator,
 	config *models.BotConfig,
-	summaryCallback SummaryCallback,
+	deliver DeliveryCallback,
 	syncJob *SyncJob,
+	settings *settings.Service,
 	logger zerolog.Logger,
-) (*Scheduler, error) {
-	// Load timezone
-	loc, err := time.LoadLocation(config.Timezone)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load timezone %s: %w", config.Timezone, err)
-	}
+) *Scheduler {
+	loc := settings.DefaultLocation()
+	cronLog := cronLogger{logger: logger.With().Str("component", "cron").Logger()}
 
 	return &Scheduler{
-		storage:         storage,
-		generator:       generator,
-		config:          config,
-		summaryCallback: summaryCallback,
-		syncJob:         syncJob,
-		logger:          logger.With().Str("component", "scheduler").Logger(),
-		timezone:        loc,
-	}, nil
+		storage:   storage,
+		generator: generator,
+		config:    config,
+		deliver:   deliver,
+		syncJob:   syncJob,
+		settings:  settings,
+		logger:    logger.With().Str("component", "scheduler").Logger(),
+		timezone:  loc,
+		cron: cron.New(
+			cron.WithLocation(loc),
+			cron.WithChain(cron.Recover(cronLog), cron.SkipIfStillRunning(cronLog)),
+		),
+		chatEntries: make(map[chatJob]cron.EntryID),
+		lastRuns:    make(map[string]*jobRun),
+	}
+}
+
+// SetChartCallback sets the callback that sends activity charts of digests
+func (s *Scheduler) SetChartCallback(callback ChartCallback) {
+	s.chartCallback = callback
 }
 
-// Start starts the scheduler
+// Start schedules jobs and runs them until the context is cancelled
 func (s *Scheduler) Start(ctx context.Context) error {
 	s.logger.Info().Msg("Starting scheduler...")
+	s.runCtx = ctx
 
-	// Calculate time until next 7 AM for summaries
-	nextSummaryRun := s.calculateNextRun(7, 0)
-	s.logger.Info().
-		Time("next_summary_run", nextSummaryRun).
-		Dur("wait_duration", time.Until(nextSummaryRun)).
-		Msg("Scheduled next daily summary")
-
-	// Calculate time until next 3 AM for RAG sync
-	nextSyncRun := s.calculateNextRun(3, 0)
-	s.logger.Info().
-		Time("next_sync_run", nextSyncRun).
-		Dur("wait_duration", time.Until(nextSyncRun)).
-		Msg("Scheduled next RAG sync")
+	syncSchedule, err := cron.ParseStandard(s.config.SyncCronSchedule)
+	if err != nil {
+		return fmt.Errorf("invalid sync schedule %q: %w", s.config.SyncCronSchedule, err)
+	}
+	s.syncSchedule = syncSchedule
+	s.cron.Schedule(syncSchedule, cron.FuncJob(func() { s.runRAGSync(s.runCtx) }))
 
-	// Start goroutine for summary scheduling
-	go s.runSummaryScheduler(ctx, nextSummaryRun)
+	if _, err := s.cron.AddFunc(s.config.SummaryDeliveryRetrySchedule, func() { s.runDeliveryRetry(s.runCtx) }); err != nil {
+		return fmt.Errorf("invalid delivery retry schedule %q: %w", s.config.SummaryDeliveryRetrySchedule, err)
+	}
 
-	// Start goroutine for sync scheduling
-	go s.runSyncScheduler(ctx, nextSyncRun)
+	// Chats without a stored schedule still get the default one if loading fails
+	if err := s.ReloadSchedules(ctx); err != nil {
+		s.logger.Warn().Err(err).Msg("Failed to load chat schedules, using defaults")
+	}
 
-	s.logger.Info().Msg("Scheduler started and running")
+	s.cron.Start()
 
-	// Wait for context cancellation
+	s.logger.Info().
+		Str("sync_schedule", s.config.SyncCronSchedule).
+		Str("summary_schedule", s.config.SummaryCronSchedule).
+		Str("weekly_digest_schedule", s.config.DigestWeeklyCronSchedule).
+		Str("monthly_digest_schedule", s.config.DigestMonthlyCronSchedule).
+		Time("next_sync_run", s.syncSchedule.Next(time.Now().In(s.timezone))).
+		Msg("Scheduler started and running")
+
+	// Wait for context cancellation, then for running jobs
 	<-ctx.Done()
+	<-s.cron.Stop().Done()
 	s.logger.Info().Msg("Scheduler stopped")
 	return ctx.Err()
 }
 
-// runSummaryScheduler handles daily summary scheduling
-func (s *Scheduler) runSummaryScheduler(ctx context.Context, nextRun time.Time) {
-	// Initial wait until first run
-	select {
-	case <-ctx.Done():
-		return
-	case <-time.After(time.Until(nextRun)):
-		s.runDailySummaries(ctx)
-	}
+// ReloadSchedules (re)schedules summaries and digests of all allowed chats from stored per-chat schedules
+func (s *Scheduler) ReloadSchedules(ctx context.Context) error {
+	schedules, err := s.storage.GetChatSchedules(ctx)
 
-	// Create ticker for subsequent runs (every 24 hours)
-	ticker := time.NewTicker(24 * time.Hour)
-	defer ticker.Stop()
+	overrides := make(map[chatJob]*models.ChatSchedule, len(schedules))
+	for i := range schedules {
+		overrides[chatJob{chatID: schedules[i].ChatID, job: schedules[i].Job}] = &schedules[i]
+	}
 
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			s.runDailySummaries(ctx)
+	for _, chatID := range s.config.AllowedChatIDs {
+		loc := s.settings.ChatLocation(ctx, chatID)
+		for _, job := range chatJobs {
+			override := overrides[chatJob{chatID: chatID, job: job}]
+			if scheduleErr := s.scheduleChatJob(chatID, job, override, loc); scheduleErr != nil {
+				s.logger.Error().
+					Err(scheduleErr).
+					Int64("chat_id", chatID).
+					Str("job", job).
+					Msg("Failed to schedule chat job")
+			}
 		}
 	}
+
+	return err
 }
 
-// runSyncScheduler handles RAG sync scheduling
-func (s *Scheduler) runSyncScheduler(ctx context.Context, nextRun time.Time) {
-	// Initial wait until first run
-	select {
-	case <-ctx.Done():
-		return
-	case <-time.After(time.Until(nextRun)):
-		s.runRAGSync(ctx)
+// ReloadChat reschedules jobs of a chat after its schedule or timezone was changed
+func (s *Scheduler) ReloadChat(ctx context.Context, chatID int64) error {
+	schedules, err := s.storage.GetChatSchedules(ctx)
+	if err != nil {
+		return err
 	}
 
-	// Create ticker for subsequent runs (every 24 hours)
-	ticker := time.NewTicker(24 * time.Hour)
-	defer ticker.Stop()
+	loc := s.settings.ChatLocation(ctx, chatID)
+	for _, job := range chatJobs {
+		var override *models.ChatSchedule
+		for i := range schedules {
+			if schedules[i].ChatID == chatID && schedules[i].Job == job {
+				override = &schedules[i]
+			}
+		}
 
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			s.runRAGSync(ctx)
+		if err := s.scheduleChatJob(chatID, job, override, loc); err != nil {
+			return err
 		}
 	}
+
+	return nil
+}
+
+// ValidateSchedule checks a cron expression (5 fields or a descriptor like @daily)
+func (s *Scheduler) ValidateSchedule(expr string) error {
+	_, err := cron.ParseStandard(expr)
+	return err
 }
 
-// calculateNextRun calculates the next run time for a specific hour and minute
-func (s *Scheduler) calculateNextRun(hour, minute int) time.Time {
+// NextRun returns the next run of a job in a chat, false if the job is disabled
+func (s *Scheduler) NextRun(chatID int64, job string) (time.Time, bool) {
 	now := time.Now().In(s.timezone)
 
-	// Set to specified time today
-	next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, s.timezone)
+	switch job {
+	case models.ScheduleJobSync:
+		if s.syncSchedule == nil {
+			return time.Time{}, false
+		}
+		return s.syncSchedule.Next(now), true
+	case models.ScheduleJobSummary, models.ScheduleJobWeekly, models.ScheduleJobMonthly:
+		s.entriesMu.Lock()
+		defer s.entriesMu.Unlock()
+
+		id, ok := s.chatEntries[chatJob{chatID: chatID, job: job}]
+		if !ok {
+			return time.Time{}, false
+		}
+		return s.cron.Entry(id).Schedule.Next(now), true
+	}
+
+	return time.Time{}, false
+}
+
+// DefaultSchedule returns the global cron expression of a per-chat job
+func (s *Scheduler) DefaultSchedule(job string) string {
+	switch job {
+	case models.ScheduleJobWeekly:
+		return s.config.DigestWeeklyCronSchedule
+	case models.ScheduleJobMonthly:
+		return s.config.DigestMonthlyCronSchedule
+	default:
+		return s.config.SummaryCronSchedule
+	}
+}
+
+// scheduleChatJob replaces the cron entry of a per-chat job
+// The global schedule is used unless the chat overrides or disables it.
+// The schedule runs in the chat's timezone unless the expression sets CRON_TZ itself
+func (s *Scheduler) scheduleChatJob(chatID int64, job string, override *models.ChatSchedule, loc *time.Location) error {
+	s.entriesMu.Lock()
+	defer s.entriesMu.Unlock()
+
+	key := chatJob{chatID: chatID, job: job}
+	if id, ok := s.chatEntries[key]; ok {
+		s.cron.Remove(id)
+		delete(s.chatEntries, key)
+	}
+
+	expr := s.DefaultSchedule(job)
+	if override != nil {
+		if !override.Enabled {
+			s.logger.Info().Int64("chat_id", chatID).Str("job", job).Msg("Job disabled for chat")
+			return nil
+		}
+		if override.CronExpr != "" {
+			expr = override.CronExpr
+		}
+	}
+
+	if !strings.HasPrefix(expr, "CRON_TZ=") && !strings.HasPrefix(expr, "TZ=") {
+		expr = fmt.Sprintf("CRON_TZ=%s %s", loc.String(), expr)
+	}
 
-	// If it's already past the time today, schedule for tomorrow
-	if now.After(next) || now.Equal(next) {
-		next = next.AddDate(0, 0, 1)
+	schedule, err := cron.ParseStandard(expr)
+	if err != nil {
+		return fmt.Errorf("invalid %s schedule %q: %w", job, expr, err)
 	}
 
-	return next
+	s.chatEntries[key] = s.cron.Schedule(schedule, cron.FuncJob(func() {
+		switch job {
+		case models.ScheduleJobWeekly:
+			s.runChatDigest(s.runCtx, chatID, models.DigestPeriodWeek)
+		case models.ScheduleJobMonthly:
+			s.runChatDigest(s.runCtx, chatID, models.DigestPeriodMonth)
+		default:
+			s.runChatSummary(s.runCtx, chatID)
+		}
+	}))
+
+	s.logger.Info().
+		Int64("chat_id", chatID).
+		Str("job", job).
+		Str("schedule", expr).
+		Time("next_run", schedule.Next(time.Now().In(loc))).
+		Msg("Scheduled chat job")
+
+	return nil
+}
+
+// observeJob records the outcome and duration of a scheduled job run
+func (s *Scheduler) observeJob(job string, startTime time.Time, err error) {
+	metrics.SchedulerJobRuns.WithLabelValues(job, metrics.Status(err)).Inc()
+	metrics.SchedulerJobDuration.WithLabelValues(job).Observe(time.Since(startTime).Seconds())
+
+	s.runsMu.Lock()
+	defer s.runsMu.Unlock()
+
+	run, ok := s.lastRuns[job]
+	if !ok {
+		run = &jobRun{}
+		s.lastRuns[job] = run
+	}
+
+	if err != nil {
+		run.lastFailure = time.Now()
+		run.lastError = err.Error()
+	} else {
+		run.lastSuccess = time.Now()
+	}
+}
+
+// HealthCheck reports last successful runs of scheduled jobs
+// The scheduler is degraded if the latest run of any job failed
+func (s *Scheduler) HealthCheck(ctx context.Context) health.Result {
+	s.runsMu.Lock()
+	defer s.runsMu.Unlock()
+
+	result := health.Result{Status: health.StatusOK, Details: map[string]interface{}{}}
+	for job, run := range s.lastRuns {
+		if !run.lastSuccess.IsZero() {
+			result.Details[job+"_last_success_at"] = run.lastSuccess.UTC().Format(time.RFC3339)
+		}
+		if run.lastFailure.After(run.lastSuccess) {
+			result.Status = health.StatusDegraded
+			result.Details[job+"_last_error"] = run.lastError
+		}
+	}
+
+	return result
 }
 
 // runRAGSync executes RAG synchronization
@@ -156,7 +361,11 @@ func (s *Scheduler) runRAGSync(ctx context.Context) {
 		return
 	}
 
-	if err := s.syncJob.Run(ctx); err != nil {
+	startTime := time.Now()
+	err := s.syncJob.Run(ctx)
+	s.observeJob(jobRAGSync, startTime, err)
+
+	if err != nil {
 		s.logger.Error().
 			Err(err).
 			Msg("Scheduled RAG sync failed")
@@ -165,32 +374,21 @@ func (s *Scheduler) runRAGSync(ctx context.Context) {
 	}
 }
 
-// runDailySummaries generates and sends summaries for all allowed chats
-func (s *Scheduler) runDailySummaries(ctx context.Context) {
-	s.logger.Info().Msg("Running daily summaries for all chats")
-
-	// Get yesterday's date in Moscow timezone
-	now := time.Now().In(s.timezone)
-	yesterday := now.AddDate(0, 0, -1)
-	dateStr := yesterday.Format("2006-01-02")
+// runChatSummary generates and sends the summary of yesterday for a chat
+func (s *Scheduler) runChatSummary(ctx context.Context, chatID int64) {
+	// Get yesterday's date in the chat's timezone
+	dateStr := time.Now().In(s.settings.ChatLocation(ctx, chatID)).AddDate(0, 0, -1).Format("2006-01-02")
 
-	s.logger.Info().
-		Str("date", dateStr).
-		Int("chat_count", len(s.config.AllowedChatIDs)).
-		Msg("Generating summaries for yesterday")
+	startTime := time.Now()
+	err := s.processChatSummary(ctx, chatID, dateStr)
+	s.observeJob(jobDailySummary, startTime, err)
 
-	// Process each allowed chat
-	for _, chatID := range s.config.AllowedChatIDs {
-		// Use a separate goroutine for each chat to avoid blocking
-		go func(cid int64) {
-			if err := s.processChatSummary(ctx, cid, dateStr); err != nil {
-				s.logger.Error().
-					Err(err).
-					Int64("chat_id", cid).
-					Str("date", dateStr).
-					Msg("Failed to process chat summary")
-			}
-		}(chatID)
+	if err != nil {
+		s.logger.Error().
+			Err(err).
+			Int64("chat_id", chatID).
+			Str("date", dateStr).
+			Msg("Failed to process chat summary")
 	}
 }
 
@@ -206,22 +404,36 @@ func (s *Scheduler) processChatSummaryWithForce(ctx context.Context, chatID int6
 	logger.Info().Msg("Processing daily summary")
 
 	// Check if summary already exists (avoid duplicates on restart)
-	if !force {
-		exists, err := s.storage.SummaryExistsForDate(ctx, chatID, date)
-		if err != nil {
-			return fmt.Errorf("failed to check if summary exists: %w", err)
-		}
+	existing, err := s.storage.GetDailySummary(ctx, chatID, date)
+	if err != nil {
+		return fmt.Errorf("failed to check if summary exists: %w", err)
+	}
 
-		if exists {
+	if existing != nil && !force {
+		if existing.DeliveryStatus == models.DeliveryStatusSent {
 			logger.Info().Msg("Summary already exists for this date, skipping")
 			return nil
 		}
-	} else {
+
+		// Generated before but not delivered, send the stored text again
+		logger.Info().Str("delivery_status", existing.DeliveryStatus).Msg("Summary exists but was not delivered, retrying delivery")
+		return s.deliverSummary(ctx, existing, 0)
+	}
+	if force {
 		logger.Info().Msg("Force flag set, will regenerate summary if exists")
 	}
 
+	// A regenerated summary replaces the pinned message of the previous one
+	var replacedMessageID int64
+	if existing != nil {
+		replacedMessageID = existing.SentMessageID
+	}
+
+	// Day boundaries follow the chat's timezone
+	loc := s.settings.ChatLocation(ctx, chatID)
+
 	// Get messages for the date
-	messages, err := s.storage.GetMessagesForDate(ctx, chatID, date)
+	messages, err := s.storage.GetMessagesForDate(ctx, chatID, date, loc)
 	if err != nil {
 		return fmt.Errorf("failed to get messages: %w", err)
 	}
@@ -232,9 +444,8 @@ func (s *Scheduler) processChatSummaryWithForce(ctx context.Context, chatID int6
 		return nil
 	}
 
-	loc := s.timezone
-	firstMsgMoscow := messages[0].CreatedAt.In(loc)
-	lastMsgMoscow := messages[len(messages)-1].CreatedAt.In(loc)
+	firstMsgLocal := messages[0].CreatedAt.In(loc)
+	lastMsgLocal := messages[len(messages)-1].CreatedAt.In(loc)
 	allMatch := true
 	for _, msg := range messages {
 		if msg.CreatedAt.In(loc).Format("2006-01-02") != date {
@@ -245,25 +456,37 @@ func (s *Scheduler) processChatSummaryWithForce(ctx context.Context, chatID int6
 
 	logger.Info().
 		Int("message_count", len(messages)).
-		Str("first_message_moscow", firstMsgMoscow.Format(time.RFC3339)).
-		Str("last_message_moscow", lastMsgMoscow.Format(time.RFC3339)).
+		Str("timezone", loc.String()).
+		Str("first_message_local", firstMsgLocal.Format(time.RFC3339)).
+		Str("last_message_local", lastMsgLocal.Format(time.RFC3339)).
 		Bool("all_messages_match_date", allMatch).
-		Msg("Retrieved messages for summary (Moscow time)")
+		Msg("Retrieved messages for summary")
 
-	// Get most active user
-	mostActiveUser, err := s.storage.GetMostActiveUser(ctx, chatID, date)
+	// Get message counts per user, most active first
+	counts, err := s.storage.GetUserMessageCounts(ctx, chatID, date, loc)
 	if err != nil {
-		return fmt.Errorf("failed to get most active user: %w", err)
+		return fmt.Errorf("failed to get user message counts: %w", err)
 	}
+	sortUserMessageCounts(counts)
 
-	// Generate summary using LLM
-	result, err := s.generator.GenerateSummary(ctx, messages, date)
+	// Generate summary using LLM, in the chat's language
+	tr := i18n.New(s.settings.ChatLocale(ctx, chatID))
+	result, err := s.generator.GenerateSummary(ctx, messages, date, loc, tr)
 	if err != nil {
 		return fmt.Errorf("failed to generate summary: %w", err)
 	}
 
-	// Format summary message
-	summaryText := s.formatSummaryMessage(date, result.Topics, mostActiveUser)
+	// Format summary message with the sections enabled in the chat and its template
+	report := &dailyReport{
+		chatID:   chatID,
+		date:     date,
+		loc:      loc,
+		messages: messages,
+		counts:   counts,
+		result:   result,
+		tr:       tr,
+	}
+	summaryText := s.formatSummaryMessage(report, s.settings.SummaryReport(ctx, chatID), s.settings.SummaryTemplate(ctx, chatID))
 
 	// Save to database
 	dailySummary := &models.DailySummary{
@@ -271,22 +494,21 @@ func (s *Scheduler) processChatSummaryWithForce(ctx context.Context, chatID int6
 		Date:         date,
 		SummaryText:  summaryText,
 		MessageCount: len(messages),
+		Data:         &models.StructuredSummary{Topics: result.Topics, Quote: result.Quote},
 	}
 
-	if mostActiveUser != nil {
-		dailySummary.MostActiveUserID = mostActiveUser.UserID
-		dailySummary.MostActiveUsername = mostActiveUser.Username
+	if len(counts) > 0 {
+		dailySummary.MostActiveUserID = counts[0].UserID
+		dailySummary.MostActiveUsername = counts[0].Username
 	}
 
 	if err := s.storage.SaveDailySummary(ctx, dailySummary); err != nil {
 		return fmt.Errorf("failed to save summary: %w", err)
 	}
 
-	// Send summary to chat
-	if s.summaryCallback != nil {
-		if err := s.summaryCallback(chatID, summaryText); err != nil {
-			return fmt.Errorf("failed to send summary: %w", err)
-		}
+	// Send summary to chat, a failed delivery is retried by the delivery job
+	if err := s.deliverSummary(ctx, dailySummary, replacedMessageID); err != nil {
+		return err
 	}
 
 	logger.Info().
@@ -299,8 +521,8 @@ func (s *Scheduler) processChatSummaryWithForce(ctx context.Context, chatID int6
 
 // GenerateSummaryForYesterday generates summary for yesterday for a specific chat (used for manual /summary command)
 func (s *Scheduler) GenerateSummaryForYesterday(ctx context.Context, chatID int64) error {
-	// Get yesterday's date in Moscow timezone
-	now := time.Now().In(s.timezone)
+	// Get yesterday's date in the chat's timezone
+	now := time.Now().In(s.settings.ChatLocation(ctx, chatID))
 	yesterday := now.AddDate(0, 0, -1)
 	dateStr := yesterday.Format("2006-01-02")
 
@@ -332,60 +554,64 @@ func escapeMarkdownV1(text string) string {
 	return result
 }
 
-// formatSummaryMessage formats the summary into a nice Telegram message
-func (s *Scheduler) formatSummaryMessage(date string, topics []string, mostActiveUser *models.UserMessageCount) string {
-	// Parse date for prettier display
-	t, err := time.Parse("2006-01-02", date)
-	var dateDisplay string
-	if err == nil {
-		// Format as "20 ноября"
-		months := []string{
-			"января", "февраля", "марта", "апреля", "мая", "июня",
-			"июля", "августа", "сентября", "октября", "ноября", "декабря",
+// formatTopics formats structured topics with descriptions, participants and links to messages
+func formatTopics(chatID int64, topics []models.SummaryTopic) string {
+	var sb strings.Builder
+	for i, topic := range topics {
+		if i > 0 {
+			sb.WriteString("\n")
 		}
-		dateDisplay = fmt.Sprintf("%d %s", t.Day(), months[t.Month()-1])
-	} else {
-		dateDisplay = date
-	}
-
-	var message string
-	message = fmt.Sprintf("📊 *Саммари за %s*\n\n", dateDisplay)
-
-	if len(topics) > 0 {
-		message += "*Основные темы обсуждения:*\n"
-		for _, topic := range topics {
-			message += topic + "\n"
+		sb.WriteString(formatTopicLine(topic))
+		if topic.Description != "" {
+			sb.WriteString(" — " + escapeMarkdownV1(topic.Description))
 		}
-	} else {
-		message += "*В этот день не было активных обсуждений*\n"
-	}
+		sb.WriteString("\n")
 
-	if mostActiveUser != nil {
-		username := mostActiveUser.Username
-		if username == "" {
-			username = mostActiveUser.FirstName
+		var details []string
+		if len(topic.Participants) > 0 {
+			names := make([]string, len(topic.Participants))
+			for i, name := range topic.Participants {
+				names[i] = escapeMarkdownV1(name)
+			}
+			details = append(details, "👥 "+strings.Join(names, ", "))
 		}
-		if username == "" {
-			username = fmt.Sprintf("User%d", mostActiveUser.UserID)
+		if links := messageLinks(chatID, topic.MessageIDs); links != "" {
+			details = append(details, "💬 "+links)
 		}
-
-		// Escape special Markdown characters in username
-		escapedUsername := escapeMarkdownV1(username)
-
-		// Format message count with proper Russian pluralization
-		var msgWord string
-		count := mostActiveUser.MessageCount
-		if count%10 == 1 && count%100 != 11 {
-			msgWord = "сообщение"
-		} else if (count%10 >= 2 && count%10 <= 4) && (count%100 < 10 || count%100 >= 20) {
-			msgWord = "сообщения"
-		} else {
-			msgWord = "сообщений"
+		if len(details) > 0 {
+			sb.WriteString(strings.Join(details, " · ") + "\n")
 		}
+	}
+	return sb.String()
+}
 
-		message += fmt.Sprintf("\n*Вчера больше всех пиздел:* @%s (%d %s)",
-			escapedUsername, count, msgWord)
+// formatTopicLine formats the emoji and the bold title of a topic
+func formatTopicLine(topic models.SummaryTopic) string {
+	line := "*" + escapeMarkdownV1(topic.Title) + "*"
+	if topic.Emoji != "" {
+		line = topic.Emoji + " " + line
+	}
+	return line
+}
+
+// messageLinks formats links to chat messages
+// Links only work in supergroups, other chats get no links
+func messageLinks(chatID int64, messageIDs []int64) string {
+	if chatID > supergroupIDPrefix || len(messageIDs) == 0 {
+		return ""
 	}
 
-	return message
+	links := make([]string, len(messageIDs))
+	for i, id := range messageIDs {
+		links[i] = fmt.Sprintf("[%d](%s)", i+1, messageLink(chatID, id))
+	}
+	return strings.Join(links, " ")
+}
+
+// messageLink returns the link to a message of a supergroup, empty for other chats
+func messageLink(chatID, messageID int64) string {
+	if chatID > supergroupIDPrefix {
+		return ""
+	}
+	return fmt.Sprintf("https://t.me/c/%d/%d", supergroupIDPrefix-chatID, messageID)
 }
@@ -49,6 +49,9 @@ func (j *SyncJob) Run(ctx context.Context) error {
 		return fmt.Errorf("failed to get unindexed messages: %w", err)
 	}
 
+	// Summaries are few and indexed independently of messages
+	j.indexSummaries(ctx)
+
 	if len(messages) == 0 {
 		j.logger.Info().Msg("No unindexed messages found")
 		return nil
@@ -124,3 +127,40 @@ func (j *SyncJob) processBatch(ctx context.Context, messages []*models.ChatMessa
 
 	return updated, nil
 }
+
+// indexSummaries generates embeddings of daily summaries for /summaries search
+// Failures are logged, unindexed summaries are picked up by the next run
+func (j *SyncJob) indexSummaries(ctx context.Context) {
+	summaries, err := j.storage.GetUnindexedSummaries(ctx, j.batchSize)
+	if err != nil {
+		j.logger.Error().Err(err).Msg("Failed to get unindexed summaries")
+		return
+	}
+
+	if len(summaries) == 0 {
+		return
+	}
+
+	texts := make([]string, len(summaries))
+	ids := make([]int64, len(summaries))
+	for i := range summaries {
+		texts[i] = summaries[i].EmbeddingText()
+		ids[i] = summaries[i].ID
+	}
+
+	embeddings, err := j.embeddingsClient.GenerateEmbeddingsBatch(ctx, texts)
+	if err != nil {
+		j.logger.Error().Err(err).Msg("Failed to generate summary embeddings")
+		return
+	}
+
+	updated, err := j.storage.BatchUpdateSummaryEmbeddings(ctx, ids, embeddings)
+	if err != nil {
+		j.logger.Error().Err(err).Msg("Failed to update summary embeddings")
+		return
+	}
+
+	j.logger.Info().
+		Int("indexed", updated).
+		Msg("Summaries indexed")
+}
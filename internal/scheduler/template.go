@@ -0,0 +1,187 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/telegram-llm-bot/internal/i18n"
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+const (
+	// maxSummaryTemplateLength limits the source of chat summary templates
+	maxSummaryTemplateLength = 4000
+
+	// maxSummaryTemplateOutput stops templates that render far more than a Telegram message
+	maxSummaryTemplateOutput = 16 * 1024
+)
+
+// defaultSummaryTemplateText renders the daily summary when the chat has no template of its own:
+// the heading followed by every enabled section under its title
+const defaultSummaryTemplateText = `{{.Header}}{{range .Sections}}
+*{{escape .Title}}:*
+{{.Body}}{{end}}`
+
+// defaultSummaryTemplate is the parsed defaultSummaryTemplateText
+var defaultSummaryTemplate = template.Must(newSummaryTemplate().Parse(defaultSummaryTemplateText))
+
+// errSummaryTemplateOutput means a template rendered more than maxSummaryTemplateOutput
+var errSummaryTemplateOutput = errors.New("summary template output is too long")
+
+// summaryTemplateData is what summary templates are executed with
+// Section bodies are Telegram Markdown, titles and names are plain text to be passed through escape
+type summaryTemplateData struct {
+	Header           string                             // Summary heading with the date, Markdown
+	Date             string                             // Day and month in the chat's language, e.g. "20 ноября"
+	ISODate          string                             // YYYY-MM-DD
+	MessageCount     int                                // Messages of the day
+	ParticipantCount int                                // Users who wrote that day
+	Sections         []summaryTemplateSection           // Enabled non-empty sections in display order
+	Section          map[string]*summaryTemplateSection // The same sections by name, nil if skipped: {{with .Section.top}}
+}
+
+// summaryTemplateSection is one rendered section of the daily summary
+type summaryTemplateSection struct {
+	Name  string // Section name as in /summaryconfig, e.g. "topics"
+	Title string // Custom or default heading
+	Body  string
+}
+
+// newSummaryTemplate creates a summary template with its functions
+func newSummaryTemplate() *template.Template {
+	return template.New("summary").
+		Option("missingkey=zero").
+		Funcs(template.FuncMap{
+			"escape": escapeMarkdownV1,
+		})
+}
+
+// ValidateSummaryTemplate checks that a chat summary template parses and renders a non-empty message
+// The template is executed with sample data, so references to unknown fields are reported as well
+func (s *Scheduler) ValidateSummaryTemplate(text string) error {
+	if len(text) > maxSummaryTemplateLength {
+		return fmt.Errorf("template is longer than %d bytes", maxSummaryTemplateLength)
+	}
+
+	tmpl, err := newSummaryTemplate().Parse(text)
+	if err != nil {
+		return err
+	}
+
+	tr := i18n.New(i18n.DefaultLocale)
+	data := &summaryTemplateData{
+		Header:           tr.T("summary.title", "1"),
+		Date:             "1",
+		ISODate:          "2006-01-02",
+		MessageCount:     1,
+		ParticipantCount: 1,
+		Section:          make(map[string]*summaryTemplateSection),
+	}
+	for _, name := range models.SummarySections {
+		section := summaryTemplateSection{Name: name, Title: tr.T("summary.section." + name), Body: "-\n"}
+		data.Sections = append(data.Sections, section)
+		data.Section[name] = &section
+	}
+
+	_, err = executeSummaryTemplate(tmpl, data)
+	return err
+}
+
+// DefaultSummaryTemplate returns the template used by chats without their own
+func (s *Scheduler) DefaultSummaryTemplate() string {
+	return defaultSummaryTemplateText
+}
+
+// executeSummaryTemplate renders a summary template, failing on an empty or oversized output
+func executeSummaryTemplate(tmpl *template.Template, data *summaryTemplateData) (string, error) {
+	out := &limitedBuilder{limit: maxSummaryTemplateOutput}
+	if err := tmpl.Execute(out, data); err != nil {
+		return "", err
+	}
+
+	text := out.String()
+	if strings.TrimSpace(text) == "" {
+		return "", errors.New("template renders an empty message")
+	}
+	return text, nil
+}
+
+// limitedBuilder is a strings.Builder refusing writes past the limit
+type limitedBuilder struct {
+	strings.Builder
+	limit int
+}
+
+// Write appends p unless the output would exceed the limit
+func (b *limitedBuilder) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.limit {
+		return 0, errSummaryTemplateOutput
+	}
+	return b.Builder.Write(p)
+}
+
+// PreviewSummary renders the latest stored daily summary of the chat with a template, the chat's own if empty
+// Topics and the quote come from the stored summary, so nothing is generated. Returns an empty text if the
+// chat has no stored summaries
+func (s *Scheduler) PreviewSummary(ctx context.Context, chatID int64, text string) (string, error) {
+	if text == "" {
+		text = s.settings.SummaryTemplate(ctx, chatID)
+	}
+	tmpl := defaultSummaryTemplate
+	if text != "" {
+		var err error
+		if tmpl, err = newSummaryTemplate().Parse(text); err != nil {
+			return "", err
+		}
+	}
+
+	dates, err := s.storage.GetSummaryDates(ctx, chatID, 1)
+	if err != nil {
+		return "", fmt.Errorf("failed to get summary dates: %w", err)
+	}
+	if len(dates) == 0 {
+		return "", nil
+	}
+	date := dates[0]
+
+	summary, err := s.storage.GetDailySummary(ctx, chatID, date)
+	if err != nil {
+		return "", fmt.Errorf("failed to get summary: %w", err)
+	}
+	if summary == nil {
+		return "", nil
+	}
+
+	loc := s.settings.ChatLocation(ctx, chatID)
+	messages, err := s.storage.GetMessagesForDate(ctx, chatID, date, loc)
+	if err != nil {
+		return "", fmt.Errorf("failed to get messages: %w", err)
+	}
+
+	counts, err := s.storage.GetUserMessageCounts(ctx, chatID, date, loc)
+	if err != nil {
+		return "", fmt.Errorf("failed to get user message counts: %w", err)
+	}
+	sortUserMessageCounts(counts)
+
+	result := &models.SummaryResult{}
+	if summary.Data != nil {
+		result.Topics = summary.Data.Topics
+		result.Quote = summary.Data.Quote
+	}
+
+	report := &dailyReport{
+		chatID:   chatID,
+		date:     date,
+		loc:      loc,
+		messages: messages,
+		counts:   counts,
+		result:   result,
+		tr:       i18n.New(s.settings.ChatLocale(ctx, chatID)),
+	}
+
+	return executeSummaryTemplate(tmpl, s.summaryTemplateData(report, s.settings.SummaryReport(ctx, chatID)))
+}
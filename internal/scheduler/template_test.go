@@ -0,0 +1,133 @@
+package scheduler
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestLimitedBuilder(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   int
+		writes  []string
+		want    string
+		wantErr bool // Error of the last write
+	}{
+		{name: "under limit", limit: 10, writes: []string{"abc", "def"}, want: "abcdef"},
+		{name: "exactly at limit", limit: 6, writes: []string{"abc", "def"}, want: "abcdef"},
+		{name: "write past limit refused", limit: 5, writes: []string{"abc", "def"}, want: "abc", wantErr: true},
+		{name: "empty write at limit", limit: 3, writes: []string{"abc", ""}, want: "abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &limitedBuilder{limit: tt.limit}
+			var err error
+			for _, w := range tt.writes {
+				_, err = b.Write([]byte(w))
+			}
+
+			if tt.wantErr != errors.Is(err, errSummaryTemplateOutput) {
+				t.Errorf("last write error = %v, want error %v", err, tt.wantErr)
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteSummaryTemplate(t *testing.T) {
+	topics := summaryTemplateSection{Name: "topics", Title: "Top_topics", Body: "- news\n"}
+	data := &summaryTemplateData{
+		Header:       "*Summary*\n",
+		MessageCount: 42,
+		Sections:     []summaryTemplateSection{topics},
+		Section:      map[string]*summaryTemplateSection{"topics": &topics},
+	}
+
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "default template",
+			text: defaultSummaryTemplateText,
+			want: "*Summary*\n\n*Top\\_topics:*\n- news\n",
+		},
+		{
+			name: "section by name",
+			text: "{{.MessageCount}}{{with .Section.topics}} {{.Body}}{{end}}",
+			want: "42 - news\n",
+		},
+		{
+			name: "skipped section",
+			text: "{{.MessageCount}}{{with .Section.quote}} {{.Body}}{{end}}",
+			want: "42",
+		},
+		{
+			name:    "empty output",
+			text:    "{{with .Section.quote}}{{.Body}}{{end}}  \n",
+			wantErr: "template renders an empty message",
+		},
+		{
+			name:    "oversized output",
+			text:    strings.Repeat("x", maxSummaryTemplateOutput) + "{{.MessageCount}}",
+			wantErr: errSummaryTemplateOutput.Error(),
+		},
+		{
+			name:    "unknown field",
+			text:    "{{.Unknown}}",
+			wantErr: "can't evaluate field Unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := template.Must(newSummaryTemplate().Parse(tt.text))
+			got, err := executeSummaryTemplate(tmpl, data)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("executeSummaryTemplate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("executeSummaryTemplate() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("executeSummaryTemplate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSummaryTemplate(t *testing.T) {
+	s := &Scheduler{}
+
+	tests := []struct {
+		name    string
+		text    string
+		wantErr bool
+	}{
+		{name: "default template", text: defaultSummaryTemplateText},
+		{name: "plain text", text: "Good morning"},
+		{name: "every section", text: "{{range .Sections}}{{escape .Title}} {{.Body}}{{end}}"},
+		{name: "parse error", text: "{{.Header", wantErr: true},
+		{name: "unknown field", text: "{{.Topics}}", wantErr: true},
+		{name: "empty output", text: "{{if false}}x{{end}}", wantErr: true},
+		{name: "too long", text: strings.Repeat("x", maxSummaryTemplateLength+1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.ValidateSummaryTemplate(tt.text); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSummaryTemplate() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
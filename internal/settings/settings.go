@@ -0,0 +1,347 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/telegram-llm-bot/internal/i18n"
+	"github.com/telegram-llm-bot/internal/models"
+	"github.com/telegram-llm-bot/internal/storage"
+)
+
+// cacheTTL limits how long settings are cached, so changes made by other instances apply eventually
+const cacheTTL = 5 * time.Minute
+
+// cacheEntry holds cached settings with their load time
+type cacheEntry[T any] struct {
+	value    *T
+	loadedAt time.Time
+}
+
+// Service resolves per-chat and per-user settings with fallback to the global configuration
+// Settings are cached in memory and invalidated on changes made through the service
+type Service struct {
+	storage         *storage.Client
+	defaultLocation *time.Location
+	defaultLocale   string
+	logger          zerolog.Logger
+
+	mu    sync.Mutex
+	chats map[int64]cacheEntry[models.ChatSettings]
+	users map[int64]cacheEntry[models.UserSettings]
+}
+
+// NewService creates a settings service using the global TIMEZONE and DEFAULT_LOCALE as defaults
+func NewService(storage *storage.Client, config *models.BotConfig, logger zerolog.Logger) (*Service, error) {
+	loc, err := time.LoadLocation(config.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load timezone %s: %w", config.Timezone, err)
+	}
+
+	return &Service{
+		storage:         storage,
+		defaultLocation: loc,
+		defaultLocale:   config.DefaultLocale,
+		logger:          logger.With().Str("component", "settings").Logger(),
+		chats:           make(map[int64]cacheEntry[models.ChatSettings]),
+		users:           make(map[int64]cacheEntry[models.UserSettings]),
+	}, nil
+}
+
+// DefaultLocation returns the global timezone
+func (s *Service) DefaultLocation() *time.Location {
+	return s.defaultLocation
+}
+
+// ChatLocation returns the timezone of a chat, falling back to the global one
+// Used for chat-wide dates: summaries, schedules, chat pool and analytics
+func (s *Service) ChatLocation(ctx context.Context, chatID int64) *time.Location {
+	if chat := s.chatSettings(ctx, chatID); chat != nil {
+		if loc := s.loadLocation(chat.Timezone); loc != nil {
+			return loc
+		}
+	}
+	return s.defaultLocation
+}
+
+// UserLocation returns the timezone of a user in a chat
+// Resolution order: user timezone > chat timezone > global timezone
+// Used to show and parse times for the user, never for daily limits: see LimitLocation
+func (s *Service) UserLocation(ctx context.Context, userID, chatID int64) *time.Location {
+	if user := s.userSettings(ctx, userID); user != nil {
+		if loc := s.loadLocation(user.Timezone); loc != nil {
+			return loc
+		}
+	}
+	return s.ChatLocation(ctx, chatID)
+}
+
+// LimitLocation returns the timezone of the days of personal daily limits, the global one
+// Any user can change their own timezone with /mytimezone, so a day in it could be moved forward to reset used limits;
+// limits are per user across chats, so chat timezones can't be used either
+func (s *Service) LimitLocation() *time.Location {
+	return s.defaultLocation
+}
+
+// ChatTimezone returns the timezone configured for the chat, empty if none
+func (s *Service) ChatTimezone(ctx context.Context, chatID int64) string {
+	if chat := s.chatSettings(ctx, chatID); chat != nil {
+		return chat.Timezone
+	}
+	return ""
+}
+
+// UserTimezone returns the timezone configured by the user, empty if none
+func (s *Service) UserTimezone(ctx context.Context, userID int64) string {
+	if user := s.userSettings(ctx, userID); user != nil {
+		return user.Timezone
+	}
+	return ""
+}
+
+// SetChatTimezone validates and saves the timezone of a chat, empty resets it
+func (s *Service) SetChatTimezone(ctx context.Context, chatID int64, timezone string, updatedBy int64) error {
+	if err := ValidateTimezone(timezone); err != nil {
+		return err
+	}
+	if err := s.storage.SetChatTimezone(ctx, chatID, timezone, updatedBy); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.chats, chatID)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// ChatLocale returns the language of a chat, falling back to the global one
+// Used for chat-wide messages: summaries and digests
+func (s *Service) ChatLocale(ctx context.Context, chatID int64) string {
+	if chat := s.chatSettings(ctx, chatID); chat != nil && i18n.Supported(chat.Locale) {
+		return chat.Locale
+	}
+	return s.defaultLocale
+}
+
+// UserLocale returns the language of replies to a user in a chat
+// Resolution order: user locale > Telegram language code of the user > chat locale > global locale
+func (s *Service) UserLocale(ctx context.Context, userID, chatID int64, languageCode string) string {
+	if user := s.userSettings(ctx, userID); user != nil && i18n.Supported(user.Locale) {
+		return user.Locale
+	}
+	if locale := i18n.Match(languageCode); locale != "" {
+		return locale
+	}
+	return s.ChatLocale(ctx, chatID)
+}
+
+// ChatLocaleSetting returns the locale configured for the chat, empty if none
+func (s *Service) ChatLocaleSetting(ctx context.Context, chatID int64) string {
+	if chat := s.chatSettings(ctx, chatID); chat != nil {
+		return chat.Locale
+	}
+	return ""
+}
+
+// UserLocaleSetting returns the locale configured by the user, empty if none
+func (s *Service) UserLocaleSetting(ctx context.Context, userID int64) string {
+	if user := s.userSettings(ctx, userID); user != nil {
+		return user.Locale
+	}
+	return ""
+}
+
+// SetChatLocale validates and saves the language of a chat, empty resets it
+func (s *Service) SetChatLocale(ctx context.Context, chatID int64, locale string, updatedBy int64) error {
+	if err := ValidateLocale(locale); err != nil {
+		return err
+	}
+	if err := s.storage.SetChatLocale(ctx, chatID, locale, updatedBy); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.chats, chatID)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// SetUserLocale validates and saves the language of a user, empty resets it
+func (s *Service) SetUserLocale(ctx context.Context, userID int64, locale string) error {
+	if err := ValidateLocale(locale); err != nil {
+		return err
+	}
+	if err := s.storage.SetUserLocale(ctx, userID, locale); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.users, userID)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// SummaryReport returns the daily summary report sections configured for the chat, nil for defaults
+func (s *Service) SummaryReport(ctx context.Context, chatID int64) models.SummaryReport {
+	if chat := s.chatSettings(ctx, chatID); chat != nil {
+		return chat.SummaryReport
+	}
+	return nil
+}
+
+// SetSummaryReport saves the daily summary report sections of a chat, empty resets them
+func (s *Service) SetSummaryReport(ctx context.Context, chatID int64, report models.SummaryReport, updatedBy int64) error {
+	if err := s.storage.SetChatSummaryReport(ctx, chatID, report, updatedBy); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.chats, chatID)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// SummaryTemplate returns the daily summary template of the chat, empty for the default one
+func (s *Service) SummaryTemplate(ctx context.Context, chatID int64) string {
+	if chat := s.chatSettings(ctx, chatID); chat != nil {
+		return chat.SummaryTemplate
+	}
+	return ""
+}
+
+// SetSummaryTemplate saves the daily summary template of a chat, empty resets it
+// The template must be validated by the caller, settings don't know how summaries are rendered
+func (s *Service) SetSummaryTemplate(ctx context.Context, chatID int64, template string, updatedBy int64) error {
+	if err := s.storage.SetChatSummaryTemplate(ctx, chatID, template, updatedBy); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.chats, chatID)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// SummaryDelivery returns the forum topic (0 for the general chat) and pinning of summaries of the chat
+func (s *Service) SummaryDelivery(ctx context.Context, chatID int64) (threadID int64, pin bool) {
+	if chat := s.chatSettings(ctx, chatID); chat != nil {
+		return chat.SummaryThreadID, chat.SummaryPin
+	}
+	return 0, false
+}
+
+// SetSummaryDelivery saves the forum topic and pinning of summaries of a chat
+func (s *Service) SetSummaryDelivery(ctx context.Context, chatID, threadID int64, pin bool, updatedBy int64) error {
+	if err := s.storage.SetChatSummaryDelivery(ctx, chatID, threadID, pin, updatedBy); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.chats, chatID)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// SetUserTimezone validates and saves the timezone of a user, empty resets it
+func (s *Service) SetUserTimezone(ctx context.Context, userID int64, timezone string) error {
+	if err := ValidateTimezone(timezone); err != nil {
+		return err
+	}
+	if err := s.storage.SetUserTimezone(ctx, userID, timezone); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.users, userID)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// ValidateTimezone checks that a timezone is a known IANA name. Empty is valid (reset)
+func ValidateTimezone(timezone string) error {
+	if timezone == "" {
+		return nil
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return fmt.Errorf("unknown timezone %s", timezone)
+	}
+	return nil
+}
+
+// ValidateLocale checks that a locale has a message catalog. Empty is valid (reset)
+func ValidateLocale(locale string) error {
+	if locale == "" || i18n.Supported(locale) {
+		return nil
+	}
+	return fmt.Errorf("unsupported locale %s", locale)
+}
+
+// chatSettings returns cached settings of a chat, loading them if needed
+// Storage errors are logged and treated as "no settings" without caching
+func (s *Service) chatSettings(ctx context.Context, chatID int64) *models.ChatSettings {
+	s.mu.Lock()
+	entry, ok := s.chats[chatID]
+	s.mu.Unlock()
+	if ok && time.Since(entry.loadedAt) < cacheTTL {
+		return entry.value
+	}
+
+	chat, err := s.storage.GetChatSettings(ctx, chatID)
+	if err != nil {
+		s.logger.Warn().Err(err).Int64("chat_id", chatID).Msg("Failed to load chat settings, using defaults")
+		return nil
+	}
+
+	s.mu.Lock()
+	s.chats[chatID] = cacheEntry[models.ChatSettings]{value: chat, loadedAt: time.Now()}
+	s.mu.Unlock()
+
+	return chat
+}
+
+// userSettings returns cached settings of a user, loading them if needed
+func (s *Service) userSettings(ctx context.Context, userID int64) *models.UserSettings {
+	s.mu.Lock()
+	entry, ok := s.users[userID]
+	s.mu.Unlock()
+	if ok && time.Since(entry.loadedAt) < cacheTTL {
+		return entry.value
+	}
+
+	user, err := s.storage.GetUserSettings(ctx, userID)
+	if err != nil {
+		s.logger.Warn().Err(err).Int64("user_id", userID).Msg("Failed to load user settings, using defaults")
+		return nil
+	}
+
+	s.mu.Lock()
+	s.users[userID] = cacheEntry[models.UserSettings]{value: user, loadedAt: time.Now()}
+	s.mu.Unlock()
+
+	return user
+}
+
+// loadLocation loads a stored timezone, nil if it is empty or invalid
+func (s *Service) loadLocation(timezone string) *time.Location {
+	if timezone == "" {
+		return nil
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		s.logger.Warn().Err(err).Str("timezone", timezone).Msg("Invalid stored timezone, ignoring")
+		return nil
+	}
+
+	return loc
+}
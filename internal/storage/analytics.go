@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+// GetTopAskers returns users with the most bot requests in a chat since the given time
+func (c *Client) GetTopAskers(ctx context.Context, chatID int64, since time.Time, limit int) ([]models.UserActivity, error) {
+	var results []models.UserActivity
+
+	err := c.callAnalytics(ctx, "analytics_top_askers", map[string]interface{}{
+		"p_chat_id": chatID,
+		"p_since":   since.UTC(),
+		"p_limit":   limit,
+	}, &results)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetTopChatters returns users with the most chat messages since the given time
+func (c *Client) GetTopChatters(ctx context.Context, chatID int64, since time.Time, limit int) ([]models.UserActivity, error) {
+	var results []models.UserActivity
+
+	err := c.callAnalytics(ctx, "analytics_top_chatters", map[string]interface{}{
+		"p_chat_id": chatID,
+		"p_since":   since.UTC(),
+		"p_limit":   limit,
+	}, &results)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetModelUsage returns requests, errors, latency and cost per model in a chat since the given time
+func (c *Client) GetModelUsage(ctx context.Context, chatID int64, since time.Time) ([]models.ModelUsage, error) {
+	var results []models.ModelUsage
+
+	err := c.callAnalytics(ctx, "analytics_model_usage", map[string]interface{}{
+		"p_chat_id": chatID,
+		"p_since":   since.UTC(),
+	}, &results)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetWeeklyTrend returns per-week requests, messages and active users for the last weeks
+// Weeks start on Monday in the given timezone, the chat's one
+func (c *Client) GetWeeklyTrend(ctx context.Context, chatID int64, weeks int, loc *time.Location) ([]models.WeeklyUsage, error) {
+	var results []models.WeeklyUsage
+
+	err := c.callAnalytics(ctx, "analytics_weekly_trend", map[string]interface{}{
+		"p_chat_id":  chatID,
+		"p_weeks":    weeks,
+		"p_timezone": loc.String(),
+	}, &results)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetImageGenerationsSince returns the number of images generated in a chat since the given time
+func (c *Client) GetImageGenerationsSince(ctx context.Context, chatID int64, since time.Time) (int64, error) {
+	var results []struct {
+		Count int64 `json:"count"`
+	}
+
+	err := c.callAnalytics(ctx, "analytics_image_generations", map[string]interface{}{
+		"p_chat_id": chatID,
+		"p_since":   since.UTC(),
+	}, &results)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(results) == 0 {
+		return 0, nil
+	}
+
+	return results[0].Count, nil
+}
+
+// callAnalytics calls an analytics RPC function and unmarshals its rows into out
+func (c *Client) callAnalytics(ctx context.Context, function string, params map[string]interface{}, out interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	err := c.withRetry(ctx, function, func() error {
+		data := c.client.Rpc(function, "", params)
+		if data == "" {
+			return fmt.Errorf("failed to call %s: RPC returned empty", function)
+		}
+
+		if err := json.Unmarshal([]byte(data), out); err != nil {
+			return fmt.Errorf("failed to parse %s result: %w", function, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		c.logger.Error().
+			Err(err).
+			Str("function", function).
+			Msg("Failed to load analytics")
+		return err
+	}
+
+	return nil
+}
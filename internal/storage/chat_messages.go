@@ -27,6 +27,9 @@ func (c *Client) SaveChatMessage(ctx context.Context, msg *models.ChatMessage) e
 			"indexed":      false,
 			"created_at":   msg.CreatedAt,
 		}
+		if msg.ReplyToMessageID != 0 {
+			data["reply_to_message_id"] = msg.ReplyToMessageID
+		}
 
 		// Insert message (ignore if already exists due to unique constraint)
 		_, _, err := c.client.From("chat_messages").
@@ -194,16 +197,7 @@ func (c *Client) BatchUpdateEmbeddings(ctx context.Context, ids []int64, embeddi
 
 	var rowsUpdated int
 
-	// Convert embeddings to PostgreSQL vector format: "[1.0, 2.0, 3.0]"
-	embeddingsStr := make([]string, len(embeddings))
-	for i, emb := range embeddings {
-		// Convert []float32 to string format
-		parts := make([]string, len(emb))
-		for j, val := range emb {
-			parts[j] = fmt.Sprintf("%v", val)
-		}
-		embeddingsStr[i] = "[" + strings.Join(parts, ",") + "]"
-	}
+	embeddingsStr := formatVectors(embeddings)
 
 	err := c.withRetry(ctx, "batch_update_embeddings", func() error {
 		// Call PostgreSQL function with string format vectors
@@ -252,6 +246,19 @@ func (c *Client) BatchUpdateEmbeddings(ctx context.Context, ids []int64, embeddi
 	return rowsUpdated, nil
 }
 
+// formatVectors converts embeddings to PostgreSQL vector format: "[1.0,2.0,3.0]"
+func formatVectors(embeddings [][]float32) []string {
+	vectors := make([]string, len(embeddings))
+	for i, emb := range embeddings {
+		parts := make([]string, len(emb))
+		for j, val := range emb {
+			parts[j] = fmt.Sprintf("%v", val)
+		}
+		vectors[i] = "[" + strings.Join(parts, ",") + "]"
+	}
+	return vectors
+}
+
 // SearchSimilarMessages searches for similar messages using vector similarity
 func (c *Client) SearchSimilarMessages(
 	ctx context.Context,
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+// IncrementChatUsage counts a text request of a user against the chat-wide pool
+func (c *Client) IncrementChatUsage(ctx context.Context, chatID, userID int64, date string, modelType models.ModelType) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	err := c.withRetry(ctx, "increment_chat_usage", func() error {
+		modelTypeStr := "flash"
+		if modelType == models.ModelPro {
+			modelTypeStr = "pro"
+		}
+
+		params := map[string]interface{}{
+			"p_chat_id":    chatID,
+			"p_user_id":    userID,
+			"p_date":       date,
+			"p_model_type": modelTypeStr,
+		}
+
+		result := c.client.Rpc("increment_chat_usage", "", params)
+		if result == "" {
+			return fmt.Errorf("failed to increment chat usage: RPC returned empty")
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		c.logger.Error().
+			Err(err).
+			Int64("chat_id", chatID).
+			Int64("user_id", userID).
+			Str("date", date).
+			Str("model", string(modelType)).
+			Msg("Failed to increment chat usage")
+		return err
+	}
+
+	c.logger.Debug().
+		Int64("chat_id", chatID).
+		Int64("user_id", userID).
+		Str("date", date).
+		Str("model", string(modelType)).
+		Msg("Chat usage incremented")
+
+	return nil
+}
+
+// GetChatUsage retrieves per-user text requests counted against the chat pool on a date
+// Entries are sorted by total requests, most active users first
+func (c *Client) GetChatUsage(ctx context.Context, chatID int64, date string) ([]models.ChatUsageEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var entries []models.ChatUsageEntry
+
+	err := c.withRetry(ctx, "get_chat_usage", func() error {
+		data := c.client.Rpc("get_chat_usage", "", map[string]interface{}{
+			"p_chat_id": chatID,
+			"p_date":    date,
+		})
+
+		if data == "" {
+			return fmt.Errorf("failed to get chat usage: RPC returned empty")
+		}
+
+		if err := json.Unmarshal([]byte(data), &entries); err != nil {
+			return fmt.Errorf("failed to parse chat usage: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		c.logger.Error().
+			Err(err).
+			Int64("chat_id", chatID).
+			Str("date", date).
+			Msg("Failed to get chat usage")
+		return nil, err
+	}
+
+	return entries, nil
+}
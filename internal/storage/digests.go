@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+// SaveDigest stores a generated digest, replacing an existing one of the same period
+func (c *Client) SaveDigest(ctx context.Context, digest *models.ChatDigest) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	if digest.CreatedAt.IsZero() {
+		digest.CreatedAt = time.Now().UTC()
+	}
+
+	topics := digest.Topics
+	if topics == nil {
+		topics = []string{}
+	}
+
+	err := c.withRetry(ctx, "save_digest", func() error {
+		data := map[string]interface{}{
+			"chat_id":       digest.ChatID,
+			"period":        digest.Period,
+			"period_start":  digest.PeriodStart,
+			"period_end":    digest.PeriodEnd,
+			"summary_text":  digest.SummaryText,
+			"topics":        topics,
+			"message_count": digest.MessageCount,
+			"active_users":  digest.ActiveUsers,
+			"new_users":     digest.NewUsers,
+			"created_at":    digest.CreatedAt,
+		}
+
+		_, _, err := c.client.From("chat_digests").
+			Insert(data, true, "chat_id,period,period_start", "", "").
+			Execute()
+
+		if err != nil {
+			return fmt.Errorf("failed to upsert digest: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		c.logger.Error().
+			Err(err).
+			Int64("chat_id", digest.ChatID).
+			Str("period", digest.Period).
+			Str("period_start", digest.PeriodStart).
+			Msg("Failed to save digest")
+		return err
+	}
+
+	c.logger.Info().
+		Int64("chat_id", digest.ChatID).
+		Str("period", digest.Period).
+		Str("period_start", digest.PeriodStart).
+		Int("message_count", digest.MessageCount).
+		Msg("Digest saved successfully")
+
+	return nil
+}
+
+// GetDigest retrieves the digest of a period starting at the given date, nil if there is none
+func (c *Client) GetDigest(ctx context.Context, chatID int64, period, periodStart string) (*models.ChatDigest, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var digests []models.ChatDigest
+
+	err := c.withRetry(ctx, "get_digest", func() error {
+		data, _, err := c.client.From("chat_digests").
+			Select("*", "exact", false).
+			Eq("chat_id", fmt.Sprintf("%d", chatID)).
+			Eq("period", period).
+			Eq("period_start", periodStart).
+			Limit(1, "").
+			Execute()
+
+		if err != nil {
+			return fmt.Errorf("failed to fetch digest: %w", err)
+		}
+
+		if err := json.Unmarshal(data, &digests); err != nil {
+			return fmt.Errorf("failed to unmarshal digest: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		c.logger.Error().
+			Err(err).
+			Int64("chat_id", chatID).
+			Str("period", period).
+			Str("period_start", periodStart).
+			Msg("Failed to get digest")
+		return nil, err
+	}
+
+	if len(digests) == 0 {
+		return nil, nil
+	}
+
+	return &digests[0], nil
+}
+
+// GetDailyActivity returns messages and active users per day between start and end dates (inclusive)
+// Days are counted in the given timezone; days without messages are included with zeros
+func (c *Client) GetDailyActivity(ctx context.Context, chatID int64, start, end string, loc *time.Location) ([]models.DailyActivity, error) {
+	var results []models.DailyActivity
+
+	err := c.callAnalytics(ctx, "digest_daily_activity", map[string]interface{}{
+		"p_chat_id":  chatID,
+		"p_start":    start,
+		"p_end":      end,
+		"p_timezone": loc.String(),
+	}, &results)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetParticipants returns users who wrote in the chat between since and until, most active first
+// FirstSeenAt is the time of the user's first message in the chat ever
+func (c *Client) GetParticipants(ctx context.Context, chatID int64, since, until time.Time) ([]models.Participant, error) {
+	var results []models.Participant
+
+	err := c.callAnalytics(ctx, "digest_participants", map[string]interface{}{
+		"p_chat_id": chatID,
+		"p_since":   since.UTC(),
+		"p_until":   until.UTC(),
+	}, &results)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// SampleMessages returns up to limit random messages written between since and until in chronological order
+func (c *Client) SampleMessages(ctx context.Context, chatID int64, since, until time.Time, limit int) ([]models.ChatMessage, error) {
+	var results []models.ChatMessage
+
+	err := c.callAnalytics(ctx, "sample_chat_messages", map[string]interface{}{
+		"p_chat_id": chatID,
+		"p_since":   since.UTC(),
+		"p_until":   until.UTC(),
+		"p_limit":   limit,
+	}, &results)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
@@ -4,8 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-
-	"github.com/telegram-llm-bot/internal/models"
 )
 
 // GetUserImageGenerationsToday retrieves the number of image generations for a user today
@@ -101,18 +99,19 @@ func (c *Client) GetChatImageGenerationsToday(ctx context.Context, chatID int64,
 }
 
 // CheckImageGenerationLimit checks if the user and chat have not exceeded their daily limits
-func (c *Client) CheckImageGenerationLimit(ctx context.Context, userID, chatID int64, date string, config *models.BotConfig) (allowed bool, remaining int, err error) {
+// userLimit is the user's effective (tier-resolved) limit, chatLimit is the per-chat cap
+func (c *Client) CheckImageGenerationLimit(ctx context.Context, userID, chatID int64, date string, userLimit, chatLimit int) (allowed bool, remaining int, err error) {
 	// Check user limit
 	userCount, err := c.GetUserImageGenerationsToday(ctx, userID, date)
 	if err != nil {
 		return false, 0, fmt.Errorf("failed to get user image generations: %w", err)
 	}
 
-	if userCount >= config.ImageGenerationDailyLimitPerUser {
+	if userCount >= userLimit {
 		c.logger.Info().
 			Int64("user_id", userID).
 			Int("count", userCount).
-			Int("limit", config.ImageGenerationDailyLimitPerUser).
+			Int("limit", userLimit).
 			Msg("User image generation limit exceeded")
 		return false, 0, nil
 	}
@@ -123,18 +122,18 @@ func (c *Client) CheckImageGenerationLimit(ctx context.Context, userID, chatID i
 		return false, 0, fmt.Errorf("failed to get chat image generations: %w", err)
 	}
 
-	if chatCount >= config.ImageGenerationDailyLimitPerChat {
+	if chatCount >= chatLimit {
 		c.logger.Info().
 			Int64("chat_id", chatID).
 			Int("count", chatCount).
-			Int("limit", config.ImageGenerationDailyLimitPerChat).
+			Int("limit", chatLimit).
 			Msg("Chat image generation limit exceeded")
 		return false, 0, nil
 	}
 
 	// Calculate remaining (minimum of user and chat remaining)
-	userRemaining := config.ImageGenerationDailyLimitPerUser - userCount
-	chatRemaining := config.ImageGenerationDailyLimitPerChat - chatCount
+	userRemaining := userLimit - userCount
+	chatRemaining := chatLimit - chatCount
 	remaining = userRemaining
 	if chatRemaining < userRemaining {
 		remaining = chatRemaining
@@ -151,8 +150,8 @@ func (c *Client) CheckImageGenerationLimit(ctx context.Context, userID, chatID i
 	return true, remaining, nil
 }
 
-// RecordImageGeneration records an image generation for both user and chat statistics
-func (c *Client) RecordImageGeneration(ctx context.Context, userID, chatID int64, date string) error {
+// RecordImageGeneration records generated images for both user and chat statistics
+func (c *Client) RecordImageGeneration(ctx context.Context, userID, chatID int64, date string, count int) error {
 	ctx, cancel := context.WithTimeout(ctx, c.timeout)
 	defer cancel()
 
@@ -163,6 +162,7 @@ func (c *Client) RecordImageGeneration(ctx context.Context, userID, chatID int64
 			"p_user_id": userID,
 			"p_chat_id": chatID,
 			"p_date":    date,
+			"p_count":   count,
 		}
 
 		result := c.client.Rpc("record_image_generation", "", params)
@@ -179,6 +179,7 @@ func (c *Client) RecordImageGeneration(ctx context.Context, userID, chatID int64
 			Int64("user_id", userID).
 			Int64("chat_id", chatID).
 			Str("date", date).
+			Int("count", count).
 			Msg("Failed to record image generation")
 		return err
 	}
@@ -187,6 +188,7 @@ func (c *Client) RecordImageGeneration(ctx context.Context, userID, chatID int64
 		Int64("user_id", userID).
 		Int64("chat_id", chatID).
 		Str("date", date).
+		Int("count", count).
 		Msg("Image generation recorded successfully")
 
 	return nil
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+// SaveImageGenerations stores generated images with their parameters, so they can be reproduced
+func (c *Client) SaveImageGenerations(ctx context.Context, generations []*models.ImageGeneration) error {
+	if len(generations) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	now := time.Now().UTC()
+	for _, generation := range generations {
+		if generation.CreatedAt.IsZero() {
+			generation.CreatedAt = now
+		}
+	}
+
+	err := c.withRetry(ctx, "save_image_generations", func() error {
+		data := make([]map[string]interface{}, 0, len(generations))
+		for _, generation := range generations {
+			data = append(data, map[string]interface{}{
+				"chat_id":         generation.ChatID,
+				"user_id":         generation.UserID,
+				"message_id":      generation.MessageID,
+				"file_id":         nullIfEmpty(generation.FileID),
+				"prompt":          generation.Prompt,
+				"enhanced_prompt": nullIfEmpty(generation.EnhancedPrompt),
+				"negative_prompt": nullIfEmpty(generation.NegativePrompt),
+				"aspect_ratio":    nullIfEmpty(generation.AspectRatio),
+				"model":           generation.Model,
+				"seed":            generation.Seed,
+				"source_file_id":  nullIfEmpty(generation.SourceFileID),
+				"created_at":      generation.CreatedAt,
+			})
+		}
+
+		_, _, err := c.client.From("image_generations").
+			Insert(data, false, "", "", "").
+			Execute()
+
+		if err != nil {
+			return fmt.Errorf("failed to insert image generations: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		c.logger.Error().
+			Err(err).
+			Int64("chat_id", generations[0].ChatID).
+			Int("count", len(generations)).
+			Msg("Failed to save image generations")
+		return err
+	}
+
+	c.logger.Debug().
+		Int64("chat_id", generations[0].ChatID).
+		Int("count", len(generations)).
+		Msg("Image generations saved")
+
+	return nil
+}
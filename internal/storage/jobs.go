@@ -0,0 +1,226 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+// EnqueueJob adds a job to the durable queue and returns its ID
+// Jobs are deduplicated by chat, message and kind, so retrying the call never queues a request twice
+func (c *Client) EnqueueJob(ctx context.Context, kind string, chatID int64, messageID int, userID int64, payload interface{}, maxAttempts int) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var jobID int64
+
+	err := c.withRetry(ctx, "enqueue_bot_job", func() error {
+		data := c.client.Rpc("enqueue_bot_job", "", map[string]interface{}{
+			"p_kind":         kind,
+			"p_chat_id":      chatID,
+			"p_message_id":   messageID,
+			"p_user_id":      userID,
+			"p_payload":      payload,
+			"p_max_attempts": maxAttempts,
+		})
+
+		if data == "" {
+			return fmt.Errorf("failed to enqueue job: RPC returned empty")
+		}
+
+		id, err := strconv.ParseInt(strings.TrimSpace(data), 10, 64)
+		if err != nil {
+			return fmt.Errorf("failed to parse job ID: %w", err)
+		}
+
+		jobID = id
+		return nil
+	})
+
+	if err != nil {
+		c.logger.Error().
+			Err(err).
+			Str("kind", kind).
+			Int64("chat_id", chatID).
+			Int("message_id", messageID).
+			Int64("user_id", userID).
+			Msg("Failed to enqueue job")
+		return 0, err
+	}
+
+	c.logger.Debug().
+		Int64("job_id", jobID).
+		Str("kind", kind).
+		Int64("chat_id", chatID).
+		Msg("Job enqueued")
+
+	return jobID, nil
+}
+
+// ClaimJobs leases up to limit due jobs to the worker for the lease duration
+func (c *Client) ClaimJobs(ctx context.Context, owner string, limit int, lease time.Duration) ([]models.Job, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var jobs []models.Job
+
+	err := c.withRetry(ctx, "claim_bot_jobs", func() error {
+		data := c.client.Rpc("claim_bot_jobs", "", map[string]interface{}{
+			"p_owner":         owner,
+			"p_limit":         limit,
+			"p_lease_seconds": int(lease.Seconds()),
+		})
+
+		if data == "" {
+			return fmt.Errorf("failed to claim jobs: RPC returned empty")
+		}
+
+		if err := json.Unmarshal([]byte(data), &jobs); err != nil {
+			return fmt.Errorf("failed to parse claimed jobs: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		c.logger.Error().
+			Err(err).
+			Str("owner", owner).
+			Msg("Failed to claim jobs")
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// ExtendJobLease extends the lease of a running job
+// Returns false if the lease was lost to another worker
+func (c *Client) ExtendJobLease(ctx context.Context, jobID int64, owner string, lease time.Duration) (bool, error) {
+	return c.callJobRPC(ctx, "extend_bot_job_lease", jobID, map[string]interface{}{
+		"p_id":            jobID,
+		"p_owner":         owner,
+		"p_lease_seconds": int(lease.Seconds()),
+	})
+}
+
+// UpdateJobPayload replaces the payload of a running job, so later attempts reuse results of earlier ones
+func (c *Client) UpdateJobPayload(ctx context.Context, jobID int64, owner string, payload interface{}) (bool, error) {
+	return c.callJobRPC(ctx, "update_bot_job_payload", jobID, map[string]interface{}{
+		"p_id":      jobID,
+		"p_owner":   owner,
+		"p_payload": payload,
+	})
+}
+
+// CompleteJob marks a running job as done
+func (c *Client) CompleteJob(ctx context.Context, jobID int64, owner string) (bool, error) {
+	return c.callJobRPC(ctx, "complete_bot_job", jobID, map[string]interface{}{
+		"p_id":    jobID,
+		"p_owner": owner,
+	})
+}
+
+// RetryJob records a failed attempt and requeues the job after the delay
+func (c *Client) RetryJob(ctx context.Context, jobID int64, owner, errMsg string, delay time.Duration) (bool, error) {
+	return c.callJobRPC(ctx, "fail_bot_job", jobID, map[string]interface{}{
+		"p_id":                  jobID,
+		"p_owner":               owner,
+		"p_error":               errMsg,
+		"p_retry_delay_seconds": int(delay.Seconds()),
+	})
+}
+
+// FailJob marks a running job as permanently failed
+func (c *Client) FailJob(ctx context.Context, jobID int64, owner, errMsg string) (bool, error) {
+	return c.callJobRPC(ctx, "fail_bot_job", jobID, map[string]interface{}{
+		"p_id":    jobID,
+		"p_owner": owner,
+		"p_error": errMsg,
+	})
+}
+
+// ReleaseJob returns an interrupted job to the queue without counting the attempt
+func (c *Client) ReleaseJob(ctx context.Context, jobID int64, owner string) (bool, error) {
+	return c.callJobRPC(ctx, "release_bot_job", jobID, map[string]interface{}{
+		"p_id":    jobID,
+		"p_owner": owner,
+	})
+}
+
+// CleanupJobs deletes finished jobs older than keepDays and returns the number of deleted jobs
+func (c *Client) CleanupJobs(ctx context.Context, keepDays int) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var deleted int
+
+	err := c.withRetry(ctx, "cleanup_bot_jobs", func() error {
+		data := c.client.Rpc("cleanup_bot_jobs", "", map[string]interface{}{
+			"p_keep_days": keepDays,
+		})
+
+		if data == "" {
+			return fmt.Errorf("failed to clean up jobs: RPC returned empty")
+		}
+
+		n, err := strconv.Atoi(strings.TrimSpace(data))
+		if err != nil {
+			return fmt.Errorf("failed to parse deleted jobs count: %w", err)
+		}
+
+		deleted = n
+		return nil
+	})
+
+	if err != nil {
+		c.logger.Error().
+			Err(err).
+			Int("keep_days", keepDays).
+			Msg("Failed to clean up jobs")
+		return 0, err
+	}
+
+	c.logger.Debug().
+		Int("deleted", deleted).
+		Int("keep_days", keepDays).
+		Msg("Finished jobs cleaned up")
+
+	return deleted, nil
+}
+
+// callJobRPC calls a job state transition function returning whether the worker still owned the job
+func (c *Client) callJobRPC(ctx context.Context, function string, jobID int64, params map[string]interface{}) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var owned bool
+
+	err := c.withRetry(ctx, function, func() error {
+		data := c.client.Rpc(function, "", params)
+		if data == "" {
+			return fmt.Errorf("failed to call %s: RPC returned empty", function)
+		}
+
+		if err := json.Unmarshal([]byte(data), &owned); err != nil {
+			return fmt.Errorf("failed to parse %s result: %w", function, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		c.logger.Error().
+			Err(err).
+			Str("function", function).
+			Int64("job_id", jobID).
+			Msg("Failed to update job state")
+		return false, err
+	}
+
+	return owned, nil
+}
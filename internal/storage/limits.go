@@ -42,6 +42,7 @@ func (c *Client) GetDailyLimit(ctx context.Context, userID int64, date string) (
 	var results []struct {
 		ProCount   int `json:"pro_count"`
 		FlashCount int `json:"flash_count"`
+		TokensUsed int `json:"tokens_used"`
 	}
 
 	if err := json.Unmarshal([]byte(data), &results); err != nil {
@@ -60,9 +61,11 @@ func (c *Client) GetDailyLimit(ctx context.Context, userID int64, date string) (
 
 	proCount := 0
 	flashCount := 0
+	tokensUsed := 0
 	if len(results) > 0 {
 		proCount = results[0].ProCount
 		flashCount = results[0].FlashCount
+		tokensUsed = results[0].TokensUsed
 	}
 
 	c.logger.Debug().
@@ -70,6 +73,7 @@ func (c *Client) GetDailyLimit(ctx context.Context, userID int64, date string) (
 		Str("date", date).
 		Int("pro_count", proCount).
 		Int("flash_count", flashCount).
+		Int("tokens_used", tokensUsed).
 		Msg("Retrieved daily limit")
 
 	return &models.DailyLimit{
@@ -77,6 +81,7 @@ func (c *Client) GetDailyLimit(ctx context.Context, userID int64, date string) (
 		Date:               date,
 		ProRequestsCount:   proCount,
 		FlashRequestsCount: flashCount,
+		TokensUsed:         tokensUsed,
 		UpdatedAt:          time.Now().UTC(),
 	}, nil
 }
@@ -128,6 +133,49 @@ func (c *Client) IncrementLimit(ctx context.Context, userID int64, date string,
 	return nil
 }
 
+// AddDailyTokens adds consumed tokens to the user's daily token counter
+func (c *Client) AddDailyTokens(ctx context.Context, userID int64, date string, tokens int) error {
+	if tokens <= 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	err := c.withRetry(ctx, "add_daily_tokens", func() error {
+		params := map[string]interface{}{
+			"p_user_id": userID,
+			"p_date":    date,
+			"p_tokens":  tokens,
+		}
+
+		result := c.client.Rpc("add_daily_tokens", "", params)
+		if result == "" {
+			return fmt.Errorf("failed to add daily tokens: RPC returned empty")
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		c.logger.Error().
+			Err(err).
+			Int64("user_id", userID).
+			Str("date", date).
+			Int("tokens", tokens).
+			Msg("Failed to add daily tokens")
+		return err
+	}
+
+	c.logger.Debug().
+		Int64("user_id", userID).
+		Str("date", date).
+		Int("tokens", tokens).
+		Msg("Daily tokens added")
+
+	return nil
+}
+
 // isNotFoundError checks if error is a "not found" error
 func isNotFoundError(err error) bool {
 	if err == nil {
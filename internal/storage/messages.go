@@ -6,19 +6,15 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/supabase/postgrest-go"
 	"github.com/telegram-llm-bot/internal/models"
 )
 
-// GetMessagesForDate retrieves all messages for a specific date in Moscow timezone
-func (c *Client) GetMessagesForDate(ctx context.Context, chatID int64, date string) ([]models.ChatMessage, error) {
+// GetMessagesForDate retrieves all messages for a specific date, with day boundaries in the given timezone
+func (c *Client) GetMessagesForDate(ctx context.Context, chatID int64, date string, loc *time.Location) ([]models.ChatMessage, error) {
 	ctx, cancel := context.WithTimeout(ctx, c.timeout)
 	defer cancel()
 
-	loc, err := time.LoadLocation("Europe/Moscow")
-	if err != nil {
-		return nil, fmt.Errorf("failed to load timezone: %w", err)
-	}
-
 	startTime, err := time.ParseInLocation("2006-01-02", date, loc)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse date: %w", err)
@@ -33,7 +29,7 @@ func (c *Client) GetMessagesForDate(ctx context.Context, chatID int64, date stri
 
 	err = c.withRetry(ctx, operation, func() error {
 		data, _, err := c.client.From("chat_messages").
-			Select("id,message_id,user_id,username,first_name,chat_id,message_text,indexed,created_at,indexed_at", "exact", false).
+			Select("id,message_id,user_id,username,first_name,chat_id,message_text,reply_to_message_id,indexed,created_at,indexed_at", "exact", false).
 			Eq("chat_id", fmt.Sprintf("%d", chatID)).
 			Gte("created_at", startUTC.Format(time.RFC3339)).
 			Lt("created_at", endUTC.Format(time.RFC3339)).
@@ -72,20 +68,102 @@ func (c *Client) GetMessagesForDate(ctx context.Context, chatID int64, date stri
 		Int64("chat_id", chatID).
 		Str("date", date).
 		Int("retrieved_count", len(messages)).
+		Str("timezone", loc.String()).
 		Int("filtered_count", len(filtered)).
-		Msg("Filtered messages for date in Moscow timezone")
+		Msg("Filtered messages for date in chat timezone")
 
 	return filtered, nil
 }
 
+// GetMessagesInRange retrieves messages of a chat created in [since, until) in chronological order
+func (c *Client) GetMessagesInRange(ctx context.Context, chatID int64, since, until time.Time) ([]models.ChatMessage, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var messages []models.ChatMessage
+	operation := "get_messages_in_range"
+
+	err := c.withRetry(ctx, operation, func() error {
+		data, _, err := c.client.From("chat_messages").
+			Select("id,message_id,user_id,username,first_name,chat_id,message_text,reply_to_message_id,indexed,created_at,indexed_at", "exact", false).
+			Eq("chat_id", fmt.Sprintf("%d", chatID)).
+			Gte("created_at", since.UTC().Format(time.RFC3339)).
+			Lt("created_at", until.UTC().Format(time.RFC3339)).
+			Order("created_at", nil).
+			Execute()
+
+		if err != nil {
+			return fmt.Errorf("failed to fetch messages: %w", err)
+		}
+
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return fmt.Errorf("failed to unmarshal messages: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		c.logger.Error().
+			Err(err).
+			Int64("chat_id", chatID).
+			Time("since", since).
+			Time("until", until).
+			Msg("Failed to get messages in range")
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// GetLastUserMessage retrieves the last message of a user in a chat created before the given time
+// Returns nil if the user has no such messages
+func (c *Client) GetLastUserMessage(ctx context.Context, chatID, userID int64, before time.Time) (*models.ChatMessage, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var messages []models.ChatMessage
+	operation := "get_last_user_message"
+
+	err := c.withRetry(ctx, operation, func() error {
+		data, _, err := c.client.From("chat_messages").
+			Select("id,message_id,user_id,username,first_name,chat_id,message_text,reply_to_message_id,indexed,created_at,indexed_at", "exact", false).
+			Eq("chat_id", fmt.Sprintf("%d", chatID)).
+			Eq("user_id", fmt.Sprintf("%d", userID)).
+			Lt("created_at", before.UTC().Format(time.RFC3339)).
+			Order("created_at", &postgrest.OrderOpts{Ascending: false}).
+			Limit(1, "").
+			Execute()
+
+		if err != nil {
+			return fmt.Errorf("failed to fetch last user message: %w", err)
+		}
+
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return fmt.Errorf("failed to unmarshal messages: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(messages) == 0 {
+		return nil, nil
+	}
+	return &messages[0], nil
+}
+
 // GetUserMessageCounts retrieves message counts per user for a specific date
-func (c *Client) GetUserMessageCounts(ctx context.Context, chatID int64, date string) ([]models.UserMessageCount, error) {
+func (c *Client) GetUserMessageCounts(ctx context.Context, chatID int64, date string, loc *time.Location) ([]models.UserMessageCount, error) {
 	ctx, cancel := context.WithTimeout(ctx, c.timeout)
 	defer cancel()
 
 	// Note: Supabase Go client doesn't support GROUP BY directly
 	// We'll fetch all messages and count them in Go
-	messages, err := c.GetMessagesForDate(ctx, chatID, date)
+	messages, err := c.GetMessagesForDate(ctx, chatID, date, loc)
 	if err != nil {
 		return nil, err
 	}
@@ -121,8 +199,8 @@ func (c *Client) GetUserMessageCounts(ctx context.Context, chatID int64, date st
 }
 
 // GetMostActiveUser finds the user with the most messages for a specific date
-func (c *Client) GetMostActiveUser(ctx context.Context, chatID int64, date string) (*models.UserMessageCount, error) {
-	counts, err := c.GetUserMessageCounts(ctx, chatID, date)
+func (c *Client) GetMostActiveUser(ctx context.Context, chatID int64, date string, loc *time.Location) (*models.UserMessageCount, error) {
+	counts, err := c.GetUserMessageCounts(ctx, chatID, date, loc)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,236 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/supabase/postgrest-go"
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+// GetEffectiveQuotaTier resolves the quota tier that applies to a user in a chat
+// Resolution order: user in chat > user in all chats > chat default > built-in default
+func (c *Client) GetEffectiveQuotaTier(ctx context.Context, userID, chatID int64) (*models.QuotaTier, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var results []struct {
+		TierName        string `json:"tier_name"`
+		Source          string `json:"source"`
+		Description     string `json:"description"`
+		ProDailyLimit   *int   `json:"pro_daily_limit"`
+		FlashDailyLimit *int   `json:"flash_daily_limit"`
+		ImageDailyLimit *int   `json:"image_daily_limit"`
+		TokenBudget     *int   `json:"daily_token_budget"`
+	}
+
+	err := c.withRetry(ctx, "get_effective_quota_tier", func() error {
+		data := c.client.Rpc("get_effective_quota_tier", "", map[string]interface{}{
+			"p_user_id": userID,
+			"p_chat_id": chatID,
+		})
+
+		if data == "" {
+			return fmt.Errorf("failed to resolve quota tier: RPC returned empty")
+		}
+
+		if err := json.Unmarshal([]byte(data), &results); err != nil {
+			return fmt.Errorf("failed to parse quota tier: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(results) == 0 {
+		return &models.QuotaTier{Name: models.TierDefault}, models.QuotaSourceDefault, nil
+	}
+
+	tier := &models.QuotaTier{
+		Name:             results[0].TierName,
+		Description:      results[0].Description,
+		ProDailyLimit:    results[0].ProDailyLimit,
+		FlashDailyLimit:  results[0].FlashDailyLimit,
+		ImageDailyLimit:  results[0].ImageDailyLimit,
+		DailyTokenBudget: results[0].TokenBudget,
+	}
+
+	c.logger.Debug().
+		Int64("user_id", userID).
+		Int64("chat_id", chatID).
+		Str("tier", tier.Name).
+		Str("source", results[0].Source).
+		Msg("Resolved effective quota tier")
+
+	return tier, results[0].Source, nil
+}
+
+// ListQuotaTiers returns all configured quota tiers
+func (c *Client) ListQuotaTiers(ctx context.Context) ([]models.QuotaTier, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var tiers []models.QuotaTier
+
+	err := c.withRetry(ctx, "list_quota_tiers", func() error {
+		data, _, err := c.client.From("quota_tiers").
+			Select("name,description,pro_daily_limit,flash_daily_limit,image_daily_limit,daily_token_budget", "exact", false).
+			Order("name", nil).
+			Execute()
+
+		if err != nil {
+			return fmt.Errorf("failed to fetch quota tiers: %w", err)
+		}
+
+		if err := json.Unmarshal(data, &tiers); err != nil {
+			return fmt.Errorf("failed to unmarshal quota tiers: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tiers, nil
+}
+
+// SetQuotaAssignment assigns a tier to a user and/or chat (upsert)
+func (c *Client) SetQuotaAssignment(ctx context.Context, assignment *models.QuotaAssignment) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	if assignment.UpdatedAt.IsZero() {
+		assignment.UpdatedAt = time.Now().UTC()
+	}
+
+	err := c.withRetry(ctx, "set_quota_assignment", func() error {
+		data := map[string]interface{}{
+			"chat_id":     assignment.ChatID,
+			"user_id":     assignment.UserID,
+			"tier":        assignment.Tier,
+			"assigned_by": assignment.AssignedBy,
+			"updated_at":  assignment.UpdatedAt,
+		}
+
+		_, _, err := c.client.From("quota_assignments").
+			Insert(data, true, "chat_id,user_id", "", "").
+			Execute()
+
+		if err != nil {
+			return fmt.Errorf("failed to upsert quota assignment: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		c.logger.Error().
+			Err(err).
+			Int64("chat_id", assignment.ChatID).
+			Int64("user_id", assignment.UserID).
+			Str("tier", assignment.Tier).
+			Msg("Failed to set quota assignment")
+		return err
+	}
+
+	c.logger.Info().
+		Int64("chat_id", assignment.ChatID).
+		Int64("user_id", assignment.UserID).
+		Str("tier", assignment.Tier).
+		Int64("assigned_by", assignment.AssignedBy).
+		Msg("Quota assignment saved")
+
+	return nil
+}
+
+// DeleteQuotaAssignment removes a tier assignment so resolution falls back to the next level
+func (c *Client) DeleteQuotaAssignment(ctx context.Context, chatID, userID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	err := c.withRetry(ctx, "delete_quota_assignment", func() error {
+		_, _, err := c.client.From("quota_assignments").
+			Delete("", "").
+			Eq("chat_id", fmt.Sprintf("%d", chatID)).
+			Eq("user_id", fmt.Sprintf("%d", userID)).
+			Execute()
+
+		if err != nil {
+			return fmt.Errorf("failed to delete quota assignment: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		c.logger.Error().
+			Err(err).
+			Int64("chat_id", chatID).
+			Int64("user_id", userID).
+			Msg("Failed to delete quota assignment")
+		return err
+	}
+
+	c.logger.Info().
+		Int64("chat_id", chatID).
+		Int64("user_id", userID).
+		Msg("Quota assignment deleted")
+
+	return nil
+}
+
+// likeEscaper escapes LIKE wildcards, so patterns match literally; "_" is common in usernames
+// PostgREST reads "*" as "%" too
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`, "*", `\*`)
+
+// FindUserIDByUsername looks up a Telegram user ID by username among saved chat messages
+// The username is matched case-insensitively; if it changed hands, the latest author using it wins.
+// Returns 0 if the user has never written in the chat
+func (c *Client) FindUserIDByUsername(ctx context.Context, chatID int64, username string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	username = strings.TrimPrefix(username, "@")
+
+	var rows []struct {
+		UserID int64 `json:"user_id"`
+	}
+
+	err := c.withRetry(ctx, "find_user_id_by_username", func() error {
+		data, _, err := c.client.From("chat_messages").
+			Select("user_id", "exact", false).
+			Eq("chat_id", fmt.Sprintf("%d", chatID)).
+			Ilike("username", likeEscaper.Replace(username)).
+			Order("created_at", &postgrest.OrderOpts{Ascending: false}).
+			Limit(1, "").
+			Execute()
+
+		if err != nil {
+			return fmt.Errorf("failed to find user by username: %w", err)
+		}
+
+		if err := json.Unmarshal(data, &rows); err != nil {
+			return fmt.Errorf("failed to unmarshal user lookup: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	return rows[0].UserID, nil
+}
@@ -30,6 +30,10 @@ func (c *Client) LogRequest(ctx context.Context, log *models.RequestLog) error {
 			"model_used":        log.ModelUsed,
 			"response_length":   log.ResponseLength,
 			"execution_time_ms": log.ExecutionTimeMs,
+			"prompt_tokens":     log.PromptTokens,
+			"candidates_tokens": log.CandidatesTokens,
+			"total_tokens":      log.TotalTokens,
+			"cost_usd":          log.CostUSD,
 			"error_message":     log.ErrorMessage,
 			"created_at":        log.CreatedAt,
 		}
@@ -60,6 +64,7 @@ func (c *Client) LogRequest(ctx context.Context, log *models.RequestLog) error {
 		Str("model", log.ModelUsed).
 		Int("response_len", log.ResponseLength).
 		Int("exec_time_ms", log.ExecutionTimeMs).
+		Int("total_tokens", log.TotalTokens).
 		Msg("Request logged successfully")
 
 	return nil
@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+// GetChatSchedules returns per-chat schedules of all chats
+func (c *Client) GetChatSchedules(ctx context.Context) ([]models.ChatSchedule, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var schedules []models.ChatSchedule
+
+	err := c.withRetry(ctx, "get_chat_schedules", func() error {
+		data, _, err := c.client.From("chat_schedules").
+			Select("chat_id,job,cron_expr,enabled,updated_by,updated_at", "exact", false).
+			Execute()
+
+		if err != nil {
+			return fmt.Errorf("failed to fetch chat schedules: %w", err)
+		}
+
+		if err := json.Unmarshal(data, &schedules); err != nil {
+			return fmt.Errorf("failed to unmarshal chat schedules: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		c.logger.Error().Err(err).Msg("Failed to get chat schedules")
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+// SetChatSchedule saves the schedule of a job in a chat (upsert)
+func (c *Client) SetChatSchedule(ctx context.Context, schedule *models.ChatSchedule) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	if schedule.UpdatedAt.IsZero() {
+		schedule.UpdatedAt = time.Now().UTC()
+	}
+
+	err := c.withRetry(ctx, "set_chat_schedule", func() error {
+		data := map[string]interface{}{
+			"chat_id":    schedule.ChatID,
+			"job":        schedule.Job,
+			"cron_expr":  schedule.CronExpr,
+			"enabled":    schedule.Enabled,
+			"updated_by": schedule.UpdatedBy,
+			"updated_at": schedule.UpdatedAt,
+		}
+
+		_, _, err := c.client.From("chat_schedules").
+			Insert(data, true, "chat_id,job", "", "").
+			Execute()
+
+		if err != nil {
+			return fmt.Errorf("failed to upsert chat schedule: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		c.logger.Error().
+			Err(err).
+			Int64("chat_id", schedule.ChatID).
+			Str("job", schedule.Job).
+			Msg("Failed to set chat schedule")
+		return err
+	}
+
+	c.logger.Info().
+		Int64("chat_id", schedule.ChatID).
+		Str("job", schedule.Job).
+		Str("cron_expr", schedule.CronExpr).
+		Bool("enabled", schedule.Enabled).
+		Msg("Chat schedule saved")
+
+	return nil
+}
+
+// DeleteChatSchedule removes the schedule of a job in a chat so the global default applies
+func (c *Client) DeleteChatSchedule(ctx context.Context, chatID int64, job string) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	err := c.withRetry(ctx, "delete_chat_schedule", func() error {
+		_, _, err := c.client.From("chat_schedules").
+			Delete("", "").
+			Eq("chat_id", fmt.Sprintf("%d", chatID)).
+			Eq("job", job).
+			Execute()
+
+		if err != nil {
+			return fmt.Errorf("failed to delete chat schedule: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		c.logger.Error().
+			Err(err).
+			Int64("chat_id", chatID).
+			Str("job", job).
+			Msg("Failed to delete chat schedule")
+		return err
+	}
+
+	c.logger.Info().
+		Int64("chat_id", chatID).
+		Str("job", job).
+		Msg("Chat schedule reset to default")
+
+	return nil
+}
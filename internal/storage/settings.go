@@ -0,0 +1,204 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+// GetChatSettings retrieves settings of a chat, nil if the chat has none
+func (c *Client) GetChatSettings(ctx context.Context, chatID int64) (*models.ChatSettings, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var settings []models.ChatSettings
+
+	err := c.withRetry(ctx, "get_chat_settings", func() error {
+		data, _, err := c.client.From("chat_settings").
+			Select("*", "exact", false).
+			Eq("chat_id", fmt.Sprintf("%d", chatID)).
+			Execute()
+
+		if err != nil {
+			return fmt.Errorf("failed to fetch chat settings: %w", err)
+		}
+
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return fmt.Errorf("failed to unmarshal chat settings: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		c.logger.Error().Err(err).Int64("chat_id", chatID).Msg("Failed to get chat settings")
+		return nil, err
+	}
+
+	if len(settings) == 0 {
+		return nil, nil
+	}
+
+	return &settings[0], nil
+}
+
+// SetChatTimezone saves the timezone of a chat, an empty timezone resets it to the global one
+func (c *Client) SetChatTimezone(ctx context.Context, chatID int64, timezone string, updatedBy int64) error {
+	return c.upsertSettings(ctx, "chat_settings", "chat_id", map[string]interface{}{
+		"chat_id":    chatID,
+		"timezone":   nullIfEmpty(timezone),
+		"updated_by": updatedBy,
+		"updated_at": time.Now().UTC(),
+	})
+}
+
+// SetChatLocale saves the language of a chat, an empty locale resets it to the global one
+func (c *Client) SetChatLocale(ctx context.Context, chatID int64, locale string, updatedBy int64) error {
+	return c.upsertSettings(ctx, "chat_settings", "chat_id", map[string]interface{}{
+		"chat_id":    chatID,
+		"locale":     nullIfEmpty(locale),
+		"updated_by": updatedBy,
+		"updated_at": time.Now().UTC(),
+	})
+}
+
+// SetChatSummaryReport saves the daily summary report sections of a chat, an empty report resets them
+func (c *Client) SetChatSummaryReport(ctx context.Context, chatID int64, report models.SummaryReport, updatedBy int64) error {
+	var value interface{}
+	if len(report) > 0 {
+		value = report
+	}
+
+	return c.upsertSettings(ctx, "chat_settings", "chat_id", map[string]interface{}{
+		"chat_id":        chatID,
+		"summary_report": value,
+		"updated_by":     updatedBy,
+		"updated_at":     time.Now().UTC(),
+	})
+}
+
+// SetChatSummaryTemplate saves the daily summary template of a chat, an empty template resets it to the default one
+func (c *Client) SetChatSummaryTemplate(ctx context.Context, chatID int64, template string, updatedBy int64) error {
+	return c.upsertSettings(ctx, "chat_settings", "chat_id", map[string]interface{}{
+		"chat_id":          chatID,
+		"summary_template": nullIfEmpty(template),
+		"updated_by":       updatedBy,
+		"updated_at":       time.Now().UTC(),
+	})
+}
+
+// SetChatSummaryDelivery saves the forum topic and pinning of summaries of a chat
+func (c *Client) SetChatSummaryDelivery(ctx context.Context, chatID, threadID int64, pin bool, updatedBy int64) error {
+	var thread interface{}
+	if threadID != 0 {
+		thread = threadID
+	}
+
+	return c.upsertSettings(ctx, "chat_settings", "chat_id", map[string]interface{}{
+		"chat_id":           chatID,
+		"summary_thread_id": thread,
+		"summary_pin":       pin,
+		"updated_by":        updatedBy,
+		"updated_at":        time.Now().UTC(),
+	})
+}
+
+// GetUserSettings retrieves settings of a user, nil if the user has none
+func (c *Client) GetUserSettings(ctx context.Context, userID int64) (*models.UserSettings, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var settings []models.UserSettings
+
+	err := c.withRetry(ctx, "get_user_settings", func() error {
+		data, _, err := c.client.From("user_settings").
+			Select("*", "exact", false).
+			Eq("user_id", fmt.Sprintf("%d", userID)).
+			Execute()
+
+		if err != nil {
+			return fmt.Errorf("failed to fetch user settings: %w", err)
+		}
+
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return fmt.Errorf("failed to unmarshal user settings: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		c.logger.Error().Err(err).Int64("user_id", userID).Msg("Failed to get user settings")
+		return nil, err
+	}
+
+	if len(settings) == 0 {
+		return nil, nil
+	}
+
+	return &settings[0], nil
+}
+
+// SetUserTimezone saves the timezone of a user, an empty timezone resets it to the chat one
+func (c *Client) SetUserTimezone(ctx context.Context, userID int64, timezone string) error {
+	return c.upsertSettings(ctx, "user_settings", "user_id", map[string]interface{}{
+		"user_id":    userID,
+		"timezone":   nullIfEmpty(timezone),
+		"updated_at": time.Now().UTC(),
+	})
+}
+
+// SetUserLocale saves the language of a user, an empty locale resets it to the detected one
+func (c *Client) SetUserLocale(ctx context.Context, userID int64, locale string) error {
+	return c.upsertSettings(ctx, "user_settings", "user_id", map[string]interface{}{
+		"user_id":    userID,
+		"locale":     nullIfEmpty(locale),
+		"updated_at": time.Now().UTC(),
+	})
+}
+
+// upsertSettings inserts or updates a settings row
+// Only the given columns are written, other settings of the row are kept
+func (c *Client) upsertSettings(ctx context.Context, table, key string, data map[string]interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	err := c.withRetry(ctx, "upsert_"+table, func() error {
+		_, _, err := c.client.From(table).
+			Insert(data, true, key, "", "").
+			Execute()
+
+		if err != nil {
+			return fmt.Errorf("failed to upsert %s: %w", table, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		c.logger.Error().
+			Err(err).
+			Str("table", table).
+			Interface(key, data[key]).
+			Msg("Failed to save settings")
+		return err
+	}
+
+	c.logger.Info().
+		Str("table", table).
+		Interface(key, data[key]).
+		Msg("Settings saved")
+
+	return nil
+}
+
+// nullIfEmpty converts an empty string to a SQL NULL
+func nullIfEmpty(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
@@ -6,9 +6,14 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/supabase/postgrest-go"
 	"github.com/telegram-llm-bot/internal/models"
 )
 
+// summaryColumns are the columns of daily_summaries read into models.DailySummary (without the embedding)
+const summaryColumns = "id,chat_id,date,summary_text,most_active_user_id,most_active_username,message_count," +
+	"summary_data,delivery_status,delivery_attempts,delivery_error,sent_message_id,delivered_at,created_at"
+
 // SaveDailySummary stores a generated daily summary in the database
 // Uses upsert to allow overwriting existing summaries (for force regeneration)
 func (c *Client) SaveDailySummary(ctx context.Context, summary *models.DailySummary) error {
@@ -20,6 +25,13 @@ func (c *Client) SaveDailySummary(ctx context.Context, summary *models.DailySumm
 		summary.CreatedAt = time.Now().UTC()
 	}
 
+	// A new or regenerated summary has not been delivered yet
+	summary.DeliveryStatus = models.DeliveryStatusPending
+	summary.DeliveryAttempts = 0
+	summary.DeliveryError = ""
+	summary.SentMessageID = 0
+	summary.DeliveredAt = nil
+
 	operation := "save_daily_summary"
 	err := c.withRetry(ctx, operation, func() error {
 		data := map[string]interface{}{
@@ -29,6 +41,13 @@ func (c *Client) SaveDailySummary(ctx context.Context, summary *models.DailySumm
 			"most_active_user_id":  summary.MostActiveUserID,
 			"most_active_username": summary.MostActiveUsername,
 			"message_count":        summary.MessageCount,
+			"summary_data":         summary.Data,
+			"delivery_status":      summary.DeliveryStatus,
+			"delivery_attempts":    0,
+			"delivery_error":       nil,
+			"sent_message_id":      nil,
+			"delivered_at":         nil,
+			"embedding":            nil, // Regenerated summaries are indexed again
 			"created_at":           summary.CreatedAt,
 		}
 
@@ -119,7 +138,7 @@ func (c *Client) GetDailySummary(ctx context.Context, chatID int64, date string)
 
 	err := c.withRetry(ctx, operation, func() error {
 		data, _, err := c.client.From("daily_summaries").
-			Select("*", "exact", false).
+			Select(summaryColumns, "exact", false).
 			Eq("chat_id", fmt.Sprintf("%d", chatID)).
 			Eq("date", date).
 			Limit(1, "").
@@ -156,3 +175,170 @@ func (c *Client) GetDailySummary(ctx context.Context, chatID int64, date string)
 
 	return &summaries[0], nil
 }
+
+// GetDailySummaries retrieves daily summaries between start and end dates (inclusive) in date order
+func (c *Client) GetDailySummaries(ctx context.Context, chatID int64, start, end string) ([]models.DailySummary, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var summaries []models.DailySummary
+	operation := "get_daily_summaries"
+
+	err := c.withRetry(ctx, operation, func() error {
+		data, _, err := c.client.From("daily_summaries").
+			Select(summaryColumns, "exact", false).
+			Eq("chat_id", fmt.Sprintf("%d", chatID)).
+			Gte("date", start).
+			Lte("date", end).
+			Order("date", nil).
+			Execute()
+
+		if err != nil {
+			return fmt.Errorf("failed to fetch daily summaries: %w", err)
+		}
+
+		if err := json.Unmarshal(data, &summaries); err != nil {
+			return fmt.Errorf("failed to unmarshal summaries: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		c.logger.Error().
+			Err(err).
+			Int64("chat_id", chatID).
+			Str("start", start).
+			Str("end", end).
+			Msg("Failed to get daily summaries")
+		return nil, err
+	}
+
+	c.logger.Debug().
+		Int64("chat_id", chatID).
+		Str("start", start).
+		Str("end", end).
+		Int("count", len(summaries)).
+		Msg("Retrieved daily summaries")
+
+	return summaries, nil
+}
+
+// UpdateSummaryDelivery stores the delivery status, attempts and sent message of a daily summary
+func (c *Client) UpdateSummaryDelivery(ctx context.Context, summary *models.DailySummary) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	data := map[string]interface{}{
+		"delivery_status":   summary.DeliveryStatus,
+		"delivery_attempts": summary.DeliveryAttempts,
+		"delivery_error":    nullIfEmpty(summary.DeliveryError),
+		"sent_message_id":   nil,
+		"delivered_at":      summary.DeliveredAt,
+	}
+	if summary.SentMessageID != 0 {
+		data["sent_message_id"] = summary.SentMessageID
+	}
+
+	err := c.withRetry(ctx, "update_summary_delivery", func() error {
+		_, _, err := c.client.From("daily_summaries").
+			Update(data, "", "").
+			Eq("chat_id", fmt.Sprintf("%d", summary.ChatID)).
+			Eq("date", summary.Date).
+			Execute()
+
+		if err != nil {
+			return fmt.Errorf("failed to update summary delivery: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		c.logger.Error().
+			Err(err).
+			Int64("chat_id", summary.ChatID).
+			Str("date", summary.Date).
+			Str("status", summary.DeliveryStatus).
+			Msg("Failed to update summary delivery")
+		return err
+	}
+
+	return nil
+}
+
+// GetUndeliveredSummaries retrieves summaries created in [since, until) that were not delivered
+// and have fewer than maxAttempts delivery attempts, oldest first
+func (c *Client) GetUndeliveredSummaries(ctx context.Context, since, until time.Time, maxAttempts int) ([]models.DailySummary, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var summaries []models.DailySummary
+
+	err := c.withRetry(ctx, "get_undelivered_summaries", func() error {
+		data, _, err := c.client.From("daily_summaries").
+			Select(summaryColumns, "exact", false).
+			Neq("delivery_status", models.DeliveryStatusSent).
+			Lt("delivery_attempts", fmt.Sprintf("%d", maxAttempts)).
+			Gte("created_at", since.UTC().Format(time.RFC3339)).
+			Lt("created_at", until.UTC().Format(time.RFC3339)).
+			Order("created_at", &postgrest.OrderOpts{Ascending: true}).
+			Execute()
+
+		if err != nil {
+			return fmt.Errorf("failed to fetch undelivered summaries: %w", err)
+		}
+
+		if err := json.Unmarshal(data, &summaries); err != nil {
+			return fmt.Errorf("failed to unmarshal summaries: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		c.logger.Error().Err(err).Msg("Failed to get undelivered summaries")
+		return nil, err
+	}
+
+	return summaries, nil
+}
+
+// GetLastSentSummary retrieves the latest delivered summary of a chat before the date, nil if none
+func (c *Client) GetLastSentSummary(ctx context.Context, chatID int64, before string) (*models.DailySummary, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var summaries []models.DailySummary
+
+	err := c.withRetry(ctx, "get_last_sent_summary", func() error {
+		data, _, err := c.client.From("daily_summaries").
+			Select(summaryColumns, "exact", false).
+			Eq("chat_id", fmt.Sprintf("%d", chatID)).
+			Eq("delivery_status", models.DeliveryStatusSent).
+			Lt("date", before).
+			Order("date", &postgrest.OrderOpts{Ascending: false}).
+			Limit(1, "").
+			Execute()
+
+		if err != nil {
+			return fmt.Errorf("failed to fetch last sent summary: %w", err)
+		}
+
+		if err := json.Unmarshal(data, &summaries); err != nil {
+			return fmt.Errorf("failed to unmarshal summaries: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		c.logger.Error().Err(err).Int64("chat_id", chatID).Msg("Failed to get last sent summary")
+		return nil, err
+	}
+
+	if len(summaries) == 0 {
+		return nil, nil
+	}
+	return &summaries[0], nil
+}
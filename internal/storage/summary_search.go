@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/supabase/postgrest-go"
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+// GetSummaryDates retrieves dates of the stored daily summaries of a chat, newest first
+func (c *Client) GetSummaryDates(ctx context.Context, chatID int64, limit int) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var rows []struct {
+		Date string `json:"date"`
+	}
+
+	err := c.withRetry(ctx, "get_summary_dates", func() error {
+		data, _, err := c.client.From("daily_summaries").
+			Select("date", "", false).
+			Eq("chat_id", fmt.Sprintf("%d", chatID)).
+			Order("date", &postgrest.OrderOpts{Ascending: false}).
+			Limit(limit, "").
+			Execute()
+
+		if err != nil {
+			return fmt.Errorf("failed to fetch summary dates: %w", err)
+		}
+
+		if err := json.Unmarshal(data, &rows); err != nil {
+			return fmt.Errorf("failed to unmarshal summary dates: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		c.logger.Error().
+			Err(err).
+			Int64("chat_id", chatID).
+			Msg("Failed to get summary dates")
+		return nil, err
+	}
+
+	dates := make([]string, len(rows))
+	for i, row := range rows {
+		dates[i] = row.Date
+	}
+
+	return dates, nil
+}
+
+// GetUnindexedSummaries retrieves daily summaries that don't have embeddings yet, oldest first
+func (c *Client) GetUnindexedSummaries(ctx context.Context, limit int) ([]models.DailySummary, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var summaries []models.DailySummary
+
+	err := c.withRetry(ctx, "get_unindexed_summaries", func() error {
+		query := c.client.From("daily_summaries").
+			Select(summaryColumns, "exact", false).
+			Is("embedding", "null").
+			Order("created_at", &postgrest.OrderOpts{Ascending: true})
+
+		if limit > 0 {
+			query = query.Limit(limit, "")
+		}
+
+		data, _, err := query.Execute()
+		if err != nil {
+			return fmt.Errorf("failed to fetch unindexed summaries: %w", err)
+		}
+
+		if err := json.Unmarshal(data, &summaries); err != nil {
+			return fmt.Errorf("failed to unmarshal unindexed summaries: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug().
+		Int("count", len(summaries)).
+		Msg("Retrieved unindexed summaries")
+
+	return summaries, nil
+}
+
+// BatchUpdateSummaryEmbeddings stores embeddings of daily summaries in one operation
+func (c *Client) BatchUpdateSummaryEmbeddings(ctx context.Context, ids []int64, embeddings [][]float32) (int, error) {
+	if len(ids) != len(embeddings) {
+		return 0, fmt.Errorf("ids and embeddings must have same length")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout*2) // Double timeout for batch operation
+	defer cancel()
+
+	var rowsUpdated int
+
+	err := c.withRetry(ctx, "batch_update_summary_embeddings", func() error {
+		data := c.client.Rpc("batch_update_summary_embeddings", "", map[string]interface{}{
+			"p_summary_ids": ids,
+			"p_embeddings":  formatVectors(embeddings),
+		})
+
+		if data == "" {
+			return fmt.Errorf("failed to batch update summary embeddings: RPC returned empty")
+		}
+
+		if err := json.Unmarshal([]byte(data), &rowsUpdated); err != nil {
+			var arrayResult []int
+			if err2 := json.Unmarshal([]byte(data), &arrayResult); err2 != nil || len(arrayResult) == 0 {
+				return fmt.Errorf("failed to parse batch update result: %w", err)
+			}
+			rowsUpdated = arrayResult[0]
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	c.logger.Info().
+		Int("count", rowsUpdated).
+		Int("expected", len(ids)).
+		Msg("Batch update summary embeddings completed")
+
+	return rowsUpdated, nil
+}
+
+// SearchSimilarSummaries searches daily summaries of a chat by vector similarity, most similar first
+func (c *Client) SearchSimilarSummaries(
+	ctx context.Context,
+	queryEmbedding []float32,
+	threshold float64,
+	limit int,
+	chatID int64,
+) ([]models.DailySummary, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var results []models.DailySummary
+
+	err := c.withRetry(ctx, "search_similar_summaries", func() error {
+		data := c.client.Rpc("search_similar_summaries", "", map[string]interface{}{
+			"query_embedding":      queryEmbedding,
+			"similarity_threshold": threshold,
+			"match_count":          limit,
+			"target_chat_id":       chatID,
+		})
+
+		if data == "" {
+			// Empty result is OK - no similar summaries found
+			return nil
+		}
+
+		if err := json.Unmarshal([]byte(data), &results); err != nil {
+			return fmt.Errorf("failed to parse summary search results: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug().
+		Int64("chat_id", chatID).
+		Int("count", len(results)).
+		Float64("threshold", threshold).
+		Msg("Similar summaries found")
+
+	return results, nil
+}
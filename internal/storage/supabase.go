@@ -7,6 +7,8 @@ import (
 
 	"github.com/rs/zerolog"
 	supa "github.com/supabase-community/supabase-go"
+	"github.com/telegram-llm-bot/internal/health"
+	"github.com/telegram-llm-bot/internal/metrics"
 )
 
 // Client represents a Supabase storage client
@@ -49,11 +51,23 @@ func (c *Client) Ping(ctx context.Context) error {
 	return nil
 }
 
+// HealthCheck reports whether Supabase is reachable
+func (c *Client) HealthCheck(ctx context.Context) health.Result {
+	return health.FromError(c.Ping(ctx))
+}
+
 // withRetry executes a function with retry logic
-func (c *Client) withRetry(ctx context.Context, operation string, fn func() error) error {
+func (c *Client) withRetry(ctx context.Context, operation string, fn func() error) (err error) {
 	maxRetries := 2
 	var lastErr error
 
+	startTime := time.Now()
+	defer func() {
+		metrics.StorageOperationDuration.
+			WithLabelValues(operation, metrics.Status(err)).
+			Observe(time.Since(startTime).Seconds())
+	}()
+
 	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
 			// Exponential backoff
@@ -63,6 +77,7 @@ func (c *Client) withRetry(ctx context.Context, operation string, fn func() erro
 				Int("attempt", attempt+1).
 				Dur("backoff", backoff).
 				Msg("Retrying operation")
+			metrics.StorageRetries.WithLabelValues(operation).Inc()
 
 			select {
 			case <-ctx.Done():
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+// RecordTokenUsage stores token usage of a background Gemini call (summaries, embeddings)
+func (c *Client) RecordTokenUsage(ctx context.Context, record *models.UsageRecord) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now().UTC()
+	}
+
+	err := c.withRetry(ctx, "record_token_usage", func() error {
+		data := map[string]interface{}{
+			"component":         record.Component,
+			"model":             record.Model,
+			"chat_id":           record.ChatID,
+			"user_id":           record.UserID,
+			"prompt_tokens":     record.Usage.PromptTokens,
+			"candidates_tokens": record.Usage.CandidatesTokens,
+			"thoughts_tokens":   record.Usage.ThoughtsTokens,
+			"total_tokens":      record.Usage.TotalTokens,
+			"cost_usd":          record.Usage.CostUSD,
+			"estimated":         record.Usage.Estimated,
+			"created_at":        record.CreatedAt,
+		}
+
+		_, _, err := c.client.From("token_usage").
+			Insert(data, false, "", "", "").
+			Execute()
+
+		if err != nil {
+			return fmt.Errorf("failed to insert token usage: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		c.logger.Error().
+			Err(err).
+			Str("component", record.Component).
+			Str("model", record.Model).
+			Msg("Failed to record token usage")
+		return err
+	}
+
+	c.logger.Debug().
+		Str("component", record.Component).
+		Str("model", record.Model).
+		Int("total_tokens", record.Usage.TotalTokens).
+		Float64("cost_usd", record.Usage.CostUSD).
+		Msg("Token usage recorded")
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package summary
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/telegram-llm-bot/internal/i18n"
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+// maxDigestMessageLength truncates long sampled messages to keep the prompt compact
+const maxDigestMessageLength = 300
+
+// GenerateDigest generates a weekly or monthly digest
+// The digest is built hierarchically: daily summaries of the period give its structure,
+// sampled messages add details, and topics of the previous digest are compared to find trends
+func (g *Generator) GenerateDigest(ctx context.Context, input *models.DigestInput, loc *time.Location, tr *i18n.Localizer) (*models.DigestResult, error) {
+	if len(input.DailySummaries) == 0 && len(input.Messages) == 0 {
+		g.logger.Debug().
+			Str("period", input.Period).
+			Str("start", input.Start).
+			Msg("No material for digest")
+		return &models.DigestResult{Topics: []models.SummaryTopic{}}, nil
+	}
+
+	g.logger.Info().
+		Int64("chat_id", input.ChatID).
+		Str("period", input.Period).
+		Str("start", input.Start).
+		Str("end", input.End).
+		Int("daily_summaries", len(input.DailySummaries)).
+		Int("sampled_messages", len(input.Messages)).
+		Int("previous_topics", len(input.PreviousTopics)).
+		Msg("Starting digest generation")
+
+	prompt := g.buildDigestPrompt(input, loc, tr)
+
+	var response digestResponse
+	usage, err := g.generateJSON(ctx, prompt, digestSchema(tr), &response)
+	g.recordUsage(ctx, input.ChatID, usage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate digest: %w", err)
+	}
+
+	result := &models.DigestResult{
+		Topics: sanitizeTopics(response.Topics, input.Messages, maxTopics),
+		Usage:  usage,
+	}
+
+	// Trends make no sense without a previous period
+	if len(input.PreviousTopics) > 0 {
+		result.Rising = sanitizeTopics(response.Rising, input.Messages, maxTrendTopics)
+		result.Fading = sanitizeTopics(response.Fading, input.Messages, maxTrendTopics)
+	}
+
+	g.logger.Info().
+		Int64("chat_id", input.ChatID).
+		Str("period", input.Period).
+		Int("topic_count", len(result.Topics)).
+		Int("rising_count", len(result.Rising)).
+		Int("fading_count", len(result.Fading)).
+		Int("total_tokens", usage.TotalTokens).
+		Msg("Digest generation completed")
+
+	return result, nil
+}
+
+// buildDigestPrompt constructs the digest prompt from daily summaries, sampled messages and previous topics
+func (g *Generator) buildDigestPrompt(input *models.DigestInput, loc *time.Location, tr *i18n.Localizer) string {
+	var sb strings.Builder
+
+	sb.WriteString(tr.T("prompt.digest", tr.T("prompt.digest_period."+input.Period), input.Start, input.End))
+
+	if len(input.DailySummaries) > 0 {
+		sb.WriteString(tr.T("prompt.digest_days"))
+		for _, daily := range input.DailySummaries {
+			sb.WriteString(tr.T("prompt.digest_day", daily.Date, tr.N("unit.messages", daily.MessageCount)))
+			if daily.Data == nil {
+				sb.WriteString(daily.SummaryText + "\n")
+				continue
+			}
+			for _, topic := range daily.Data.Topics {
+				sb.WriteString(fmt.Sprintf("%s: %s\n", topic.String(), topic.Description))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(input.Messages) > 0 {
+		sb.WriteString(tr.T("prompt.digest_sample"))
+		for _, msg := range input.Messages {
+			username := msg.Username
+			if username == "" {
+				username = msg.FirstName
+			}
+			if username == "" {
+				username = fmt.Sprintf("User%d", msg.UserID)
+			}
+
+			text := msg.MessageText
+			if runes := []rune(text); len(runes) > maxDigestMessageLength {
+				text = string(runes[:maxDigestMessageLength]) + "…"
+			}
+
+			sb.WriteString(fmt.Sprintf("[#%d %s] %s: %s\n",
+				msg.MessageID, msg.CreatedAt.In(loc).Format("02.01 15:04"), username, text))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(input.PreviousTopics) > 0 {
+		sb.WriteString(tr.T("prompt.digest_previous"))
+		for _, topic := range input.PreviousTopics {
+			sb.WriteString(topic + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(tr.T("prompt.digest_task"))
+	if len(input.PreviousTopics) > 0 {
+		sb.WriteString(tr.T("prompt.digest_trends"))
+	}
+	sb.WriteString("\n" + tr.T("prompt.topic_rules"))
+	sb.WriteString(tr.T("prompt.digest_ids"))
+	sb.WriteString(tr.T("prompt.language"))
+
+	return sb.String()
+}
@@ -4,20 +4,24 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/generative-ai-go/genai"
 	"github.com/rs/zerolog"
+	"github.com/telegram-llm-bot/internal/i18n"
 	"github.com/telegram-llm-bot/internal/models"
 	"google.golang.org/api/option"
 )
 
 // Generator handles daily summary generation using LLM
 type Generator struct {
-	apiKey      string
-	config      *models.BotConfig
-	logger      zerolog.Logger
-	genaiClient *genai.Client
+	apiKey        string
+	config        *models.BotConfig
+	logger        zerolog.Logger
+	genaiClient   *genai.Client
+	mu            sync.Mutex
+	usageCallback models.UsageCallback
 }
 
 // NewGenerator creates a new summary generator
@@ -29,8 +33,16 @@ func NewGenerator(apiKey string, config *models.BotConfig, logger zerolog.Logger
 	}
 }
 
+// SetUsageCallback sets the callback that receives token usage of summary generation
+func (g *Generator) SetUsageCallback(callback models.UsageCallback) {
+	g.usageCallback = callback
+}
+
 // Close closes the generator and releases resources
 func (g *Generator) Close() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
 	if g.genaiClient != nil {
 		err := g.genaiClient.Close()
 		g.genaiClient = nil
@@ -43,8 +55,11 @@ func (g *Generator) Close() error {
 	return nil
 }
 
-// getClient returns or creates a genai client
+// getClient returns or creates a genai client (thread-safe)
 func (g *Generator) getClient(ctx context.Context) (*genai.Client, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
 	if g.genaiClient != nil {
 		return g.genaiClient, nil
 	}
@@ -59,12 +74,14 @@ func (g *Generator) getClient(ctx context.Context) (*genai.Client, error) {
 	return g.genaiClient, nil
 }
 
-// GenerateSummary generates a daily summary from messages
-func (g *Generator) GenerateSummary(ctx context.Context, messages []models.ChatMessage, date string) (*models.SummaryResult, error) {
+// GenerateSummary generates a summary of a day or another range of messages
+// The date labels the range in prompts (a day or a time range). Message times are interpreted in the chat's timezone loc,
+// prompts and topics are in the language of tr
+func (g *Generator) GenerateSummary(ctx context.Context, messages []models.ChatMessage, date string, loc *time.Location, tr *i18n.Localizer) (*models.SummaryResult, error) {
 	if len(messages) == 0 {
 		g.logger.Debug().Str("date", date).Msg("No messages to summarize")
 		return &models.SummaryResult{
-			Topics:       []string{},
+			Topics:       []models.SummaryTopic{},
 			MessageCount: 0,
 		}, nil
 	}
@@ -74,27 +91,87 @@ func (g *Generator) GenerateSummary(ctx context.Context, messages []models.ChatM
 		Int("message_count", len(messages)).
 		Msg("Starting summary generation")
 
-	// Generate topics using LLM
-	topics, err := g.generateTopics(ctx, messages, date)
+	// Generate topics using LLM, busy days are summarized in chunks
+	var structured *models.StructuredSummary
+	var usage models.TokenUsage
+	var err error
+	if len(messages) > g.config.SummaryChunkSize {
+		structured, usage, err = g.generateTopicsMapReduce(ctx, messages, date, loc, tr)
+	} else {
+		structured, usage, err = g.generateTopics(ctx, messages, date, loc, tr)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate topics: %w", err)
 	}
 
+	g.recordUsage(ctx, messages[0].ChatID, usage)
+
 	result := &models.SummaryResult{
-		Topics:       topics,
+		Topics:       structured.Topics,
+		Quote:        structured.Quote,
 		MessageCount: len(messages),
+		Usage:        usage,
 	}
 
 	g.logger.Info().
 		Str("date", date).
-		Int("topic_count", len(topics)).
+		Int("topic_count", len(result.Topics)).
+		Bool("quote", result.Quote != nil).
+		Int("total_tokens", usage.TotalTokens).
 		Msg("Summary generation completed")
 
 	return result, nil
 }
 
-// generateTopics uses LLM to extract main discussion topics
-func (g *Generator) generateTopics(ctx context.Context, messages []models.ChatMessage, date string) ([]string, error) {
+// recordUsage reports token usage to the usage callback if one is set
+func (g *Generator) recordUsage(ctx context.Context, chatID int64, usage models.TokenUsage) {
+	if g.usageCallback == nil || usage.TotalTokens == 0 {
+		return
+	}
+
+	g.usageCallback(ctx, &models.UsageRecord{
+		Component: models.UsageComponentSummary,
+		Model:     string(models.ModelFlash),
+		ChatID:    chatID,
+		Usage:     usage,
+	})
+}
+
+// generateTopics uses LLM to extract main discussion topics and the quote of the day as structured output
+func (g *Generator) generateTopics(ctx context.Context, messages []models.ChatMessage, date string, loc *time.Location, tr *i18n.Localizer) (*models.StructuredSummary, models.TokenUsage, error) {
+	// Build the prompt
+	prompt := g.buildSummaryPrompt(messages, date, loc, tr)
+
+	g.logger.Debug().
+		Str("date", date).
+		Int("message_count", len(messages)).
+		Int("prompt_length", len(prompt)).
+		Msg("Sending request to LLM for topic extraction")
+
+	var response topicsResponse
+	usage, err := g.generateJSON(ctx, prompt, topicsSchema(tr), &response)
+	if err != nil {
+		return nil, usage, err
+	}
+
+	structured := &models.StructuredSummary{
+		Topics: sanitizeTopics(response.Topics, messages, maxTopics),
+		Quote:  sanitizeQuote(response.Quote, messages),
+	}
+
+	g.logger.Debug().
+		Str("date", date).
+		Int("topic_count", len(structured.Topics)).
+		Msg("Received topics from LLM")
+
+	return structured, usage, nil
+}
+
+// generateText sends a prompt to the Flash model and returns the response text with token usage
+// With a schema, the model responds with JSON matching it (structured output)
+func (g *Generator) generateText(ctx context.Context, prompt string, schema *genai.Schema) (string, models.TokenUsage, error) {
+	var usage models.TokenUsage
+
 	// Create timeout context for LLM request
 	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
@@ -102,7 +179,7 @@ func (g *Generator) generateTopics(ctx context.Context, messages []models.ChatMe
 	// Get or create Gemini client
 	client, err := g.getClient(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get genai client: %w", err)
+		return "", usage, fmt.Errorf("failed to get genai client: %w", err)
 	}
 
 	// Use Flash model for cost-effectiveness
@@ -112,31 +189,35 @@ func (g *Generator) generateTopics(ctx context.Context, messages []models.ChatMe
 	model.SetTemperature(0.7)
 	model.SetTopP(0.95)
 	model.SetTopK(40)
-	model.SetMaxOutputTokens(2048)
-
-	// Build the prompt
-	prompt := g.buildSummaryPrompt(messages, date)
-
-	g.logger.Debug().
-		Str("date", date).
-		Int("message_count", len(messages)).
-		Int("prompt_length", len(prompt)).
-		Msg("Sending request to LLM for topic extraction")
+	model.SetMaxOutputTokens(4096)
+	if schema != nil {
+		model.ResponseMIMEType = "application/json"
+		model.ResponseSchema = schema
+	}
 
 	// Generate content
 	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate content: %w", err)
+		return "", usage, fmt.Errorf("failed to generate content: %w", err)
 	}
 
 	// Extract text from response
 	if resp == nil || len(resp.Candidates) == 0 {
-		return nil, fmt.Errorf("no response candidates from LLM")
+		return "", usage, fmt.Errorf("no response candidates from LLM")
+	}
+
+	if resp.UsageMetadata != nil {
+		usage = models.NewTokenUsage(
+			string(models.ModelFlash),
+			int(resp.UsageMetadata.PromptTokenCount),
+			int(resp.UsageMetadata.CandidatesTokenCount),
+			int(resp.UsageMetadata.TotalTokenCount),
+		)
 	}
 
 	candidate := resp.Candidates[0]
 	if candidate.Content == nil || len(candidate.Content.Parts) == 0 {
-		return nil, fmt.Errorf("no content parts in response")
+		return "", usage, fmt.Errorf("no content parts in response")
 	}
 
 	// Extract text from all parts
@@ -147,74 +228,28 @@ func (g *Generator) generateTopics(ctx context.Context, messages []models.ChatMe
 		}
 	}
 
-	text := responseText.String()
-
-	g.logger.Debug().
-		Str("date", date).
-		Int("response_length", len(text)).
-		Msg("Received LLM response")
-
-	// Parse topics from response
-	topics := g.parseTopics(text)
-
-	return topics, nil
+	return responseText.String(), usage, nil
 }
 
 // buildSummaryPrompt constructs the prompt for LLM
-func (g *Generator) buildSummaryPrompt(messages []models.ChatMessage, date string) string {
+func (g *Generator) buildSummaryPrompt(messages []models.ChatMessage, date string, loc *time.Location, tr *i18n.Localizer) string {
 	var sb strings.Builder
 
-	sb.WriteString("Проанализируй следующие сообщения из группового чата за день ")
-	sb.WriteString(date)
-	sb.WriteString(" и выдели 5-7 основных тем обсуждения.\n\n")
-	sb.WriteString("ВАЖНО:\n")
-	sb.WriteString("1. Каждая тема должна быть краткой (максимум 5-7 слов)\n")
-	sb.WriteString("2. Начинай каждую тему с подходящего эмодзи\n")
-	sb.WriteString("3. Выводи каждую тему на отдельной строке\n")
-	sb.WriteString("4. НЕ нумеруй темы, только эмодзи и текст\n")
-	sb.WriteString("5. Сфокусируйся на самых обсуждаемых и важных темах\n")
-	sb.WriteString("6. Если тем меньше 5, выведи только те что есть\n\n")
-
-	sb.WriteString("Сообщения:\n\n")
-
-	const maxMessagesInPrompt = 500
-	messagesToUse := messages
-	selectionStrategy := "all"
-
-	if len(messages) > maxMessagesInPrompt {
-		firstBatch := messages[:250]
-		lastBatch := messages[len(messages)-250:]
-		messagesToUse = append(firstBatch, lastBatch...)
-		selectionStrategy = "first_250_and_last_250"
-
-		sb.WriteString(fmt.Sprintf("[Показаны первые 250 и последние 250 сообщений из %d]\n\n", len(messages)))
-	}
+	sb.WriteString(tr.T("prompt.summary", date))
+	sb.WriteString(tr.T("prompt.topic_rules"))
+	sb.WriteString(tr.T("prompt.summary_rules"))
+	sb.WriteString(tr.T("prompt.quote_rule"))
+	sb.WriteString(tr.T("prompt.language") + "\n")
 
-	g.logMessageSelection(date, len(messages), selectionStrategy, messagesToUse)
+	sb.WriteString(tr.T("prompt.messages"))
 
-	for _, msg := range messagesToUse {
-		timestamp := msg.CreatedAt.Format("15:04")
-		username := msg.Username
-		if username == "" {
-			username = msg.FirstName
-		}
-		if username == "" {
-			username = fmt.Sprintf("User%d", msg.UserID)
-		}
-
-		sb.WriteString(fmt.Sprintf("[%s] %s: %s\n", timestamp, username, msg.MessageText))
-	}
-
-	sb.WriteString("\n\nТеперь выдели 5-7 основных тем в формате:\n")
-	sb.WriteString("🎮 Тема 1\n")
-	sb.WriteString("💻 Тема 2\n")
-	sb.WriteString("и так далее...\n\n")
-	sb.WriteString("Темы:")
+	g.logMessageSelection(date, loc, len(messages), "all", messages)
+	writeMessages(&sb, messages, loc)
 
 	return sb.String()
 }
 
-func (g *Generator) logMessageSelection(date string, totalMessages int, strategy string, selected []models.ChatMessage) {
+func (g *Generator) logMessageSelection(date string, loc *time.Location, totalMessages int, strategy string, selected []models.ChatMessage) {
 	if totalMessages == 0 {
 		g.logger.Info().
 			Str("date", date).
@@ -235,17 +270,12 @@ func (g *Generator) logMessageSelection(date string, totalMessages int, strategy
 		return
 	}
 
-	moscowLoc, err := time.LoadLocation("Europe/Moscow")
-	if err != nil {
-		moscowLoc = time.UTC
-	}
-
-	firstMsgMoscow := selected[0].CreatedAt.In(moscowLoc)
-	lastMsgMoscow := selected[len(selected)-1].CreatedAt.In(moscowLoc)
+	firstMsgLocal := selected[0].CreatedAt.In(loc)
+	lastMsgLocal := selected[len(selected)-1].CreatedAt.In(loc)
 
 	allMatch := true
 	for _, msg := range selected {
-		if msg.CreatedAt.In(moscowLoc).Format("2006-01-02") != date {
+		if msg.CreatedAt.In(loc).Format("2006-01-02") != date {
 			allMatch = false
 			break
 		}
@@ -256,62 +286,39 @@ func (g *Generator) logMessageSelection(date string, totalMessages int, strategy
 		Int("total_messages", totalMessages).
 		Int("selected_messages", len(selected)).
 		Str("selection_strategy", strategy).
-		Str("first_message_moscow", firstMsgMoscow.Format(time.RFC3339)).
-		Str("last_message_moscow", lastMsgMoscow.Format(time.RFC3339)).
+		Str("timezone", loc.String()).
+		Str("first_message_local", firstMsgLocal.Format(time.RFC3339)).
+		Str("last_message_local", lastMsgLocal.Format(time.RFC3339)).
 		Bool("all_messages_match_date", allMatch).
 		Msg("Message selection for summary prompt")
 }
 
-// parseTopics extracts topic lines from LLM response
-func (g *Generator) parseTopics(text string) []string {
-	lines := strings.Split(text, "\n")
-	topics := make([]string, 0, 7)
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-
-		// Skip empty lines
-		if line == "" {
-			continue
-		}
+// writeMessages writes messages as "[#id 15:04] user: text" lines
+// IDs let the model reference representative messages
+func writeMessages(sb *strings.Builder, messages []models.ChatMessage, loc *time.Location) {
+	if len(messages) == 0 {
+		return
+	}
 
-		// Skip lines that look like instructions or headers
-		if strings.HasPrefix(strings.ToLower(line), "темы:") ||
-			strings.HasPrefix(strings.ToLower(line), "основные темы") ||
-			strings.HasPrefix(strings.ToLower(line), "вот") {
-			continue
+	layout := timeLayout(messages[0].CreatedAt.In(loc), messages[len(messages)-1].CreatedAt.In(loc))
+	for _, msg := range messages {
+		timestamp := msg.CreatedAt.In(loc).Format(layout)
+		username := msg.Username
+		if username == "" {
+			username = msg.FirstName
 		}
-
-		// Line should start with emoji or be a valid topic
-		// Check if line has at least one emoji-like character (simple heuristic)
-		hasEmoji := false
-		for _, r := range line {
-			if r > 0x1F000 { // Rough check for emoji range
-				hasEmoji = true
-				break
-			}
+		if username == "" {
+			username = fmt.Sprintf("User%d", msg.UserID)
 		}
 
-		// Accept lines with emoji or lines that look like topics
-		if hasEmoji || (len(line) > 3 && len(line) < 100) {
-			// Remove any numbering (1., 2., etc.)
-			line = strings.TrimPrefix(line, "- ")
-			for i := 1; i <= 10; i++ {
-				line = strings.TrimPrefix(line, fmt.Sprintf("%d. ", i))
-				line = strings.TrimPrefix(line, fmt.Sprintf("%d) ", i))
-			}
-			line = strings.TrimSpace(line)
-
-			if line != "" && len(topics) < 7 {
-				topics = append(topics, line)
-			}
-		}
+		sb.WriteString(fmt.Sprintf("[#%d %s] %s: %s\n", msg.MessageID, timestamp, username, msg.MessageText))
 	}
+}
 
-	g.logger.Debug().
-		Int("parsed_topics", len(topics)).
-		Strs("topics", topics).
-		Msg("Parsed topics from LLM response")
-
-	return topics
+// timeLayout returns the layout of message times, with the date if the range spans several days
+func timeLayout(from, to time.Time) string {
+	if from.Format("2006-01-02") != to.Format("2006-01-02") {
+		return "02.01 15:04"
+	}
+	return "15:04"
 }
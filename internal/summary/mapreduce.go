@@ -0,0 +1,214 @@
+package summary
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/telegram-llm-bot/internal/i18n"
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+// chunkTopics represents topics of one time window of the day
+type chunkTopics struct {
+	from      time.Time
+	to        time.Time
+	count     int
+	topics    []models.SummaryTopic
+	quote     *models.SummaryQuote // Candidate for the quote of the day
+	quoteText string               // Author and text of the candidate message
+	usage     models.TokenUsage
+	err       error
+}
+
+// generateTopicsMapReduce summarizes a busy day in chunks so every message is covered
+// Map: the day is split into time windows that are summarized with Flash in parallel.
+// Reduce: topics of all windows are merged into the final topic list
+func (g *Generator) generateTopicsMapReduce(ctx context.Context, messages []models.ChatMessage, date string, loc *time.Location, tr *i18n.Localizer) (*models.StructuredSummary, models.TokenUsage, error) {
+	var usage models.TokenUsage
+
+	chunks := chunkByTimeWindows(messages, loc, g.config.SummaryChunkSize)
+	g.logMessageSelection(date, loc, len(messages), "map_reduce", messages)
+
+	g.logger.Info().
+		Str("date", date).
+		Int("message_count", len(messages)).
+		Int("chunk_count", len(chunks)).
+		Int("concurrency", g.config.SummaryMapConcurrency).
+		Msg("Summarizing day in chunks")
+
+	results := make([]chunkTopics, len(chunks))
+	slots := make(chan struct{}, g.config.SummaryMapConcurrency)
+	var wg sync.WaitGroup
+
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk []models.ChatMessage) {
+			defer wg.Done()
+
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			result := chunkTopics{
+				from:  chunk[0].CreatedAt.In(loc),
+				to:    chunk[len(chunk)-1].CreatedAt.In(loc),
+				count: len(chunk),
+			}
+
+			var response topicsResponse
+			prompt := g.buildChunkPrompt(chunk, date, loc, i+1, len(chunks), tr)
+			result.usage, result.err = g.generateJSON(ctx, prompt, topicsSchema(tr), &response)
+			result.topics = sanitizeTopics(response.Topics, chunk, maxTopics)
+			if result.quote = sanitizeQuote(response.Quote, chunk); result.quote != nil {
+				result.quoteText = quotedMessage(chunk, result.quote.MessageID)
+			}
+
+			results[i] = result
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	// A failed window is skipped, the rest of the day is still summarized
+	summarized := make([]chunkTopics, 0, len(results))
+	for i, result := range results {
+		usage.Add(result.usage)
+		if result.err != nil {
+			g.logger.Warn().
+				Err(result.err).
+				Str("date", date).
+				Int("chunk", i+1).
+				Int("message_count", result.count).
+				Msg("Failed to summarize chunk, skipping")
+			continue
+		}
+		summarized = append(summarized, result)
+	}
+
+	if len(summarized) == 0 {
+		return nil, usage, fmt.Errorf("all %d chunks failed: %w", len(results), results[0].err)
+	}
+
+	var response topicsResponse
+	prompt := g.buildReducePrompt(summarized, date, len(messages), tr)
+	reduceUsage, err := g.generateJSON(ctx, prompt, topicsSchema(tr), &response)
+	usage.Add(reduceUsage)
+	if err != nil {
+		return nil, usage, fmt.Errorf("failed to reduce chunk topics: %w", err)
+	}
+
+	structured := &models.StructuredSummary{
+		Topics: sanitizeTopics(response.Topics, messages, maxTopics),
+		Quote:  sanitizeQuote(response.Quote, messages),
+	}
+
+	g.logger.Info().
+		Str("date", date).
+		Int("chunk_count", len(chunks)).
+		Int("summarized_chunks", len(summarized)).
+		Int("topic_count", len(structured.Topics)).
+		Msg("Map-reduce summarization completed")
+
+	return structured, usage, nil
+}
+
+// quotedMessage formats the author and text of a message for the reduce prompt
+func quotedMessage(messages []models.ChatMessage, messageID int64) string {
+	for _, msg := range messages {
+		if msg.MessageID == messageID {
+			username := msg.Username
+			if username == "" {
+				username = msg.FirstName
+			}
+			return fmt.Sprintf("%s: %s", username, msg.MessageText)
+		}
+	}
+	return ""
+}
+
+// chunkByTimeWindows splits messages of a day into chunks of whole hours with at most maxSize messages
+// Consecutive hours are merged while they fit; an hour with more messages is split by count
+func chunkByTimeWindows(messages []models.ChatMessage, loc *time.Location, maxSize int) [][]models.ChatMessage {
+	// Group messages by hour, keeping the chronological order
+	var hours [][]models.ChatMessage
+	lastHour := -1
+	for _, msg := range messages {
+		hour := msg.CreatedAt.In(loc).Hour()
+		if hour != lastHour || len(hours) == 0 {
+			hours = append(hours, nil)
+			lastHour = hour
+		}
+		hours[len(hours)-1] = append(hours[len(hours)-1], msg)
+	}
+
+	var chunks [][]models.ChatMessage
+	var current []models.ChatMessage
+	for _, hour := range hours {
+		if len(current)+len(hour) > maxSize && len(current) > 0 {
+			chunks = append(chunks, current)
+			current = nil
+		}
+
+		for len(hour) > maxSize {
+			chunks = append(chunks, hour[:maxSize])
+			hour = hour[maxSize:]
+		}
+		current = append(current, hour...)
+	}
+	if len(current) > 0 {
+		chunks = append(chunks, current)
+	}
+
+	return chunks
+}
+
+// buildChunkPrompt constructs the map prompt for one time window of the day
+func (g *Generator) buildChunkPrompt(messages []models.ChatMessage, date string, loc *time.Location, index, total int, tr *i18n.Localizer) string {
+	var sb strings.Builder
+
+	from := messages[0].CreatedAt.In(loc)
+	to := messages[len(messages)-1].CreatedAt.In(loc)
+	layout := timeLayout(from, to)
+	sb.WriteString(tr.T("prompt.chunk", index, total, date, from.Format(layout), to.Format(layout)))
+	sb.WriteString(tr.T("prompt.topic_rules") + "\n")
+	sb.WriteString(tr.T("prompt.quote_rule"))
+	sb.WriteString(tr.T("prompt.language") + "\n")
+
+	sb.WriteString(tr.T("prompt.messages"))
+	writeMessages(&sb, messages, loc)
+
+	return sb.String()
+}
+
+// buildReducePrompt constructs the prompt merging topics of all time windows into the day's topics
+func (g *Generator) buildReducePrompt(chunks []chunkTopics, date string, totalMessages int, tr *i18n.Localizer) string {
+	var sb strings.Builder
+
+	sb.WriteString(tr.T("prompt.reduce", date, tr.N("unit.messages", totalMessages)))
+
+	layout := timeLayout(chunks[0].from, chunks[len(chunks)-1].to)
+	for _, chunk := range chunks {
+		sb.WriteString(tr.T("prompt.reduce_chunk", chunk.from.Format(layout), chunk.to.Format(layout), tr.N("unit.messages", chunk.count)))
+		for _, topic := range chunk.topics {
+			sb.WriteString(tr.T("prompt.reduce_topic",
+				topic.String(), topic.Description, strings.Join(topic.Participants, ", "), topic.MessageIDs))
+		}
+	}
+
+	sb.WriteString(tr.T("prompt.reduce_merge"))
+	sb.WriteString(tr.T("prompt.topic_rules"))
+	sb.WriteString(tr.T("prompt.language"))
+
+	var candidates []string
+	for _, chunk := range chunks {
+		if chunk.quote != nil {
+			candidates = append(candidates, fmt.Sprintf("[#%d] %s (%s)", chunk.quote.MessageID, chunk.quoteText, chunk.quote.Comment))
+		}
+	}
+	if len(candidates) > 0 {
+		sb.WriteString(tr.T("prompt.reduce_quotes", strings.Join(candidates, "\n")))
+	}
+
+	return sb.String()
+}
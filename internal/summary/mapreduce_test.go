@@ -0,0 +1,112 @@
+package summary
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+func TestChunkByTimeWindows(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*60*60)
+
+	tests := []struct {
+		name    string
+		hours   []int // Local hour of each message, message IDs are their positions from 1
+		maxSize int
+		want    [][]int64
+	}{
+		{
+			name:    "no messages",
+			maxSize: 3,
+			want:    nil,
+		},
+		{
+			name:    "one hour fits",
+			hours:   []int{9, 9},
+			maxSize: 3,
+			want:    [][]int64{{1, 2}},
+		},
+		{
+			name:    "consecutive hours merged while they fit",
+			hours:   []int{9, 10, 11, 12},
+			maxSize: 3,
+			want:    [][]int64{{1, 2, 3}, {4}},
+		},
+		{
+			name:    "hour not split when it fits into the next chunk",
+			hours:   []int{9, 9, 10, 10},
+			maxSize: 3,
+			want:    [][]int64{{1, 2}, {3, 4}},
+		},
+		{
+			name:    "busy hour split by count",
+			hours:   []int{8, 9, 9, 9, 9, 9, 10},
+			maxSize: 2,
+			want:    [][]int64{{1}, {2, 3}, {4, 5}, {6, 7}},
+		},
+		{
+			name:    "busy hour split exactly",
+			hours:   []int{9, 9, 9, 9},
+			maxSize: 2,
+			want:    [][]int64{{1, 2}, {3, 4}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages := make([]models.ChatMessage, len(tt.hours))
+			for i, hour := range tt.hours {
+				messages[i] = models.ChatMessage{
+					MessageID: int64(i + 1),
+					CreatedAt: time.Date(2024, 11, 20, hour, i, 0, 0, loc).UTC(),
+				}
+			}
+
+			var got [][]int64
+			for _, chunk := range chunkByTimeWindows(messages, loc, tt.maxSize) {
+				ids := make([]int64, len(chunk))
+				for i, msg := range chunk {
+					ids[i] = msg.MessageID
+				}
+				got = append(got, ids)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("chunkByTimeWindows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestChunkByTimeWindowsLocation checks that messages are grouped by hours of the chat timezone
+func TestChunkByTimeWindowsLocation(t *testing.T) {
+	// 09:50, 10:10 and 10:20 UTC are 12:20, 12:40 and 12:50 in UTC+2:30
+	messages := []models.ChatMessage{
+		{MessageID: 1, CreatedAt: time.Date(2024, 11, 20, 9, 50, 0, 0, time.UTC)},
+		{MessageID: 2, CreatedAt: time.Date(2024, 11, 20, 10, 10, 0, 0, time.UTC)},
+		{MessageID: 3, CreatedAt: time.Date(2024, 11, 20, 10, 20, 0, 0, time.UTC)},
+	}
+
+	tests := []struct {
+		name string
+		loc  *time.Location
+		want []int // Chunk sizes
+	}{
+		{name: "UTC", loc: time.UTC, want: []int{1, 2}},
+		{name: "half-hour offset", loc: time.FixedZone("UTC+2:30", 150*60), want: []int{2, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			for _, chunk := range chunkByTimeWindows(messages, tt.loc, 2) {
+				got = append(got, len(chunk))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("chunk sizes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package summary
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/telegram-llm-bot/internal/i18n"
+	"github.com/telegram-llm-bot/internal/models"
+)
+
+// Limits of structured topics
+const (
+	maxTopics                 = 7
+	maxTrendTopics            = 3
+	maxTopicParticipants      = 5
+	maxTopicMessageIDs        = 3
+	maxTopicTitleLength       = 80
+	maxTopicDescriptionLength = 300
+	maxQuoteCommentLength     = 200
+)
+
+// quoteSchema describes the quote of the day
+func quoteSchema(tr *i18n.Localizer) *genai.Schema {
+	return &genai.Schema{
+		Type:     genai.TypeObject,
+		Nullable: true,
+		Properties: map[string]*genai.Schema{
+			"message_id": {Type: genai.TypeInteger, Format: "int64", Description: tr.T("schema.quote.message_id")},
+			"comment":    {Type: genai.TypeString, Description: tr.T("schema.quote.comment")},
+		},
+		Required: []string{"message_id"},
+	}
+}
+
+// topicSchema describes one topic of structured output
+func topicSchema(tr *i18n.Localizer) *genai.Schema {
+	return &genai.Schema{
+		Type: genai.TypeObject,
+		Properties: map[string]*genai.Schema{
+			"emoji":       {Type: genai.TypeString, Description: tr.T("schema.topic.emoji")},
+			"title":       {Type: genai.TypeString, Description: tr.T("schema.topic.title")},
+			"description": {Type: genai.TypeString, Description: tr.T("schema.topic.description")},
+			"participants": {
+				Type:        genai.TypeArray,
+				Description: tr.T("schema.topic.participants"),
+				Items:       &genai.Schema{Type: genai.TypeString},
+			},
+			"message_ids": {
+				Type:        genai.TypeArray,
+				Description: tr.T("schema.topic.message_ids"),
+				Items:       &genai.Schema{Type: genai.TypeInteger, Format: "int64"},
+			},
+		},
+		Required: []string{"emoji", "title", "description"},
+	}
+}
+
+// topicsSchema describes the response with the topics and the quote of a summary
+func topicsSchema(tr *i18n.Localizer) *genai.Schema {
+	return &genai.Schema{
+		Type: genai.TypeObject,
+		Properties: map[string]*genai.Schema{
+			"topics": {Type: genai.TypeArray, Items: topicSchema(tr)},
+			"quote":  quoteSchema(tr),
+		},
+		Required: []string{"topics"},
+	}
+}
+
+// digestSchema describes the response of a digest with trends
+func digestSchema(tr *i18n.Localizer) *genai.Schema {
+	return &genai.Schema{
+		Type: genai.TypeObject,
+		Properties: map[string]*genai.Schema{
+			"topics": {Type: genai.TypeArray, Items: topicSchema(tr), Description: tr.T("schema.digest.topics")},
+			"rising": {Type: genai.TypeArray, Items: topicSchema(tr), Description: tr.T("schema.digest.rising")},
+			"fading": {Type: genai.TypeArray, Items: topicSchema(tr), Description: tr.T("schema.digest.fading")},
+		},
+		Required: []string{"topics"},
+	}
+}
+
+// topicsResponse is the structured response matching topicsSchema
+type topicsResponse struct {
+	Topics []models.SummaryTopic `json:"topics"`
+	Quote  *models.SummaryQuote  `json:"quote"`
+}
+
+// digestResponse is the structured response matching digestSchema
+type digestResponse struct {
+	Topics []models.SummaryTopic `json:"topics"`
+	Rising []models.SummaryTopic `json:"rising"`
+	Fading []models.SummaryTopic `json:"fading"`
+}
+
+// generateJSON sends a prompt with a response schema and decodes the JSON response into out
+func (g *Generator) generateJSON(ctx context.Context, prompt string, schema *genai.Schema, out interface{}) (models.TokenUsage, error) {
+	text, usage, err := g.generateText(ctx, prompt, schema)
+	if err != nil {
+		return usage, err
+	}
+
+	if err := json.Unmarshal([]byte(text), out); err != nil {
+		return usage, fmt.Errorf("failed to decode structured response: %w", err)
+	}
+
+	return usage, nil
+}
+
+// sanitizeTopics trims topics returned by the model and enforces limits
+// Message IDs are kept only if they belong to the given messages, so links never point to unrelated messages
+func sanitizeTopics(topics []models.SummaryTopic, messages []models.ChatMessage, limit int) []models.SummaryTopic {
+	known := make(map[int64]bool, len(messages))
+	for _, msg := range messages {
+		known[msg.MessageID] = true
+	}
+
+	result := make([]models.SummaryTopic, 0, min(len(topics), limit))
+	for _, topic := range topics {
+		if len(result) >= limit {
+			break
+		}
+
+		topic.Emoji = strings.TrimSpace(topic.Emoji)
+		topic.Title = truncateRunes(strings.TrimSpace(topic.Title), maxTopicTitleLength)
+		topic.Description = truncateRunes(strings.TrimSpace(topic.Description), maxTopicDescriptionLength)
+		if topic.Title == "" {
+			continue
+		}
+
+		participants := make([]string, 0, len(topic.Participants))
+		for _, participant := range topic.Participants {
+			participant = strings.TrimPrefix(strings.TrimSpace(participant), "@")
+			if participant != "" && len(participants) < maxTopicParticipants {
+				participants = append(participants, participant)
+			}
+		}
+		topic.Participants = participants
+
+		ids := make([]int64, 0, len(topic.MessageIDs))
+		for _, id := range topic.MessageIDs {
+			if known[id] && len(ids) < maxTopicMessageIDs {
+				ids = append(ids, id)
+			}
+		}
+		topic.MessageIDs = ids
+
+		result = append(result, topic)
+	}
+
+	return result
+}
+
+// sanitizeQuote drops a quote that doesn't belong to the given messages
+func sanitizeQuote(quote *models.SummaryQuote, messages []models.ChatMessage) *models.SummaryQuote {
+	if quote == nil {
+		return nil
+	}
+
+	for _, msg := range messages {
+		if msg.MessageID == quote.MessageID {
+			return &models.SummaryQuote{
+				MessageID: quote.MessageID,
+				Comment:   truncateRunes(strings.TrimSpace(quote.Comment), maxQuoteCommentLength),
+			}
+		}
+	}
+
+	return nil
+}
+
+// truncateRunes shortens text to at most limit runes
+func truncateRunes(text string, limit int) string {
+	if runes := []rune(text); len(runes) > limit {
+		return string(runes[:limit-1]) + "…"
+	}
+	return text
+}
@@ -9,7 +9,9 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/telegram-llm-bot/internal/config"
+	"github.com/telegram-llm-bot/internal/models"
 	"github.com/telegram-llm-bot/internal/scheduler"
+	"github.com/telegram-llm-bot/internal/settings"
 	"github.com/telegram-llm-bot/internal/storage"
 	"github.com/telegram-llm-bot/internal/summary"
 )
@@ -65,6 +67,12 @@ func main() {
 	}
 	logger.Info().Msg("Supabase connection successful")
 
+	// Initialize chat settings (timezone of the chat)
+	settingsService, err := settings.NewService(storageClient, cfg, logger)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to create settings service")
+	}
+
 	// Initialize summary generator
 	logger.Info().Msg("Initializing summary generator...")
 	summaryGenerator := summary.NewGenerator(cfg.GeminiAPIKey, cfg, logger)
@@ -76,23 +84,21 @@ func main() {
 
 	// Initialize scheduler (without sync job and callback for testing)
 	logger.Info().Msg("Initializing scheduler...")
-	summaryScheduler, err := scheduler.NewScheduler(
+	summaryScheduler := scheduler.NewScheduler(
 		storageClient,
 		summaryGenerator,
 		cfg,
-		func(chatID int64, summaryText string) error {
+		func(ctx context.Context, delivery *models.SummaryDelivery) (int64, error) {
 			logger.Info().
-				Int64("chat_id", chatID).
-				Str("summary", summaryText).
+				Int64("chat_id", delivery.ChatID).
+				Str("summary", delivery.Text).
 				Msg("Summary would be sent to chat (not actually sending in test mode)")
-			return nil
+			return 0, nil
 		},
 		nil, // no sync job
+		settingsService,
 		logger,
 	)
-	if err != nil {
-		logger.Fatal().Err(err).Msg("Failed to create scheduler")
-	}
 
 	// Generate summary for yesterday
 	logger.Info().